@@ -0,0 +1,324 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// EndpointPickerConfig is the configuration file read by the EPP
+// +kubebuilder:object:generate=false
+type EndpointPickerConfig struct {
+	APIVersion         string                 `json:"apiVersion"`
+	Kind               string                 `json:"kind"`
+	Plugins            []EPPPlugin            `json:"plugins"`
+	SchedulingProfiles []EPPSchedulingProfile `json:"schedulingProfiles"`
+}
+
+// EPPConfigPresets are the EndpointPickerConfig presets selected by configProfile
+var EPPConfigPresets = map[string]string{
+	"default": `apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: load-aware-scorer
+- type: prefix-cache-scorer
+  parameters:
+    hashBlockSize: 5
+    maxPrefixBlocksToMatch: 256
+    lruCapacityPerServer: 31250
+- type: kv-cache-utilization-scorer
+- type: decode-filter
+- type: max-score-picker
+- type: single-profile-handler
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: decode-filter
+  - pluginRef: max-score-picker
+  - pluginRef: load-aware-scorer
+    weight: 1
+  - pluginRef: prefix-cache-scorer
+    weight: 2
+  - pluginRef: kv-cache-utilization-scorer
+    weight: 1
+`,
+	"proxy-performance": `apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: active-request-scorer
+  parameters:
+    requestTimeout: "2m"
+- type: max-score-picker
+- type: single-profile-handler
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: max-score-picker
+  - pluginRef: active-request-scorer
+    weight: 1
+`,
+	"proxy-performance-by-backend": `apiVersion: inference.networking.x-k8s.io/v1alpha1
+kind: EndpointPickerConfig
+plugins:
+- type: active-request-scorer
+  parameters:
+    requestTimeout: "2m"
+- type: by-label-selector
+  name: by-kv-backend
+  parameters:
+    matchLabels:
+      llm-d.ai/kv-backend: nvlink
+- type: max-score-picker
+- type: single-profile-handler
+schedulingProfiles:
+- name: default
+  plugins:
+  - pluginRef: by-kv-backend
+  - pluginRef: max-score-picker
+  - pluginRef: active-request-scorer
+    weight: 1
+`,
+}
+
+// KnownEPPPluginTypes lists the plugin types registered by the supported EPP
+// images (gateway-api-inference-extension and llm-d-inference-scheduler).
+// Keep it in sync when bumping the default EPP image.
+var KnownEPPPluginTypes = map[string]bool{
+	// Profile handlers
+	"single-profile-handler": true,
+	"pd-profile-handler":     true,
+	// Filters
+	"decode-filter":         true,
+	"prefill-filter":        true,
+	"by-label":              true,
+	"by-label-selector":     true,
+	"least-queue-filter":    true,
+	"least-kv-cache-filter": true,
+	"low-queue-filter":      true,
+	"lora-affinity-filter":  true,
+	"decision-tree-filter":  true,
+	// Scorers
+	"load-aware-scorer":           true,
+	"active-request-scorer":       true,
+	"prefix-cache-scorer":         true,
+	"precise-prefix-cache-scorer": true,
+	"kv-cache-utilization-scorer": true,
+	"queue-scorer":                true,
+	"lora-affinity-scorer":        true,
+	"session-affinity-scorer":     true,
+	"no-hit-lru-scorer":           true,
+	// Pickers
+	"max-score-picker":       true,
+	"random-picker":          true,
+	"weighted-random-picker": true,
+	// Request control
+	"prefill-header-handler":  true,
+	"prefix-based-pd-decider": true,
+}
+
+// BuildEndpointPickerConfig merges plugins and profiles into the named preset
+// and validates the result. Errors are reported against fldPath.
+func BuildEndpointPickerConfig(preset string, plugins []EPPPlugin, profiles []EPPSchedulingProfile, fldPath *field.Path) (*EndpointPickerConfig, field.ErrorList) {
+	var allErrs field.ErrorList
+	if preset == "" {
+		preset = "default"
+	}
+	raw, ok := EPPConfigPresets[preset]
+	if !ok {
+		return nil, field.ErrorList{field.NotSupported(fldPath.Child("configProfile"), preset, sortedKeys(EPPConfigPresets))}
+	}
+	config := &EndpointPickerConfig{}
+	if err := yaml.Unmarshal([]byte(raw), config); err != nil {
+		return nil, field.ErrorList{field.InternalError(fldPath.Child("configProfile"), fmt.Errorf("invalid preset %q: %w", preset, err))}
+	}
+
+	pluginsPath := fldPath.Child("plugins")
+	for i, plugin := range plugins {
+		name := pluginName(plugin)
+		if name == "" {
+			allErrs = append(allErrs, field.Required(pluginsPath.Index(i).Child("type"), "type or name is required"))
+			continue
+		}
+		existing := -1
+		for j := range config.Plugins {
+			if pluginName(config.Plugins[j]) == name {
+				existing = j
+				break
+			}
+		}
+		if existing < 0 {
+			if plugin.Type == "" {
+				allErrs = append(allErrs, field.Required(pluginsPath.Index(i).Child("type"), fmt.Sprintf("plugin %q is not part of the %q preset", name, preset)))
+				continue
+			}
+			config.Plugins = append(config.Plugins, *plugin.DeepCopy())
+			continue
+		}
+		base := &config.Plugins[existing]
+		if plugin.Type != "" {
+			base.Type = plugin.Type
+		}
+		merged, err := mergeParameters(base.Parameters, plugin.Parameters)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(pluginsPath.Index(i).Child("parameters"), string(plugin.Parameters.Raw), err.Error()))
+			continue
+		}
+		base.Parameters = merged
+	}
+
+	for _, profile := range profiles {
+		existing := -1
+		for j := range config.SchedulingProfiles {
+			if config.SchedulingProfiles[j].Name == profile.Name {
+				existing = j
+				break
+			}
+		}
+		if existing < 0 {
+			config.SchedulingProfiles = append(config.SchedulingProfiles, *profile.DeepCopy())
+			continue
+		}
+		base := &config.SchedulingProfiles[existing]
+		for _, ref := range profile.Plugins {
+			found := false
+			for k := range base.Plugins {
+				if base.Plugins[k].PluginRef == ref.PluginRef {
+					if ref.Weight != nil {
+						weight := *ref.Weight
+						base.Plugins[k].Weight = &weight
+					}
+					found = true
+					break
+				}
+			}
+			if !found {
+				base.Plugins = append(base.Plugins, *ref.DeepCopy())
+			}
+		}
+	}
+
+	allErrs = append(allErrs, validateEndpointPickerConfig(config, fldPath)...)
+	if len(allErrs) > 0 {
+		return nil, allErrs
+	}
+	return config, nil
+}
+
+// RenderEndpointPickerConfig builds the EndpointPickerConfig and returns it as YAML
+func RenderEndpointPickerConfig(preset string, plugins []EPPPlugin, profiles []EPPSchedulingProfile) (string, error) {
+	config, errs := BuildEndpointPickerConfig(preset, plugins, profiles, field.NewPath("spec", "epp"))
+	if len(errs) > 0 {
+		return "", errs.ToAggregate()
+	}
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func validateEndpointPickerConfig(config *EndpointPickerConfig, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	pluginsPath := fldPath.Child("plugins")
+	profilesPath := fldPath.Child("schedulingProfiles")
+
+	names := map[string]bool{}
+	handlers := 0
+	for _, plugin := range config.Plugins {
+		name := pluginName(plugin)
+		if !KnownEPPPluginTypes[plugin.Type] {
+			allErrs = append(allErrs, field.NotSupported(pluginsPath.Key(name).Child("type"), plugin.Type, sortedKeys(KnownEPPPluginTypes)))
+		}
+		if names[name] {
+			allErrs = append(allErrs, field.Duplicate(pluginsPath.Key(name).Child("name"), name))
+		}
+		names[name] = true
+		if strings.HasSuffix(plugin.Type, "-profile-handler") {
+			handlers++
+		}
+	}
+	if handlers != 1 {
+		allErrs = append(allErrs, field.Invalid(pluginsPath, handlers, "exactly one profile handler plugin is required"))
+	}
+
+	if len(config.SchedulingProfiles) == 0 {
+		allErrs = append(allErrs, field.Required(profilesPath, "at least one scheduling profile is required"))
+	}
+	profileNames := map[string]bool{}
+	for _, profile := range config.SchedulingProfiles {
+		profilePath := profilesPath.Key(profile.Name)
+		if profile.Name == "" {
+			allErrs = append(allErrs, field.Required(profilePath.Child("name"), ""))
+		}
+		if profileNames[profile.Name] {
+			allErrs = append(allErrs, field.Duplicate(profilePath.Child("name"), profile.Name))
+		}
+		profileNames[profile.Name] = true
+		for _, ref := range profile.Plugins {
+			refPath := profilePath.Child("plugins").Key(ref.PluginRef)
+			if !names[ref.PluginRef] {
+				allErrs = append(allErrs, field.NotFound(refPath.Child("pluginRef"), ref.PluginRef))
+			}
+			if ref.Weight != nil && *ref.Weight < 0 {
+				allErrs = append(allErrs, field.Invalid(refPath.Child("weight"), *ref.Weight, "must be non-negative"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func pluginName(plugin EPPPlugin) string {
+	if plugin.Name != "" {
+		return plugin.Name
+	}
+	return plugin.Type
+}
+
+// mergeParameters overlays override onto base, recursing into nested objects
+func mergeParameters(base, override *runtime.RawExtension) (*runtime.RawExtension, error) {
+	if override == nil || len(override.Raw) == 0 {
+		return base, nil
+	}
+	var overrideValue map[string]interface{}
+	if err := json.Unmarshal(override.Raw, &overrideValue); err != nil {
+		return nil, fmt.Errorf("parameters must be an object: %w", err)
+	}
+	baseValue := map[string]interface{}{}
+	if base != nil && len(base.Raw) > 0 {
+		if err := json.Unmarshal(base.Raw, &baseValue); err != nil {
+			return nil, err
+		}
+	}
+	merged, err := json.Marshal(mergeObjects(baseValue, overrideValue))
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: merged}, nil
+}
+
+func mergeObjects(base, override map[string]interface{}) map[string]interface{} {
+	for key, value := range override {
+		baseChild, baseIsObject := base[key].(map[string]interface{})
+		overrideChild, overrideIsObject := value.(map[string]interface{})
+		if baseIsObject && overrideIsObject {
+			base[key] = mergeObjects(baseChild, overrideChild)
+			continue
+		}
+		base[key] = value
+	}
+	return base
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
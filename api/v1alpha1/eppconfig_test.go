@@ -0,0 +1,134 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+func TestEPPConfigPresetsAreValid(t *testing.T) {
+	for name := range EPPConfigPresets {
+		if _, errs := BuildEndpointPickerConfig(name, nil, nil, field.NewPath("spec", "epp")); len(errs) > 0 {
+			t.Errorf("preset %q is invalid: %v", name, errs.ToAggregate())
+		}
+	}
+}
+
+func TestBuildEndpointPickerConfigOverrides(t *testing.T) {
+	weight := int32(5)
+	plugins := []EPPPlugin{
+		{Type: "prefix-cache-scorer", Parameters: &runtime.RawExtension{Raw: []byte(`{"hashBlockSize":64}`)}},
+		{Type: "by-label-selector", Name: "by-gpu", Parameters: &runtime.RawExtension{Raw: []byte(`{"matchLabels":{"gpu":"h100"}}`)}},
+	}
+	profiles := []EPPSchedulingProfile{{
+		Name: "default",
+		Plugins: []EPPProfilePlugin{
+			{PluginRef: "prefix-cache-scorer", Weight: &weight},
+			{PluginRef: "by-gpu"},
+		},
+	}}
+
+	config, errs := BuildEndpointPickerConfig("default", plugins, profiles, field.NewPath("spec", "epp"))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs.ToAggregate())
+	}
+
+	var prefix *EPPPlugin
+	for i := range config.Plugins {
+		if config.Plugins[i].Type == "prefix-cache-scorer" {
+			prefix = &config.Plugins[i]
+		}
+	}
+	if prefix == nil {
+		t.Fatalf("prefix-cache-scorer missing from %+v", config.Plugins)
+	}
+	params := map[string]interface{}{}
+	if err := yaml.Unmarshal(prefix.Parameters.Raw, &params); err != nil {
+		t.Fatalf("unmarshal parameters: %v", err)
+	}
+	if params["hashBlockSize"] != float64(64) {
+		t.Errorf("hashBlockSize = %v, want the override 64", params["hashBlockSize"])
+	}
+	if params["lruCapacityPerServer"] != float64(31250) {
+		t.Errorf("lruCapacityPerServer = %v, want the preset value 31250", params["lruCapacityPerServer"])
+	}
+
+	refs := config.SchedulingProfiles[0].Plugins
+	if got := len(refs); got != 6 {
+		t.Fatalf("default profile has %d plugins, want 6", got)
+	}
+	for _, ref := range refs {
+		if ref.PluginRef == "prefix-cache-scorer" && (ref.Weight == nil || *ref.Weight != 5) {
+			t.Errorf("prefix-cache-scorer weight = %v, want 5", ref.Weight)
+		}
+	}
+}
+
+func TestBuildEndpointPickerConfigValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		preset   string
+		plugins  []EPPPlugin
+		profiles []EPPSchedulingProfile
+		wantErr  string
+	}{
+		{
+			name:    "unknown plugin type",
+			plugins: []EPPPlugin{{Type: "made-up-scorer"}},
+			wantErr: "made-up-scorer",
+		},
+		{
+			name:    "new plugin without type",
+			plugins: []EPPPlugin{{Name: "mystery"}},
+			wantErr: "spec.epp.plugins[0].type",
+		},
+		{
+			name:     "dangling pluginRef",
+			profiles: []EPPSchedulingProfile{{Name: "default", Plugins: []EPPProfilePlugin{{PluginRef: "missing"}}}},
+			wantErr:  "missing",
+		},
+		{
+			name:    "second profile handler",
+			plugins: []EPPPlugin{{Type: "pd-profile-handler"}},
+			wantErr: "exactly one profile handler",
+		},
+		{
+			name:    "unknown preset",
+			preset:  "nope",
+			wantErr: "spec.epp.configProfile",
+		},
+		{
+			name:    "parameters must be an object",
+			plugins: []EPPPlugin{{Type: "prefix-cache-scorer", Parameters: &runtime.RawExtension{Raw: []byte(`[1]`)}}},
+			wantErr: "parameters must be an object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := BuildEndpointPickerConfig(tt.preset, tt.plugins, tt.profiles, field.NewPath("spec", "epp"))
+			if len(errs) == 0 {
+				t.Fatalf("expected an error mentioning %q", tt.wantErr)
+			}
+			if err := errs.ToAggregate(); !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderEndpointPickerConfig(t *testing.T) {
+	rendered, err := RenderEndpointPickerConfig("proxy-performance", nil, nil)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	config := &EndpointPickerConfig{}
+	if err := yaml.Unmarshal([]byte(rendered), config); err != nil {
+		t.Fatalf("rendered config does not parse: %v", err)
+	}
+	if config.Kind != "EndpointPickerConfig" || len(config.Plugins) != 3 {
+		t.Errorf("unexpected rendered config:\n%s", rendered)
+	}
+}
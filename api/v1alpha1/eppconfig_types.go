@@ -0,0 +1,59 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// EPP restart policies
+const (
+	// EPPRestartOnConfigChange rolls the EPP pods whenever the rendered plugin config changes
+	EPPRestartOnConfigChange = "OnConfigChange"
+	// EPPRestartManual only rolls the EPP pods through RestartEPPAnnotation
+	EPPRestartManual = "Manual"
+)
+
+// RestartEPPAnnotation restarts the EPP pods of a SchedulerInstall or
+// SimulatorDeployment whenever its value changes, e.g. to clear stale indexer
+// state: kubectl annotate schedinst <name> sim.llm-d.io/restart-epp="$(date +%s)" --overwrite
+const RestartEPPAnnotation = "sim.llm-d.io/restart-epp"
+
+// EPPPlugin configures one plugin instance of the EndpointPickerConfig.
+// Plugins are merged into the selected preset by name: a plugin whose name
+// matches a preset plugin overrides its parameters key by key, any other
+// plugin is appended.
+type EPPPlugin struct {
+	// Type is the registered plugin type, e.g. prefix-cache-scorer
+	// Required for plugins that are not part of the preset
+	Type string `json:"type,omitempty"`
+
+	// Name of the plugin instance, referenced by scheduling profiles
+	// Defaults to type
+	Name string `json:"name,omitempty"`
+
+	// Parameters passed to the plugin
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
+}
+
+// EPPSchedulingProfile configures one scheduling profile of the EndpointPickerConfig.
+// Profiles are merged into the selected preset by name, and their plugin
+// references by pluginRef.
+type EPPSchedulingProfile struct {
+	// Name of the scheduling profile
+	Name string `json:"name"`
+
+	// Plugins run by this profile
+	Plugins []EPPProfilePlugin `json:"plugins,omitempty"`
+}
+
+// EPPProfilePlugin references a plugin from a scheduling profile
+type EPPProfilePlugin struct {
+	// PluginRef is the name of the referenced plugin
+	PluginRef string `json:"pluginRef"`
+
+	// Weight of the plugin when it is a scorer
+	// +kubebuilder:validation:Minimum=0
+	Weight *int32 `json:"weight,omitempty"`
+}
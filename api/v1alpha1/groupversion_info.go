@@ -1,4 +1,9 @@
 // Package v1alpha1 contains API Schema definitions for the sim v1alpha1 API group
+//
+// The Default method of each kind is the single source of its defaults: the
+// webhook persists them, and the controller applies them in memory when the
+// webhook is not installed. Validate expects a defaulted spec and returns an
+// Invalid error listing every problem.
 // +kubebuilder:object:generate=true
 // +groupName=sim.llm-d.io
 package v1alpha1
@@ -0,0 +1,353 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LoadTestSpec defines the desired state of LoadTest. The spec is immutable:
+// a LoadTest runs once, create a new one to run again.
+type LoadTestSpec struct {
+	// Target is the gateway the requests are sent to
+	Target LoadTestTarget `json:"target"`
+
+	// API selects the OpenAI endpoint: completions (/v1/completions) or chat
+	// (/v1/chat/completions)
+	// +kubebuilder:validation:Enum=completions;chat
+	// +kubebuilder:default="completions"
+	API string `json:"api,omitempty"`
+
+	// Model is the model name sent with every request
+	// +kubebuilder:default="random"
+	Model string `json:"model,omitempty"`
+
+	// RequestsPerSecond is the rate requests are started at; 0 sends them as
+	// fast as the concurrency allows. Not used with phases.
+	// +kubebuilder:validation:Minimum=0
+	RequestsPerSecond int32 `json:"requestsPerSecond,omitempty"`
+
+	// Concurrency is the maximum number of requests in flight
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	Concurrency int32 `json:"concurrency,omitempty"`
+
+	// Duration is how long requests are started for, 60s by default. Not
+	// used with phases.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Phases shape the request rate over time. They run in order and replace
+	// requestsPerSecond and duration; the results are reported per phase too.
+	Phases []LoadPhase `json:"phases,omitempty"`
+
+	// RequestTimeout bounds a single request
+	// +kubebuilder:default="30s"
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// MaxTokens is the max_tokens of every request
+	// +kubebuilder:default=16
+	// +kubebuilder:validation:Minimum=1
+	MaxTokens int32 `json:"maxTokens,omitempty"`
+
+	// PromptLength is the distribution of prompt lengths, shared prefix included
+	PromptLength *PromptLengthDistribution `json:"promptLength,omitempty"`
+
+	// SharedPrefix starts a share of the prompts with a common prefix, which
+	// exercises prefix-cache aware scoring
+	SharedPrefix *SharedPrefixConfig `json:"sharedPrefix,omitempty"`
+
+	// Image is the load generator image
+	// +kubebuilder:default="docker.io/library/llm-d-sim-loadgen:local"
+	Image string `json:"image,omitempty"`
+
+	// Resources defines the resource requirements of the load generator pod
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// LoadTestTarget selects the gateway of a LoadTest; exactly one field is set
+type LoadTestTarget struct {
+	// SchedulerInstall sends the requests to the Gateway of the named
+	// SchedulerInstall in the namespace of the LoadTest
+	SchedulerInstall string `json:"schedulerInstall,omitempty"`
+
+	// SimulatorDeployment sends the requests to the inference gateway of the
+	// named SimulatorDeployment in the namespace of the LoadTest
+	SimulatorDeployment string `json:"simulatorDeployment,omitempty"`
+
+	// URL sends the requests to this base URL, e.g. http://gateway.ns.svc:80
+	URL string `json:"url,omitempty"`
+}
+
+// Load phase shapes
+const (
+	LoadShapeConstant   = "Constant"
+	LoadShapeRamp       = "Ramp"
+	LoadShapeStep       = "Step"
+	LoadShapeSinusoidal = "Sinusoidal"
+	LoadShapePoisson    = "Poisson"
+	LoadShapeTrace      = "Trace"
+)
+
+// LoadPhase is a period of the load test with its own arrival rate shape
+type LoadPhase struct {
+	// Name identifies the phase in the results
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Shape of the arrival rate: Constant (requestsPerSecond, 0 is as fast as
+	// the concurrency allows), Ramp (linear from from to to), Step (from from
+	// to to in steps equal steps), Sinusoidal (requestsPerSecond plus or minus
+	// amplitude over period), Poisson (random arrivals at requestsPerSecond,
+	// each starting burstSize requests) or Trace (replays recorded arrival
+	// times)
+	// +kubebuilder:validation:Enum=Constant;Ramp;Step;Sinusoidal;Poisson;Trace
+	// +kubebuilder:default="Constant"
+	Shape string `json:"shape,omitempty"`
+
+	// Duration of the phase
+	Duration metav1.Duration `json:"duration"`
+
+	// RequestsPerSecond is the rate of Constant, the mean rate of Poisson and
+	// the baseline of Sinusoidal
+	// +kubebuilder:validation:Minimum=0
+	RequestsPerSecond int32 `json:"requestsPerSecond,omitempty"`
+
+	// From is the requests per second a Ramp or Step starts at
+	// +kubebuilder:validation:Minimum=0
+	From int32 `json:"from,omitempty"`
+
+	// To is the requests per second a Ramp or Step ends at
+	// +kubebuilder:validation:Minimum=0
+	To int32 `json:"to,omitempty"`
+
+	// Steps is the number of equal steps of a Step phase, from and to included
+	// +kubebuilder:validation:Minimum=2
+	Steps int32 `json:"steps,omitempty"`
+
+	// Amplitude is how far a Sinusoidal rate swings around requestsPerSecond
+	// +kubebuilder:validation:Minimum=0
+	Amplitude int32 `json:"amplitude,omitempty"`
+
+	// Period of a Sinusoidal phase, the phase duration by default; a diurnal
+	// cycle compressed into the phase
+	Period *metav1.Duration `json:"period,omitempty"`
+
+	// BurstSize is the number of requests started together at every Poisson
+	// arrival
+	// +kubebuilder:validation:Minimum=1
+	BurstSize int32 `json:"burstSize,omitempty"`
+
+	// Trace selects the ConfigMap key holding the arrival times of a Trace
+	// phase: one offset in seconds from the phase start per line, lines
+	// starting with # are ignored. Arrivals after the phase duration are
+	// dropped.
+	Trace *corev1.ConfigMapKeySelector `json:"trace,omitempty"`
+}
+
+// PromptLengthDistribution draws the length of each prompt. One token is one
+// word of the generated prompt.
+type PromptLengthDistribution struct {
+	// Distribution is Fixed (always mean), Uniform (between min and max) or
+	// Normal (around mean with stdDev, clamped to min and max)
+	// +kubebuilder:validation:Enum=Fixed;Uniform;Normal
+	// +kubebuilder:default="Fixed"
+	Distribution string `json:"distribution,omitempty"`
+
+	// Mean prompt length in tokens
+	// +kubebuilder:default=128
+	// +kubebuilder:validation:Minimum=1
+	Mean int32 `json:"mean,omitempty"`
+
+	// Min prompt length in tokens
+	// +kubebuilder:validation:Minimum=1
+	Min int32 `json:"min,omitempty"`
+
+	// Max prompt length in tokens
+	// +kubebuilder:validation:Minimum=1
+	Max int32 `json:"max,omitempty"`
+
+	// StdDev of a Normal distribution in tokens
+	// +kubebuilder:validation:Minimum=0
+	StdDev int32 `json:"stdDev,omitempty"`
+}
+
+// Prompt length distributions
+const (
+	PromptLengthFixed   = "Fixed"
+	PromptLengthUniform = "Uniform"
+	PromptLengthNormal  = "Normal"
+)
+
+// SharedPrefixConfig starts a share of the prompts with one of a few prefixes
+type SharedPrefixConfig struct {
+	// Percent of the prompts that start with a shared prefix
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// Length of the prefix in tokens; prompts shorter than the prefix are
+	// extended to it
+	// +kubebuilder:default=64
+	// +kubebuilder:validation:Minimum=1
+	Length int32 `json:"length,omitempty"`
+
+	// Groups is the number of distinct prefixes, picked at random
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	Groups int32 `json:"groups,omitempty"`
+}
+
+// LoadTest phases
+const (
+	// LoadTestPending means the target is being resolved
+	LoadTestPending = "Pending"
+	// LoadTestRunning means the load generator Job is running
+	LoadTestRunning = "Running"
+	// LoadTestSucceeded means the Job finished and the results are reported
+	LoadTestSucceeded = "Succeeded"
+	// LoadTestFailed means the Job failed
+	LoadTestFailed = "Failed"
+)
+
+// LoadTestStatus defines the observed state of LoadTest
+type LoadTestStatus struct {
+	// Phase is Pending, Running, Succeeded or Failed
+	Phase string `json:"phase,omitempty"`
+
+	// TargetURL is the base URL the requests are sent to
+	TargetURL string `json:"targetURL,omitempty"`
+
+	// JobName is the load generator Job
+	JobName string `json:"jobName,omitempty"`
+
+	// StartTime is when the load generator started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the load generator finished
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Results are reported by the load generator when it finishes
+	Results *LoadTestResults `json:"results,omitempty"`
+
+	// Conditions represent the latest available observations: TargetResolved
+	// and Complete
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// LoadTestResults summarizes the requests of a LoadTest
+type LoadTestResults struct {
+	// Requests is the number of requests sent
+	Requests int64 `json:"requests"`
+
+	// Succeeded is the number of requests answered with a 2xx status
+	Succeeded int64 `json:"succeeded"`
+
+	// Errors is the number of failed requests
+	Errors int64 `json:"errors"`
+
+	// ErrorsByReason counts the failed requests by HTTP status code, or by
+	// timeout and connection for requests without a response
+	ErrorsByReason map[string]int64 `json:"errorsByReason,omitempty"`
+
+	// Throughput is the number of succeeded requests per second, e.g. "12.50"
+	Throughput string `json:"throughput,omitempty"`
+
+	// TimeToFirstToken of the succeeded requests
+	TimeToFirstToken *LatencyPercentiles `json:"timeToFirstToken,omitempty"`
+
+	// Latency of the succeeded requests, until the last token
+	Latency *LatencyPercentiles `json:"latency,omitempty"`
+
+	// Routing counts the succeeded requests by the pod that served them, as
+	// reported in the x-inference-pod response header
+	Routing map[string]int64 `json:"routing,omitempty"`
+
+	// Phases reports the requests started in each phase of spec.phases
+	Phases []LoadPhaseResults `json:"phases,omitempty"`
+
+	// Truncated is set when phase details, errors by reason or routing were
+	// left out to fit the termination message; the Job logs hold the full results
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// LoadPhaseResults summarizes the requests started during one load phase
+type LoadPhaseResults struct {
+	// Name of the phase
+	Name string `json:"name"`
+
+	// Shape of the phase
+	Shape string `json:"shape"`
+
+	// StartTime is when the phase started, to correlate with EPP logs and metrics
+	StartTime metav1.Time `json:"startTime"`
+
+	// Duration is how long the phase ran
+	Duration metav1.Duration `json:"duration"`
+
+	// OfferedRate is the number of requests started per second, e.g. "12.50"
+	OfferedRate string `json:"offeredRate,omitempty"`
+
+	// Requests is the number of requests started in the phase
+	Requests int64 `json:"requests"`
+
+	// Succeeded is the number of requests answered with a 2xx status
+	Succeeded int64 `json:"succeeded"`
+
+	// Errors is the number of failed requests
+	Errors int64 `json:"errors"`
+
+	// ErrorsByReason counts the failed requests like LoadTestResults
+	ErrorsByReason map[string]int64 `json:"errorsByReason,omitempty"`
+
+	// Throughput is the number of succeeded requests per second
+	Throughput string `json:"throughput,omitempty"`
+
+	// TimeToFirstToken of the succeeded requests
+	TimeToFirstToken *LatencyPercentiles `json:"timeToFirstToken,omitempty"`
+
+	// Latency of the succeeded requests, until the last token
+	Latency *LatencyPercentiles `json:"latency,omitempty"`
+
+	// Routing counts the succeeded requests by the pod that served them
+	Routing map[string]int64 `json:"routing,omitempty"`
+}
+
+// LatencyPercentiles summarizes a latency distribution
+type LatencyPercentiles struct {
+	P50 metav1.Duration `json:"p50"`
+	P90 metav1.Duration `json:"p90"`
+	P99 metav1.Duration `json:"p99"`
+	Max metav1.Duration `json:"max"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=lt
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Requests",type=integer,JSONPath=`.status.results.requests`
+// +kubebuilder:printcolumn:name="Errors",type=integer,JSONPath=`.status.results.errors`
+// +kubebuilder:printcolumn:name="TTFT-P99",type=string,JSONPath=`.status.results.timeToFirstToken.p99`
+// +kubebuilder:printcolumn:name="Latency-P99",type=string,JSONPath=`.status.results.latency.p99`
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.status.targetURL`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// LoadTest is the Schema for the loadtests API
+type LoadTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LoadTestSpec   `json:"spec,omitempty"`
+	Status LoadTestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LoadTestList contains a list of LoadTest
+type LoadTestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LoadTest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LoadTest{}, &LoadTestList{})
+}
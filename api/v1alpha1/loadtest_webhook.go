@@ -0,0 +1,327 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaultLoadGeneratorImage is the default spec.image of a LoadTest; the
+// +kubebuilder:default marker repeats it, see TestLoadTestCRDImageDefault
+const DefaultLoadGeneratorImage = "docker.io/library/llm-d-sim-loadgen:local"
+
+// SetupWebhookWithManager registers the LoadTest defaulting and validating webhooks
+func (r *LoadTest) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&loadTestWebhook{}).
+		WithValidator(&loadTestWebhook{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-sim-llm-d-io-v1alpha1-loadtest,mutating=true,failurePolicy=fail,sideEffects=None,groups=sim.llm-d.io,resources=loadtests,verbs=create;update,versions=v1alpha1,name=mloadtest.sim.llm-d.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-sim-llm-d-io-v1alpha1-loadtest,mutating=false,failurePolicy=fail,sideEffects=None,groups=sim.llm-d.io,resources=loadtests,verbs=create;update,versions=v1alpha1,name=vloadtest.sim.llm-d.io,admissionReviewVersions=v1
+
+// loadTestWebhook adapts LoadTest.Default and Validate to admission
+type loadTestWebhook struct{}
+
+var _ webhook.CustomDefaulter = &loadTestWebhook{}
+var _ webhook.CustomValidator = &loadTestWebhook{}
+
+func (w *loadTestWebhook) Default(_ context.Context, obj runtime.Object) error {
+	loadTest, ok := obj.(*LoadTest)
+	if !ok {
+		return fmt.Errorf("expected a LoadTest but got %T", obj)
+	}
+	loadTest.Default()
+	return nil
+}
+
+func (w *loadTestWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	loadTest, ok := obj.(*LoadTest)
+	if !ok {
+		return nil, fmt.Errorf("expected a LoadTest but got %T", obj)
+	}
+	return nil, loadTest.Validate()
+}
+
+func (w *loadTestWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	loadTest, ok := newObj.(*LoadTest)
+	if !ok {
+		return nil, fmt.Errorf("expected a LoadTest but got %T", newObj)
+	}
+	old, ok := oldObj.(*LoadTest)
+	if !ok {
+		return nil, fmt.Errorf("expected a LoadTest but got %T", oldObj)
+	}
+	// The results describe the spec the Job ran with
+	if !equality.Semantic.DeepEqual(old.Spec, loadTest.Spec) {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("LoadTest").GroupKind(), loadTest.Name,
+			field.ErrorList{field.Forbidden(field.NewPath("spec"), "is immutable; create a new LoadTest to run again")})
+	}
+	return nil, loadTest.Validate()
+}
+
+func (w *loadTestWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// Default fills the image, request shape, load and phase timing of a LoadTest
+func (r *LoadTest) Default() {
+	spec := &r.Spec
+	if spec.API == "" {
+		spec.API = "completions"
+	}
+	if spec.Model == "" {
+		spec.Model = "random"
+	}
+	if spec.Concurrency == 0 {
+		spec.Concurrency = 1
+	}
+	// Phases carry their own durations
+	if spec.Duration == nil && len(spec.Phases) == 0 {
+		spec.Duration = &metav1.Duration{Duration: time.Minute}
+	}
+	for i := range spec.Phases {
+		phase := &spec.Phases[i]
+		if phase.Shape == "" {
+			phase.Shape = LoadShapeConstant
+		}
+		switch phase.Shape {
+		case LoadShapeStep:
+			if phase.Steps == 0 {
+				phase.Steps = 4
+			}
+		case LoadShapeSinusoidal:
+			if phase.Period == nil {
+				period := phase.Duration
+				phase.Period = &period
+			}
+		case LoadShapePoisson:
+			if phase.BurstSize == 0 {
+				phase.BurstSize = 1
+			}
+		}
+	}
+	if spec.RequestTimeout == nil {
+		spec.RequestTimeout = &metav1.Duration{Duration: 30 * time.Second}
+	}
+	if spec.MaxTokens == 0 {
+		spec.MaxTokens = 16
+	}
+	if spec.PromptLength == nil {
+		spec.PromptLength = &PromptLengthDistribution{}
+	}
+	prompt := spec.PromptLength
+	if prompt.Distribution == "" {
+		prompt.Distribution = PromptLengthFixed
+	}
+	if prompt.Mean == 0 {
+		prompt.Mean = 128
+	}
+	if prompt.Min == 0 {
+		prompt.Min = 1
+	}
+	if prompt.Max == 0 {
+		prompt.Max = 2 * prompt.Mean
+	}
+	if prefix := spec.SharedPrefix; prefix != nil {
+		if prefix.Length == 0 {
+			prefix.Length = 64
+		}
+		if prefix.Groups == 0 {
+			prefix.Groups = 1
+		}
+	}
+	if spec.Image == "" {
+		spec.Image = DefaultLoadGeneratorImage
+	}
+}
+
+// Validate checks the target, request shape and phases of a LoadTest
+func (r *LoadTest) Validate() error {
+	var allErrs field.ErrorList
+	spec := &r.Spec
+	specPath := field.NewPath("spec")
+
+	// The Job is named after the LoadTest and its pods carry the name in the job-name label
+	for _, msg := range validation.IsDNS1123Label(r.Name) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), r.Name, msg))
+	}
+
+	targetPath := specPath.Child("target")
+	targets := 0
+	if name := spec.Target.SchedulerInstall; name != "" {
+		targets++
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			allErrs = append(allErrs, field.Invalid(targetPath.Child("schedulerInstall"), name, msg))
+		}
+	}
+	if name := spec.Target.SimulatorDeployment; name != "" {
+		targets++
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			allErrs = append(allErrs, field.Invalid(targetPath.Child("simulatorDeployment"), name, msg))
+		}
+	}
+	if raw := spec.Target.URL; raw != "" {
+		targets++
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(targetPath.Child("url"), raw, "must be an absolute http or https URL"))
+		}
+	}
+	if targets != 1 {
+		allErrs = append(allErrs, field.Invalid(targetPath, spec.Target, "exactly one of schedulerInstall, simulatorDeployment and url must be set"))
+	}
+
+	switch spec.API {
+	case "completions", "chat":
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("api"), spec.API, []string{"completions", "chat"}))
+	}
+	if spec.RequestsPerSecond < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("requestsPerSecond"), spec.RequestsPerSecond, "must not be negative"))
+	}
+	if spec.Concurrency < 1 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("concurrency"), spec.Concurrency, "must be at least 1"))
+	}
+	if len(spec.Phases) == 0 {
+		if spec.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("duration"), spec.Duration.Duration.String(), "must be positive"))
+		}
+	} else {
+		if spec.RequestsPerSecond != 0 {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("requestsPerSecond"), "must not be set with phases; set it per phase"))
+		}
+		if spec.Duration != nil {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("duration"), "must not be set with phases; set it per phase"))
+		}
+		allErrs = append(allErrs, validateLoadPhases(spec.Phases, specPath.Child("phases"))...)
+	}
+	if spec.RequestTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("requestTimeout"), spec.RequestTimeout.Duration.String(), "must be positive"))
+	}
+	if spec.MaxTokens < 1 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("maxTokens"), spec.MaxTokens, "must be at least 1"))
+	}
+
+	prompt, promptPath := spec.PromptLength, specPath.Child("promptLength")
+	switch prompt.Distribution {
+	case PromptLengthFixed, PromptLengthUniform, PromptLengthNormal:
+	default:
+		allErrs = append(allErrs, field.NotSupported(promptPath.Child("distribution"), prompt.Distribution,
+			[]string{PromptLengthFixed, PromptLengthUniform, PromptLengthNormal}))
+	}
+	if prompt.Min < 1 {
+		allErrs = append(allErrs, field.Invalid(promptPath.Child("min"), prompt.Min, "must be at least 1"))
+	}
+	if prompt.Max < prompt.Min {
+		allErrs = append(allErrs, field.Invalid(promptPath.Child("max"), prompt.Max, "must not be less than min"))
+	}
+	if prompt.Distribution != PromptLengthUniform && (prompt.Mean < prompt.Min || prompt.Mean > prompt.Max) {
+		allErrs = append(allErrs, field.Invalid(promptPath.Child("mean"), prompt.Mean, "must be between min and max"))
+	}
+	if prompt.StdDev < 0 {
+		allErrs = append(allErrs, field.Invalid(promptPath.Child("stdDev"), prompt.StdDev, "must not be negative"))
+	}
+
+	if prefix := spec.SharedPrefix; prefix != nil {
+		prefixPath := specPath.Child("sharedPrefix")
+		if prefix.Percent < 0 || prefix.Percent > 100 {
+			allErrs = append(allErrs, field.Invalid(prefixPath.Child("percent"), prefix.Percent, "must be a percentage between 0 and 100"))
+		}
+		if prefix.Length < 1 {
+			allErrs = append(allErrs, field.Invalid(prefixPath.Child("length"), prefix.Length, "must be at least 1"))
+		}
+		if prefix.Groups < 1 {
+			allErrs = append(allErrs, field.Invalid(prefixPath.Child("groups"), prefix.Groups, "must be at least 1"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("LoadTest").GroupKind(), r.Name, allErrs)
+}
+
+// validateLoadPhases checks the shape parameters of every phase
+func validateLoadPhases(phases []LoadPhase, phasesPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+	for i, phase := range phases {
+		path := phasesPath.Index(i)
+		// Phase names key the results
+		for _, msg := range validation.IsDNS1123Label(phase.Name) {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), phase.Name, msg))
+		}
+		if names[phase.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), phase.Name))
+		}
+		names[phase.Name] = true
+		if phase.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("duration"), phase.Duration.Duration.String(), "must be positive"))
+		}
+		for _, rate := range []struct {
+			name  string
+			value int32
+		}{{"requestsPerSecond", phase.RequestsPerSecond}, {"from", phase.From}, {"to", phase.To}, {"amplitude", phase.Amplitude}} {
+			if rate.value < 0 {
+				allErrs = append(allErrs, field.Invalid(path.Child(rate.name), rate.value, "must not be negative"))
+			}
+		}
+		if phase.Trace != nil && phase.Shape != LoadShapeTrace {
+			allErrs = append(allErrs, field.Forbidden(path.Child("trace"), "is only used by the Trace shape"))
+		}
+
+		switch phase.Shape {
+		case LoadShapeConstant:
+		case LoadShapeRamp:
+			if phase.From == 0 && phase.To == 0 {
+				allErrs = append(allErrs, field.Invalid(path.Child("to"), phase.To, "from or to must be positive"))
+			}
+		case LoadShapeStep:
+			if phase.From == 0 && phase.To == 0 {
+				allErrs = append(allErrs, field.Invalid(path.Child("to"), phase.To, "from or to must be positive"))
+			}
+			if phase.Steps < 2 {
+				allErrs = append(allErrs, field.Invalid(path.Child("steps"), phase.Steps, "must be at least 2"))
+			}
+		case LoadShapeSinusoidal:
+			if phase.RequestsPerSecond < 1 {
+				allErrs = append(allErrs, field.Invalid(path.Child("requestsPerSecond"), phase.RequestsPerSecond, "must be at least 1"))
+			}
+			if phase.Amplitude > phase.RequestsPerSecond {
+				allErrs = append(allErrs, field.Invalid(path.Child("amplitude"), phase.Amplitude, "must not exceed requestsPerSecond"))
+			}
+			if phase.Period.Duration <= 0 {
+				allErrs = append(allErrs, field.Invalid(path.Child("period"), phase.Period.Duration.String(), "must be positive"))
+			}
+		case LoadShapePoisson:
+			if phase.RequestsPerSecond < 1 {
+				allErrs = append(allErrs, field.Invalid(path.Child("requestsPerSecond"), phase.RequestsPerSecond, "must be at least 1"))
+			}
+			if phase.BurstSize < 1 {
+				allErrs = append(allErrs, field.Invalid(path.Child("burstSize"), phase.BurstSize, "must be at least 1"))
+			}
+		case LoadShapeTrace:
+			if phase.Trace == nil || phase.Trace.Name == "" || phase.Trace.Key == "" {
+				allErrs = append(allErrs, field.Required(path.Child("trace"), "a ConfigMap name and key are required by the Trace shape"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(path.Child("shape"), phase.Shape, []string{
+				LoadShapeConstant, LoadShapeRamp, LoadShapeStep, LoadShapeSinusoidal, LoadShapePoisson, LoadShapeTrace,
+			}))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,248 @@
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newLoadTest(name string) *LoadTest {
+	return &LoadTest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       LoadTestSpec{Target: LoadTestTarget{SimulatorDeployment: "llm-sim-full"}},
+	}
+}
+
+func TestLoadTestDefault(t *testing.T) {
+	loadTest := newLoadTest("defaults")
+	loadTest.Spec.SharedPrefix = &SharedPrefixConfig{Percent: 30}
+	loadTest.Default()
+
+	spec := loadTest.Spec
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"api", spec.API, "completions"},
+		{"model", spec.Model, "random"},
+		{"concurrency", spec.Concurrency, int32(1)},
+		{"duration", spec.Duration.Duration, time.Minute},
+		{"requestTimeout", spec.RequestTimeout.Duration, 30 * time.Second},
+		{"maxTokens", spec.MaxTokens, int32(16)},
+		{"promptLength.distribution", spec.PromptLength.Distribution, PromptLengthFixed},
+		{"promptLength.mean", spec.PromptLength.Mean, int32(128)},
+		{"promptLength.min", spec.PromptLength.Min, int32(1)},
+		{"promptLength.max", spec.PromptLength.Max, int32(256)},
+		{"sharedPrefix.length", spec.SharedPrefix.Length, int32(64)},
+		{"sharedPrefix.groups", spec.SharedPrefix.Groups, int32(1)},
+		{"image", spec.Image, DefaultLoadGeneratorImage},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	again := loadTest.DeepCopy()
+	again.Default()
+	if !equalJSON(t, loadTest, again) {
+		t.Errorf("Default is not idempotent")
+	}
+	if err := loadTest.Validate(); err != nil {
+		t.Errorf("defaulted spec is invalid: %v", err)
+	}
+}
+
+func TestLoadTestDefaultPhases(t *testing.T) {
+	loadTest := newLoadTest("phases")
+	loadTest.Spec.Phases = []LoadPhase{
+		{Name: "warmup", Duration: metav1.Duration{Duration: 10 * time.Second}, RequestsPerSecond: 2},
+		{Name: "steps", Shape: LoadShapeStep, Duration: metav1.Duration{Duration: time.Minute}, From: 5, To: 20},
+		{Name: "diurnal", Shape: LoadShapeSinusoidal, Duration: metav1.Duration{Duration: 2 * time.Minute}, RequestsPerSecond: 10, Amplitude: 5},
+		{Name: "bursts", Shape: LoadShapePoisson, Duration: metav1.Duration{Duration: time.Minute}, RequestsPerSecond: 4},
+	}
+	loadTest.Default()
+
+	spec := loadTest.Spec
+	if spec.Duration != nil {
+		t.Errorf("duration = %v, want it unset with phases", spec.Duration)
+	}
+	if got := spec.Phases[0].Shape; got != LoadShapeConstant {
+		t.Errorf("phases[0].shape = %q, want %q", got, LoadShapeConstant)
+	}
+	if got := spec.Phases[1].Steps; got != 4 {
+		t.Errorf("phases[1].steps = %d, want 4", got)
+	}
+	if got := spec.Phases[2].Period; got == nil || got.Duration != 2*time.Minute {
+		t.Errorf("phases[2].period = %v, want the phase duration", got)
+	}
+	if got := spec.Phases[3].BurstSize; got != 1 {
+		t.Errorf("phases[3].burstSize = %d, want 1", got)
+	}
+	if err := loadTest.Validate(); err != nil {
+		t.Errorf("defaulted spec is invalid: %v", err)
+	}
+}
+
+func TestLoadTestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*LoadTest)
+		wantErr string
+	}{
+		{
+			name:    "no target",
+			mutate:  func(l *LoadTest) { l.Spec.Target = LoadTestTarget{} },
+			wantErr: "exactly one of",
+		},
+		{
+			name:    "two targets",
+			mutate:  func(l *LoadTest) { l.Spec.Target.SchedulerInstall = "llm-sched-install" },
+			wantErr: "exactly one of",
+		},
+		{
+			name:    "relative url",
+			mutate:  func(l *LoadTest) { l.Spec.Target = LoadTestTarget{URL: "gateway:80"} },
+			wantErr: "spec.target.url",
+		},
+		{
+			name:    "unknown api",
+			mutate:  func(l *LoadTest) { l.Spec.API = "embeddings" },
+			wantErr: "spec.api",
+		},
+		{
+			name:    "zero duration",
+			mutate:  func(l *LoadTest) { l.Spec.Duration = &metav1.Duration{} },
+			wantErr: "spec.duration",
+		},
+		{
+			name: "mean outside min and max",
+			mutate: func(l *LoadTest) {
+				l.Spec.PromptLength = &PromptLengthDistribution{Distribution: PromptLengthNormal, Mean: 50, Min: 100, Max: 200}
+			},
+			wantErr: "spec.promptLength.mean",
+		},
+		{
+			name:    "prefix percent above 100",
+			mutate:  func(l *LoadTest) { l.Spec.SharedPrefix = &SharedPrefixConfig{Percent: 120} },
+			wantErr: "spec.sharedPrefix.percent",
+		},
+		{
+			name:    "name too long for the job-name label",
+			mutate:  func(l *LoadTest) { l.Name = strings.Repeat("a", 64) },
+			wantErr: "metadata.name",
+		},
+		{
+			name: "duration with phases",
+			mutate: func(l *LoadTest) {
+				l.Spec.Duration = &metav1.Duration{Duration: time.Minute}
+				l.Spec.Phases = []LoadPhase{{Name: "steady", Duration: metav1.Duration{Duration: time.Minute}}}
+			},
+			wantErr: "spec.duration",
+		},
+		{
+			name: "duplicate phase names",
+			mutate: func(l *LoadTest) {
+				phase := LoadPhase{Name: "steady", Duration: metav1.Duration{Duration: time.Minute}, RequestsPerSecond: 5}
+				l.Spec.Phases = []LoadPhase{phase, phase}
+			},
+			wantErr: "spec.phases[1].name",
+		},
+		{
+			name: "sinusoidal amplitude above the baseline",
+			mutate: func(l *LoadTest) {
+				l.Spec.Phases = []LoadPhase{{Name: "diurnal", Shape: LoadShapeSinusoidal, Duration: metav1.Duration{Duration: time.Minute}, RequestsPerSecond: 5, Amplitude: 10}}
+			},
+			wantErr: "spec.phases[0].amplitude",
+		},
+		{
+			name: "ramp without a rate",
+			mutate: func(l *LoadTest) {
+				l.Spec.Phases = []LoadPhase{{Name: "ramp", Shape: LoadShapeRamp, Duration: metav1.Duration{Duration: time.Minute}}}
+			},
+			wantErr: "spec.phases[0].to",
+		},
+		{
+			name: "trace without a ConfigMap",
+			mutate: func(l *LoadTest) {
+				l.Spec.Phases = []LoadPhase{{Name: "replay", Shape: LoadShapeTrace, Duration: metav1.Duration{Duration: time.Minute}}}
+			},
+			wantErr: "spec.phases[0].trace",
+		},
+		{
+			name: "trace on a constant phase",
+			mutate: func(l *LoadTest) {
+				l.Spec.Phases = []LoadPhase{{
+					Name: "steady", Duration: metav1.Duration{Duration: time.Minute}, RequestsPerSecond: 5,
+					Trace: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "arrivals"}, Key: "trace"},
+				}}
+			},
+			wantErr: "spec.phases[0].trace",
+		},
+		{
+			name: "valid phases",
+			mutate: func(l *LoadTest) {
+				l.Spec.Phases = []LoadPhase{
+					{Name: "ramp-up", Shape: LoadShapeRamp, Duration: metav1.Duration{Duration: time.Minute}, To: 20},
+					{Name: "replay", Shape: LoadShapeTrace, Duration: metav1.Duration{Duration: time.Minute},
+						Trace: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "arrivals"}, Key: "trace"}},
+				}
+			},
+		},
+		{
+			name: "valid url target",
+			mutate: func(l *LoadTest) {
+				l.Spec.Target = LoadTestTarget{URL: "http://gateway.llm-d-sim.svc:80"}
+				l.Spec.PromptLength = &PromptLengthDistribution{Distribution: PromptLengthUniform, Min: 10, Max: 20}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := newLoadTest("validate")
+			tt.mutate(loadTest)
+			loadTest.Default()
+			err := loadTest.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) {
+				t.Fatalf("expected an Invalid error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadTestValidateUpdate(t *testing.T) {
+	old := newLoadTest("immutable")
+	old.Default()
+
+	relabeled := old.DeepCopy()
+	relabeled.Labels = map[string]string{"run": "2"}
+	if _, err := (&loadTestWebhook{}).ValidateUpdate(context.Background(), old, relabeled); err != nil {
+		t.Errorf("metadata update rejected: %v", err)
+	}
+
+	changed := old.DeepCopy()
+	changed.Spec.Concurrency = 16
+	if _, err := (&loadTestWebhook{}).ValidateUpdate(context.Background(), old, changed); !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "immutable") {
+		t.Errorf("spec update = %v, want an Invalid immutable error", err)
+	}
+}
+
+func TestLoadTestCRDImageDefault(t *testing.T) {
+	if got := crdDefault(t, "sim.llm-d.io_loadtests.yaml", "image"); got != DefaultLoadGeneratorImage {
+		t.Errorf("spec.image default = %v, want %s", got, DefaultLoadGeneratorImage)
+	}
+}
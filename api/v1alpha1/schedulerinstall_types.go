@@ -11,8 +11,14 @@ type SchedulerInstallSpec struct {
 	SchedulerNamespace string `json:"schedulerNamespace,omitempty"`
 
 	// SimulatorNamespace is the namespace where simulator backends run
+	// Defaults to simulatorRef.namespace
 	SimulatorNamespace string `json:"simulatorNamespace,omitempty"`
 
+	// SimulatorRef binds the install to a SimulatorDeployment. The proxy
+	// Service and InferencePool selectors and target ports that are not set
+	// are derived from its decode stage.
+	SimulatorRef *SimulatorReference `json:"simulatorRef,omitempty"`
+
 	// ProxyService defines the proxy Service that fronts simulator backends
 	ProxyService ProxyServiceConfig `json:"proxyService,omitempty"`
 
@@ -30,6 +36,12 @@ type SchedulerInstallSpec struct {
 
 	// EnvoyFilter configuration for ext_proc (scoring)
 	EnvoyFilter *SchedulerEnvoyFilterConfig `json:"envoyFilter,omitempty"`
+
+	// InferencePool configuration (operator-managed InferencePool for the EPP)
+	InferencePool *SchedulerInferencePoolConfig `json:"inferencePool,omitempty"`
+
+	// Canary shifts the HTTPRoute traffic from a stable to a candidate backend in steps
+	Canary *SchedulerCanaryConfig `json:"canary,omitempty"`
 }
 
 // ProxyServiceConfig defines the Service that routes to simulator backends
@@ -43,13 +55,25 @@ type ProxyServiceConfig struct {
 	Port int32 `json:"port,omitempty"`
 
 	// TargetPort on backend pods
-	// +kubebuilder:default=8200
+	// Defaults to the decode port of simulatorRef, then port
 	TargetPort int32 `json:"targetPort,omitempty"`
 
 	// Selector to match simulator backend pods
+	// Defaults to the decode pods of simulatorRef, then all decode pods
 	Selector map[string]string `json:"selector,omitempty"`
 }
 
+// SimulatorReference identifies a SimulatorDeployment
+type SimulatorReference struct {
+	// Name of the SimulatorDeployment
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the SimulatorDeployment
+	// Defaults to simulatorNamespace, then the namespace of the SchedulerInstall
+	Namespace string `json:"namespace,omitempty"`
+}
+
 // SchedulerEPPConfig defines EPP configuration for the scheduler namespace
 type SchedulerEPPConfig struct {
 	// Enabled determines if EPP should be deployed
@@ -79,8 +103,12 @@ type SchedulerEPPConfig struct {
 	// Args are additional arguments to pass to the EPP container
 	Args []string `json:"args,omitempty"`
 
+	// Env are additional environment variables for the EPP container, e.g.
+	// SCHEDULING_DECISIONS_ADDR to serve the scoring decisions of the EPP
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
 	// PoolName is the InferencePool name EPP watches
-	// +kubebuilder:default="gaie-inference-scheduling"
+	// Defaults to inferencePool.name, then "gaie-inference-scheduling"
 	PoolName string `json:"poolName,omitempty"`
 
 	// PoolNamespace is the namespace of the InferencePool
@@ -96,6 +124,19 @@ type SchedulerEPPConfig struct {
 	// +kubebuilder:validation:Enum=default;proxy-performance;proxy-performance-by-backend
 	// +kubebuilder:default="default"
 	ConfigProfile string `json:"configProfile,omitempty"`
+
+	// Plugins override or extend the plugins of the ConfigProfile preset
+	Plugins []EPPPlugin `json:"plugins,omitempty"`
+
+	// SchedulingProfiles override or extend the scheduling profiles of the ConfigProfile preset
+	SchedulingProfiles []EPPSchedulingProfile `json:"schedulingProfiles,omitempty"`
+
+	// RestartPolicy controls when the EPP pods are restarted: OnConfigChange
+	// rolls them when the rendered plugin config changes, Manual only through
+	// the sim.llm-d.io/restart-epp annotation
+	// +kubebuilder:validation:Enum=OnConfigChange;Manual
+	// +kubebuilder:default="OnConfigChange"
+	RestartPolicy string `json:"restartPolicy,omitempty"`
 }
 
 // SchedulerGatewayConfig defines Gateway API Gateway configuration
@@ -129,6 +170,14 @@ type GatewayRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// EnvoyFilter epp-cluster types
+const (
+	// EnvoyFilterClusterStatic points epp-cluster at the EPP Service ClusterIP
+	EnvoyFilterClusterStatic = "STATIC"
+	// EnvoyFilterClusterStrictDNS resolves the EPP Service name from the gateway
+	EnvoyFilterClusterStrictDNS = "STRICT_DNS"
+)
+
 // SchedulerEnvoyFilterConfig defines EnvoyFilter configuration for ext_proc
 type SchedulerEnvoyFilterConfig struct {
 	// Enabled determines if the EnvoyFilter should be created
@@ -141,6 +190,55 @@ type SchedulerEnvoyFilterConfig struct {
 	// WorkloadSelector labels to match the Gateway pod
 	// Defaults to matching the gateway name if not provided
 	WorkloadSelector map[string]string `json:"workloadSelector,omitempty"`
+
+	// ClusterType of the epp-cluster the gateway uses to reach the EPP:
+	// STATIC uses the EPP Service ClusterIP and is re-rendered when it changes,
+	// STRICT_DNS resolves the Service name through the gateway's DNS
+	// +kubebuilder:validation:Enum=STATIC;STRICT_DNS
+	// +kubebuilder:default="STATIC"
+	ClusterType string `json:"clusterType,omitempty"`
+
+	// ProcessingMode selects which parts of requests and responses Envoy sends to the EPP
+	ProcessingMode *ExtProcProcessingMode `json:"processingMode,omitempty"`
+
+	// Timeout of the gRPC stream to the EPP
+	// +kubebuilder:default="2s"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// MessageTimeout is how long Envoy waits for the EPP to answer each message
+	// Defaults to the Envoy default (200ms) when not set
+	MessageTimeout *metav1.Duration `json:"messageTimeout,omitempty"`
+
+	// FailureModeAllow lets requests through unscheduled when the EPP fails
+	// Set to false to fail closed
+	// +kubebuilder:default=true
+	FailureModeAllow *bool `json:"failureModeAllow,omitempty"`
+
+	// AllowModeOverride lets the EPP change the processing mode in its responses
+	AllowModeOverride bool `json:"allowModeOverride,omitempty"`
+}
+
+// ExtProcProcessingMode mirrors the Envoy ext_proc ProcessingMode
+type ExtProcProcessingMode struct {
+	// RequestHeaderMode for request headers
+	// +kubebuilder:validation:Enum=DEFAULT;SEND;SKIP
+	// +kubebuilder:default="SKIP"
+	RequestHeaderMode string `json:"requestHeaderMode,omitempty"`
+
+	// RequestBodyMode for the request body
+	// +kubebuilder:validation:Enum=NONE;STREAMED;BUFFERED;BUFFERED_PARTIAL;FULL_DUPLEX_STREAMED
+	// +kubebuilder:default="BUFFERED"
+	RequestBodyMode string `json:"requestBodyMode,omitempty"`
+
+	// ResponseHeaderMode for response headers
+	// +kubebuilder:validation:Enum=DEFAULT;SEND;SKIP
+	// +kubebuilder:default="SKIP"
+	ResponseHeaderMode string `json:"responseHeaderMode,omitempty"`
+
+	// ResponseBodyMode for the response body
+	// +kubebuilder:validation:Enum=NONE;STREAMED;BUFFERED;BUFFERED_PARTIAL;FULL_DUPLEX_STREAMED
+	// +kubebuilder:default="NONE"
+	ResponseBodyMode string `json:"responseBodyMode,omitempty"`
 }
 
 // SchedulerRoutingConfig defines HTTPRoute + ReferenceGrant configuration
@@ -162,6 +260,87 @@ type SchedulerRoutingConfig struct {
 
 	// ParentGateway references the Gateway for the HTTPRoute
 	ParentGateway GatewayRef `json:"parentGateway,omitempty"`
+
+	// Rules replace the default single rule (PathPrefix / to the backend
+	// selected by BackendType) with matches and weighted backends
+	// +kubebuilder:validation:MaxItems=16
+	Rules []HTTPRouteRule `json:"rules,omitempty"`
+}
+
+// HTTPRouteRule routes requests matching any of Matches to BackendRefs
+type HTTPRouteRule struct {
+	// Matches of the rule; a rule without matches matches every request
+	// +kubebuilder:validation:MaxItems=8
+	Matches []HTTPRouteMatch `json:"matches,omitempty"`
+
+	// BackendRefs receive the matched requests in proportion to their weights
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	BackendRefs []HTTPBackendRef `json:"backendRefs"`
+}
+
+// HTTPRouteMatch matches a request when the path and all headers and query parameters match
+type HTTPRouteMatch struct {
+	// Path to match, defaults to the Gateway API default of PathPrefix /
+	Path *HTTPPathMatch `json:"path,omitempty"`
+
+	// Headers that must all match
+	// +kubebuilder:validation:MaxItems=16
+	Headers []HTTPValueMatch `json:"headers,omitempty"`
+
+	// QueryParams that must all match
+	// +kubebuilder:validation:MaxItems=16
+	QueryParams []HTTPValueMatch `json:"queryParams,omitempty"`
+}
+
+// HTTPPathMatch matches the request path
+type HTTPPathMatch struct {
+	// Type of the match
+	// +kubebuilder:validation:Enum=Exact;PathPrefix;RegularExpression
+	// +kubebuilder:default="PathPrefix"
+	Type string `json:"type,omitempty"`
+
+	// Value to match against
+	// +kubebuilder:default="/"
+	Value string `json:"value,omitempty"`
+}
+
+// HTTPValueMatch matches a request header or query parameter by name
+type HTTPValueMatch struct {
+	// Type of the match
+	// +kubebuilder:validation:Enum=Exact;RegularExpression
+	// +kubebuilder:default="Exact"
+	Type string `json:"type,omitempty"`
+
+	// Name of the header or query parameter
+	Name string `json:"name"`
+
+	// Value to match against
+	Value string `json:"value"`
+}
+
+// HTTPBackendRef is a weighted Service or InferencePool backend
+type HTTPBackendRef struct {
+	// Kind of the backend ("Service" or "InferencePool")
+	// +kubebuilder:validation:Enum=Service;InferencePool
+	// +kubebuilder:default="Service"
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the backend
+	Name string `json:"name"`
+
+	// Namespace of the backend
+	// Defaults to simulatorNamespace
+	Namespace string `json:"namespace,omitempty"`
+
+	// Port of the backend, required for Services
+	Port int32 `json:"port,omitempty"`
+
+	// Weight of the backend relative to the others in the rule
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000000
+	// +kubebuilder:default=1
+	Weight *int32 `json:"weight,omitempty"`
 }
 
 // InferencePoolRef identifies an InferencePool backend
@@ -176,15 +355,231 @@ type InferencePoolRef struct {
 	Port int32 `json:"port,omitempty"`
 }
 
+// Canary phases
+const (
+	// CanaryProgressing means the candidate weight is still being raised
+	CanaryProgressing = "Progressing"
+	// CanaryCompleted means the last step was reached and is held indefinitely
+	CanaryCompleted = "Completed"
+	// CanaryRolledBack means the analysis found the candidate unhealthy and all
+	// traffic went back to the stable backend
+	CanaryRolledBack = "RolledBack"
+)
+
+// Default metric names compared by canary analysis, as exposed by the EPP
+const (
+	DefaultCanaryRequestsMetric = "inference_objective_request_total"
+	DefaultCanaryErrorsMetric   = "inference_objective_request_error_total"
+	DefaultCanaryLatencyMetric  = "inference_objective_request_duration_seconds"
+)
+
+// SchedulerCanaryConfig shifts traffic from Stable to Candidate. It replaces
+// the default HTTPRoute rule, so it cannot be combined with routing.rules.
+type SchedulerCanaryConfig struct {
+	// Enabled determines if the canary drives the HTTPRoute weights
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Stable is the backend that serves the traffic the candidate does not
+	Stable HTTPBackendRef `json:"stable"`
+
+	// Candidate is the backend traffic is shifted to
+	Candidate HTTPBackendRef `json:"candidate"`
+
+	// Steps are the candidate weights in percent, in increasing order
+	// +kubebuilder:default={5,25,50,100}
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=20
+	Steps []int32 `json:"steps,omitempty"`
+
+	// StepDuration is how long each step is held before moving to the next one
+	// +kubebuilder:default="10m"
+	StepDuration *metav1.Duration `json:"stepDuration,omitempty"`
+
+	// Analysis gates every step on metrics of both backends and rolls the
+	// canary back when the candidate is worse than the stable backend
+	Analysis *CanaryAnalysis `json:"analysis,omitempty"`
+}
+
+// CanaryAnalysis compares the traffic each backend served during a step
+type CanaryAnalysis struct {
+	// Stable lists the metrics endpoints of the stable backend
+	Stable CanaryMetricsSource `json:"stable"`
+
+	// Candidate lists the metrics endpoints of the candidate backend
+	Candidate CanaryMetricsSource `json:"candidate"`
+
+	// Metrics names the series compared between the backends
+	// Defaults to the EPP request, error and latency metrics
+	Metrics *CanaryMetricNames `json:"metrics,omitempty"`
+
+	// MaxErrorRateIncrease is how many percentage points the candidate error
+	// rate may exceed the stable error rate
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	MaxErrorRateIncrease *int32 `json:"maxErrorRateIncrease,omitempty"`
+
+	// MaxLatencyIncrease is how many percent the candidate mean latency may
+	// exceed the stable mean latency
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=20
+	MaxLatencyIncrease *int32 `json:"maxLatencyIncrease,omitempty"`
+
+	// MinRequests each backend must serve during a step before it is analyzed
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=20
+	MinRequests int64 `json:"minRequests,omitempty"`
+}
+
+// CanaryMetricsSource is a set of Prometheus text-format endpoints whose series are summed
+type CanaryMetricsSource struct {
+	// URLs to scrape, e.g. the EPP on :9090/metrics or a proxy or simulator /metrics
+	// +kubebuilder:validation:MinItems=1
+	URLs []string `json:"urls"`
+
+	// Labels select the series to sum; empty matches every series
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// CanaryMetricNames names the series canary analysis reads
+type CanaryMetricNames struct {
+	// Requests is a counter of served requests
+	Requests string `json:"requests"`
+
+	// Errors is a counter of failed requests; the error rate is not compared when empty
+	Errors string `json:"errors,omitempty"`
+
+	// Latency is a histogram of request latency in seconds; latency is not compared when empty
+	Latency string `json:"latency,omitempty"`
+}
+
+// CanaryStatus reports the progress of a canary
+type CanaryStatus struct {
+	// Phase is Progressing, Completed or RolledBack
+	Phase string `json:"phase,omitempty"`
+
+	// Step is the index of the current step in spec.canary.steps
+	Step int32 `json:"step"`
+
+	// CandidateWeight is the percentage of traffic currently sent to the candidate
+	CandidateWeight int32 `json:"candidateWeight"`
+
+	// StepStartTime is when the current step was applied
+	StepStartTime metav1.Time `json:"stepStartTime,omitempty"`
+
+	// Revision identifies the backends and steps being rolled out; changing
+	// any of them restarts the canary from the first step
+	Revision string `json:"revision,omitempty"`
+
+	// Message explains why the canary is holding its step or was rolled back
+	Message string `json:"message,omitempty"`
+
+	// Baseline holds the counters of both backends when the current step started
+	Baseline *CanaryAnalysisSample `json:"baseline,omitempty"`
+}
+
+// CanaryAnalysisSample is one scrape of both backends
+type CanaryAnalysisSample struct {
+	// Stable is the sample of the stable backend
+	Stable CanaryMetricsSample `json:"stable"`
+
+	// Candidate is the sample of the candidate backend
+	Candidate CanaryMetricsSample `json:"candidate"`
+}
+
+// CanaryMetricsSample holds the counter values of one backend
+type CanaryMetricsSample struct {
+	// Requests counted by the requests metric
+	Requests int64 `json:"requests"`
+
+	// Errors counted by the errors metric
+	Errors int64 `json:"errors,omitempty"`
+
+	// LatencyCount is the number of observations of the latency histogram
+	LatencyCount int64 `json:"latencyCount,omitempty"`
+
+	// LatencySumMillis is the sum of the latency histogram in milliseconds
+	LatencySumMillis int64 `json:"latencySumMillis,omitempty"`
+}
+
+// SchedulerInferencePoolConfig defines an InferencePool managed by the operator
+type SchedulerInferencePoolConfig struct {
+	// Enabled determines if the InferencePool should be created
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Name of the InferencePool
+	// Defaults to epp.poolName
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the InferencePool
+	// Defaults to epp.poolNamespace
+	Namespace string `json:"namespace,omitempty"`
+
+	// Selector labels to match model server pods
+	// Defaults to proxyService.selector
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// TargetPorts on model server pods
+	// Defaults to proxyService.targetPort
+	TargetPorts []int32 `json:"targetPorts,omitempty"`
+
+	// EndpointPickerRef references the EPP Service that serves this pool
+	EndpointPickerRef *EndpointPickerRef `json:"endpointPickerRef,omitempty"`
+}
+
+// EndpointPickerRef identifies the EPP Service for an InferencePool
+type EndpointPickerRef struct {
+	// Name of the EPP Service
+	// Defaults to epp.name
+	Name string `json:"name,omitempty"`
+
+	// Port of the EPP Service
+	// Defaults to epp.port
+	Port int32 `json:"port,omitempty"`
+
+	// FailureMode configures the gateway behavior when the EPP is unavailable
+	// +kubebuilder:validation:Enum=FailOpen;FailClose
+	// +kubebuilder:default="FailClose"
+	FailureMode string `json:"failureMode,omitempty"`
+}
+
 // SchedulerInstallStatus defines the observed state of SchedulerInstall
 type SchedulerInstallStatus struct {
-	// Conditions represent the latest available observations
+	// Conditions represent the latest available observations: Ready, one
+	// condition per managed area (EPPReady, GatewayProgrammed, RouteAccepted,
+	// ReferenceGrantReady, DestinationRuleReady, EnvoyFilterApplied,
+	// InferencePoolReady, SimulatorReady) and CRDsMissing
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Canary reports the current step of spec.canary
+	Canary *CanaryStatus `json:"canary,omitempty"`
+
+	// Simulator reports the SimulatorDeployment of spec.simulatorRef
+	Simulator *SimulatorBindingStatus `json:"simulator,omitempty"`
+}
+
+// SimulatorBindingStatus reports the SimulatorDeployment a SchedulerInstall is bound to
+type SimulatorBindingStatus struct {
+	// Name of the SimulatorDeployment
+	Name string `json:"name"`
+
+	// Namespace of the SimulatorDeployment
+	Namespace string `json:"namespace"`
+
+	// Ready mirrors the Ready condition of the SimulatorDeployment; it is
+	// false while the SimulatorDeployment does not exist
+	Ready bool `json:"ready"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=schedinst
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="EPP",type=string,JSONPath=`.status.conditions[?(@.type=="EPPReady")].status`
+// +kubebuilder:printcolumn:name="Gateway",type=string,JSONPath=`.status.conditions[?(@.type=="GatewayProgrammed")].status`
+// +kubebuilder:printcolumn:name="Route",type=string,JSONPath=`.status.conditions[?(@.type=="RouteAccepted")].status`
+// +kubebuilder:printcolumn:name="Simulator",type=string,JSONPath=`.status.simulator.name`,priority=1
+// +kubebuilder:printcolumn:name="Canary",type=integer,JSONPath=`.status.canary.candidateWeight`,priority=1
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`,priority=1
 
 // SchedulerInstall is the Schema for the schedulerinstalls API
 type SchedulerInstall struct {
@@ -0,0 +1,644 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers the SchedulerInstall defaulting and validating webhooks
+func (r *SchedulerInstall) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&schedulerInstallWebhook{}).
+		WithValidator(&schedulerInstallWebhook{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-sim-llm-d-io-v1alpha1-schedulerinstall,mutating=true,failurePolicy=fail,sideEffects=None,groups=sim.llm-d.io,resources=schedulerinstalls,verbs=create;update,versions=v1alpha1,name=mschedulerinstall.sim.llm-d.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-sim-llm-d-io-v1alpha1-schedulerinstall,mutating=false,failurePolicy=fail,sideEffects=None,groups=sim.llm-d.io,resources=schedulerinstalls,verbs=create;update,versions=v1alpha1,name=vschedulerinstall.sim.llm-d.io,admissionReviewVersions=v1
+
+// schedulerInstallWebhook adapts SchedulerInstall.Default and Validate to admission
+type schedulerInstallWebhook struct{}
+
+var _ webhook.CustomDefaulter = &schedulerInstallWebhook{}
+var _ webhook.CustomValidator = &schedulerInstallWebhook{}
+
+func (w *schedulerInstallWebhook) Default(_ context.Context, obj runtime.Object) error {
+	install, ok := obj.(*SchedulerInstall)
+	if !ok {
+		return fmt.Errorf("expected a SchedulerInstall but got %T", obj)
+	}
+	install.Default()
+	return nil
+}
+
+func (w *schedulerInstallWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	install, ok := obj.(*SchedulerInstall)
+	if !ok {
+		return nil, fmt.Errorf("expected a SchedulerInstall but got %T", obj)
+	}
+	return nil, install.Validate()
+}
+
+func (w *schedulerInstallWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	install, ok := newObj.(*SchedulerInstall)
+	if !ok {
+		return nil, fmt.Errorf("expected a SchedulerInstall but got %T", newObj)
+	}
+	old, ok := oldObj.(*SchedulerInstall)
+	if !ok {
+		return nil, fmt.Errorf("expected a SchedulerInstall but got %T", oldObj)
+	}
+	// Moving the install would orphan everything created in the old namespaces
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if old.Spec.SchedulerNamespace != "" && install.Spec.SchedulerNamespace != old.Spec.SchedulerNamespace {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("schedulerNamespace"), "field is immutable"))
+	}
+	if old.Spec.SimulatorNamespace != "" && install.Spec.SimulatorNamespace != old.Spec.SimulatorNamespace {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("simulatorNamespace"), "field is immutable"))
+	}
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("SchedulerInstall").GroupKind(), install.Name, allErrs)
+	}
+	return nil, install.Validate()
+}
+
+func (w *schedulerInstallWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// Default resolves the namespaces, object names and routing of a SchedulerInstall
+func (r *SchedulerInstall) Default() {
+	spec := &r.Spec
+	if spec.SchedulerNamespace == "" {
+		spec.SchedulerNamespace = r.Namespace
+	}
+	if spec.SimulatorRef != nil {
+		if spec.SimulatorRef.Namespace == "" {
+			spec.SimulatorRef.Namespace = spec.SimulatorNamespace
+		}
+		if spec.SimulatorRef.Namespace == "" {
+			spec.SimulatorRef.Namespace = r.Namespace
+		}
+		if spec.SimulatorNamespace == "" {
+			spec.SimulatorNamespace = spec.SimulatorRef.Namespace
+		}
+	}
+	if spec.ProxyService.Name == "" {
+		spec.ProxyService.Name = "gaie-inference-scheduling-proxy"
+	}
+	if spec.ProxyService.Port == 0 {
+		spec.ProxyService.Port = 8200
+	}
+	// With a simulatorRef the target port and selector follow the simulator,
+	// see BindSimulator
+	if spec.SimulatorRef == nil {
+		if spec.ProxyService.TargetPort == 0 {
+			spec.ProxyService.TargetPort = spec.ProxyService.Port
+		}
+		if len(spec.ProxyService.Selector) == 0 {
+			spec.ProxyService.Selector = map[string]string{
+				"llm-d.ai/role":             "decode",
+				"llm-d.ai/inferenceServing": "true",
+			}
+		}
+	}
+
+	if spec.EPP != nil && spec.InferencePool != nil {
+		if spec.EPP.PoolName == "" {
+			spec.EPP.PoolName = spec.InferencePool.Name
+		}
+		if spec.EPP.PoolNamespace == "" {
+			spec.EPP.PoolNamespace = spec.InferencePool.Namespace
+		}
+	}
+
+	if spec.EPP != nil {
+		if spec.EPP.Name == "" {
+			spec.EPP.Name = "gaie-inference-scheduling-epp"
+		}
+		if spec.EPP.Replicas == 0 {
+			spec.EPP.Replicas = 1
+		}
+		if spec.EPP.Image == "" {
+			spec.EPP.Image = DefaultEPPImage
+		}
+		if spec.EPP.Port == 0 {
+			spec.EPP.Port = 9002
+		}
+		if spec.EPP.Verbosity == 0 {
+			spec.EPP.Verbosity = 1
+		}
+		if spec.EPP.PoolName == "" {
+			spec.EPP.PoolName = "gaie-inference-scheduling"
+		}
+		if spec.EPP.PoolNamespace == "" {
+			spec.EPP.PoolNamespace = spec.SimulatorNamespace
+		}
+		if spec.EPP.ConfigProfile == "" {
+			spec.EPP.ConfigProfile = "default"
+		}
+		if spec.EPP.RestartPolicy == "" {
+			spec.EPP.RestartPolicy = EPPRestartOnConfigChange
+		}
+	}
+
+	if spec.InferencePool != nil {
+		pool := spec.InferencePool
+		if pool.Name == "" && spec.EPP != nil {
+			pool.Name = spec.EPP.PoolName
+		}
+		if pool.Namespace == "" {
+			if spec.EPP != nil && spec.EPP.PoolNamespace != "" {
+				pool.Namespace = spec.EPP.PoolNamespace
+			} else {
+				pool.Namespace = spec.SimulatorNamespace
+			}
+		}
+		if len(pool.Selector) == 0 {
+			pool.Selector = spec.ProxyService.Selector
+		}
+		if len(pool.TargetPorts) == 0 && spec.ProxyService.TargetPort != 0 {
+			pool.TargetPorts = []int32{spec.ProxyService.TargetPort}
+		}
+		if pool.EndpointPickerRef == nil {
+			pool.EndpointPickerRef = &EndpointPickerRef{}
+		}
+		if pool.EndpointPickerRef.Name == "" && spec.EPP != nil {
+			pool.EndpointPickerRef.Name = spec.EPP.Name
+		}
+		if pool.EndpointPickerRef.Port == 0 && spec.EPP != nil {
+			pool.EndpointPickerRef.Port = spec.EPP.Port
+		}
+		if pool.EndpointPickerRef.FailureMode == "" {
+			pool.EndpointPickerRef.FailureMode = "FailClose"
+		}
+	}
+
+	if spec.Gateway != nil {
+		if spec.Gateway.Name == "" {
+			spec.Gateway.Name = "infra-inference-scheduling-inference-gateway"
+		}
+		if spec.Gateway.ClassName == "" {
+			spec.Gateway.ClassName = "istio"
+		}
+		if spec.Gateway.ListenerPort == 0 {
+			spec.Gateway.ListenerPort = 80
+		}
+		if spec.Gateway.ListenerProtocol == "" {
+			spec.Gateway.ListenerProtocol = "HTTP"
+		}
+	}
+
+	if spec.Routing != nil {
+		if spec.Routing.BackendType == "" {
+			spec.Routing.BackendType = "Service"
+		}
+		if spec.Routing.HTTPRouteName == "" {
+			spec.Routing.HTTPRouteName = "llm-d-inference-scheduling"
+		}
+		if spec.Routing.ParentGateway.Name == "" && spec.Gateway != nil {
+			spec.Routing.ParentGateway.Name = spec.Gateway.Name
+		}
+		if spec.Routing.ParentGateway.Namespace == "" {
+			spec.Routing.ParentGateway.Namespace = spec.SchedulerNamespace
+		}
+		if spec.Routing.BackendType == "InferencePool" {
+			if spec.Routing.InferencePool == nil {
+				spec.Routing.InferencePool = &InferencePoolRef{}
+			}
+			if spec.Routing.InferencePool.Name == "" && spec.InferencePool != nil {
+				spec.Routing.InferencePool.Name = spec.InferencePool.Name
+			}
+			if spec.Routing.InferencePool.Name == "" && spec.EPP != nil {
+				spec.Routing.InferencePool.Name = spec.EPP.PoolName
+			}
+			if spec.Routing.InferencePool.Namespace == "" {
+				if spec.InferencePool != nil {
+					spec.Routing.InferencePool.Namespace = spec.InferencePool.Namespace
+				} else if spec.EPP != nil && spec.EPP.PoolNamespace != "" {
+					spec.Routing.InferencePool.Namespace = spec.EPP.PoolNamespace
+				} else {
+					spec.Routing.InferencePool.Namespace = spec.SimulatorNamespace
+				}
+			}
+		}
+	}
+
+	if spec.Routing != nil {
+		for i := range spec.Routing.Rules {
+			defaultHTTPRouteRule(&spec.Routing.Rules[i], spec.SimulatorNamespace)
+		}
+	}
+
+	if spec.Canary != nil {
+		defaultHTTPBackendRef(&spec.Canary.Stable, spec.SimulatorNamespace)
+		defaultHTTPBackendRef(&spec.Canary.Candidate, spec.SimulatorNamespace)
+		if len(spec.Canary.Steps) == 0 {
+			spec.Canary.Steps = []int32{5, 25, 50, 100}
+		}
+		if spec.Canary.StepDuration == nil {
+			spec.Canary.StepDuration = &metav1.Duration{Duration: 10 * time.Minute}
+		}
+		if analysis := spec.Canary.Analysis; analysis != nil {
+			if analysis.Metrics == nil {
+				analysis.Metrics = &CanaryMetricNames{
+					Requests: DefaultCanaryRequestsMetric,
+					Errors:   DefaultCanaryErrorsMetric,
+					Latency:  DefaultCanaryLatencyMetric,
+				}
+			}
+			if analysis.MaxErrorRateIncrease == nil {
+				maxErrorRateIncrease := int32(1)
+				analysis.MaxErrorRateIncrease = &maxErrorRateIncrease
+			}
+			if analysis.MaxLatencyIncrease == nil {
+				maxLatencyIncrease := int32(20)
+				analysis.MaxLatencyIncrease = &maxLatencyIncrease
+			}
+			if analysis.MinRequests == 0 {
+				analysis.MinRequests = 20
+			}
+		}
+	}
+
+	if spec.DestinationRule != nil {
+		if spec.DestinationRule.Algorithm == "" {
+			spec.DestinationRule.Algorithm = "ROUND_ROBIN"
+		}
+		if spec.DestinationRule.ConnectionPool == nil {
+			spec.DestinationRule.ConnectionPool = &ConnectionPoolConfig{
+				HTTP1MaxPendingRequests:  1,
+				MaxRequestsPerConnection: 1,
+			}
+		}
+	}
+
+	if spec.EnvoyFilter != nil {
+		if spec.EnvoyFilter.Name == "" {
+			spec.EnvoyFilter.Name = "epp-ext-proc"
+		}
+		if spec.EnvoyFilter.ClusterType == "" {
+			spec.EnvoyFilter.ClusterType = EnvoyFilterClusterStatic
+		}
+		if spec.EnvoyFilter.ProcessingMode == nil {
+			spec.EnvoyFilter.ProcessingMode = &ExtProcProcessingMode{}
+		}
+		defaultExtProcProcessingMode(spec.EnvoyFilter.ProcessingMode)
+		if spec.EnvoyFilter.Timeout == nil {
+			spec.EnvoyFilter.Timeout = &metav1.Duration{Duration: 2 * time.Second}
+		}
+		if spec.EnvoyFilter.FailureModeAllow == nil {
+			failureModeAllow := true
+			spec.EnvoyFilter.FailureModeAllow = &failureModeAllow
+		}
+		if len(spec.EnvoyFilter.WorkloadSelector) == 0 && spec.Gateway != nil {
+			spec.EnvoyFilter.WorkloadSelector = map[string]string{
+				"gateway.networking.k8s.io/gateway-name": spec.Gateway.Name,
+			}
+		}
+	}
+}
+
+// BindSimulator fills the proxy Service and InferencePool fields that
+// spec.simulatorRef leaves unset from the SimulatorDeployment it references:
+// both select its decode pods, or the pods of its Deployment without stages,
+// on their port. simDep is expected to be defaulted.
+func (r *SchedulerInstall) BindSimulator(simDep *SimulatorDeployment) {
+	spec := &r.Spec
+	port := simDep.Spec.Service.Port
+	if simDep.Spec.Decode != nil && simDep.Spec.Decode.Enabled {
+		port = simDep.Spec.Decode.Port
+	}
+	if spec.ProxyService.TargetPort == 0 {
+		spec.ProxyService.TargetPort = port
+	}
+	if len(spec.ProxyService.Selector) == 0 {
+		spec.ProxyService.Selector = map[string]string{
+			"llm-d.ai/role":             "decode",
+			"llm-d.ai/inferenceServing": "true",
+			"app.kubernetes.io/name":    simDep.Name,
+		}
+	}
+	if pool := spec.InferencePool; pool != nil {
+		if len(pool.Selector) == 0 {
+			pool.Selector = spec.ProxyService.Selector
+		}
+		if len(pool.TargetPorts) == 0 {
+			pool.TargetPorts = []int32{spec.ProxyService.TargetPort}
+		}
+	}
+}
+
+// Validate checks the namespaces, ports, routing rules and canary of a SchedulerInstall
+func (r *SchedulerInstall) Validate() error {
+	var allErrs field.ErrorList
+	spec := &r.Spec
+	specPath := field.NewPath("spec")
+
+	if spec.SimulatorNamespace == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("simulatorNamespace"), "namespace of the simulator backends is required"))
+	}
+	allErrs = append(allErrs, validateNamespace(spec.SimulatorNamespace, specPath.Child("simulatorNamespace"))...)
+	allErrs = append(allErrs, validateNamespace(spec.SchedulerNamespace, specPath.Child("schedulerNamespace"))...)
+
+	if spec.SimulatorRef != nil {
+		refPath := specPath.Child("simulatorRef")
+		if spec.SimulatorRef.Name == "" {
+			allErrs = append(allErrs, field.Required(refPath.Child("name"), "name of the SimulatorDeployment is required"))
+		} else {
+			for _, msg := range validation.IsDNS1123Subdomain(spec.SimulatorRef.Name) {
+				allErrs = append(allErrs, field.Invalid(refPath.Child("name"), spec.SimulatorRef.Name, msg))
+			}
+		}
+		if spec.SimulatorRef.Namespace != spec.SimulatorNamespace {
+			allErrs = append(allErrs, field.Invalid(refPath.Child("namespace"), spec.SimulatorRef.Namespace, "must match spec.simulatorNamespace"))
+		}
+	}
+
+	proxyPath := specPath.Child("proxyService")
+	allErrs = append(allErrs, validateDNSLabel(spec.ProxyService.Name, proxyPath.Child("name"))...)
+	allErrs = append(allErrs, validatePort(spec.ProxyService.Port, proxyPath.Child("port"))...)
+	// Without a simulatorRef the target port is defaulted, with one it may be left to the simulator
+	if spec.SimulatorRef == nil || spec.ProxyService.TargetPort != 0 {
+		allErrs = append(allErrs, validatePort(spec.ProxyService.TargetPort, proxyPath.Child("targetPort"))...)
+	}
+
+	if spec.EPP != nil {
+		eppPath := specPath.Child("epp")
+		allErrs = append(allErrs, validateDNSLabel(spec.EPP.Name, eppPath.Child("name"))...)
+		allErrs = append(allErrs, validatePort(spec.EPP.Port, eppPath.Child("port"))...)
+		allErrs = append(allErrs, validateNamespace(spec.EPP.PoolNamespace, eppPath.Child("poolNamespace"))...)
+		_, configErrs := BuildEndpointPickerConfig(spec.EPP.ConfigProfile, spec.EPP.Plugins, spec.EPP.SchedulingProfiles, eppPath)
+		allErrs = append(allErrs, configErrs...)
+	}
+
+	if spec.Gateway != nil {
+		allErrs = append(allErrs, validatePort(spec.Gateway.ListenerPort, specPath.Child("gateway", "listenerPort"))...)
+	}
+
+	if spec.InferencePool != nil && spec.InferencePool.Enabled {
+		poolPath := specPath.Child("inferencePool")
+		if spec.InferencePool.Name == "" {
+			allErrs = append(allErrs, field.Required(poolPath.Child("name"), "set inferencePool.name or epp.poolName"))
+		}
+		allErrs = append(allErrs, validateNamespace(spec.InferencePool.Namespace, poolPath.Child("namespace"))...)
+		for i, port := range spec.InferencePool.TargetPorts {
+			allErrs = append(allErrs, validatePort(port, poolPath.Child("targetPorts").Index(i))...)
+		}
+		if spec.InferencePool.EndpointPickerRef == nil || spec.InferencePool.EndpointPickerRef.Name == "" {
+			allErrs = append(allErrs, field.Required(poolPath.Child("endpointPickerRef", "name"), "set endpointPickerRef.name or configure epp"))
+		} else {
+			allErrs = append(allErrs, validatePort(spec.InferencePool.EndpointPickerRef.Port, poolPath.Child("endpointPickerRef", "port"))...)
+		}
+	}
+
+	if spec.Routing != nil && spec.Routing.Enabled {
+		routingPath := specPath.Child("routing")
+		if spec.Routing.ParentGateway.Name == "" {
+			allErrs = append(allErrs, field.Required(routingPath.Child("parentGateway", "name"), "set parentGateway.name or configure gateway"))
+		}
+		if len(spec.Routing.Rules) == 0 && spec.Routing.BackendType == "InferencePool" {
+			if spec.Routing.InferencePool == nil || spec.Routing.InferencePool.Name == "" {
+				allErrs = append(allErrs, field.Required(routingPath.Child("inferencePool", "name"), "required when backendType is InferencePool"))
+			} else if spec.Routing.InferencePool.Port != 0 {
+				allErrs = append(allErrs, validatePort(spec.Routing.InferencePool.Port, routingPath.Child("inferencePool", "port"))...)
+			}
+		}
+		allErrs = append(allErrs, ValidateHTTPRouteRules(spec.Routing.Rules, routingPath.Child("rules"))...)
+	}
+
+	if spec.Canary != nil && spec.Canary.Enabled {
+		canaryPath := specPath.Child("canary")
+		if spec.Routing == nil || !spec.Routing.Enabled {
+			allErrs = append(allErrs, field.Invalid(canaryPath.Child("enabled"), true, "requires routing.enabled=true"))
+		} else if len(spec.Routing.Rules) > 0 {
+			allErrs = append(allErrs, field.Forbidden(canaryPath, "cannot be combined with routing.rules"))
+		}
+		allErrs = append(allErrs, ValidateCanaryConfig(spec.Canary, canaryPath)...)
+	}
+
+	if spec.EnvoyFilter != nil && spec.EnvoyFilter.Enabled {
+		envoyFilterPath := specPath.Child("envoyFilter")
+		if spec.EPP == nil || !spec.EPP.Enabled {
+			allErrs = append(allErrs, field.Invalid(envoyFilterPath.Child("enabled"), true, "requires epp.enabled=true"))
+		}
+		if len(spec.EnvoyFilter.WorkloadSelector) == 0 {
+			allErrs = append(allErrs, field.Required(envoyFilterPath.Child("workloadSelector"), "set workloadSelector or configure gateway for the default selector"))
+		}
+		allErrs = append(allErrs, ValidateEnvoyFilterConfig(spec.EnvoyFilter, envoyFilterPath)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("SchedulerInstall").GroupKind(), r.Name, allErrs)
+}
+
+// defaultHTTPRouteRule fills the match types and the backend kind, namespace and weight of a routing rule
+func defaultHTTPRouteRule(rule *HTTPRouteRule, namespace string) {
+	for i := range rule.Matches {
+		match := &rule.Matches[i]
+		if match.Path != nil {
+			if match.Path.Type == "" {
+				match.Path.Type = "PathPrefix"
+			}
+			if match.Path.Value == "" {
+				match.Path.Value = "/"
+			}
+		}
+		for j := range match.Headers {
+			if match.Headers[j].Type == "" {
+				match.Headers[j].Type = "Exact"
+			}
+		}
+		for j := range match.QueryParams {
+			if match.QueryParams[j].Type == "" {
+				match.QueryParams[j].Type = "Exact"
+			}
+		}
+	}
+	for i := range rule.BackendRefs {
+		defaultHTTPBackendRef(&rule.BackendRefs[i], namespace)
+	}
+}
+
+func defaultHTTPBackendRef(backend *HTTPBackendRef, namespace string) {
+	if backend.Kind == "" {
+		backend.Kind = "Service"
+	}
+	if backend.Namespace == "" {
+		backend.Namespace = namespace
+	}
+	if backend.Weight == nil {
+		weight := int32(1)
+		backend.Weight = &weight
+	}
+}
+
+// defaultExtProcProcessingMode sends the buffered request body and nothing else to the EPP
+func defaultExtProcProcessingMode(mode *ExtProcProcessingMode) {
+	if mode.RequestHeaderMode == "" {
+		mode.RequestHeaderMode = "SKIP"
+	}
+	if mode.RequestBodyMode == "" {
+		mode.RequestBodyMode = "BUFFERED"
+	}
+	if mode.ResponseHeaderMode == "" {
+		mode.ResponseHeaderMode = "SKIP"
+	}
+	if mode.ResponseBodyMode == "" {
+		mode.ResponseBodyMode = "NONE"
+	}
+}
+
+// ValidateHTTPRouteRules checks the routing rules of a defaulted spec
+func ValidateHTTPRouteRules(rules []HTTPRouteRule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, rule := range rules {
+		rulePath := fldPath.Index(i)
+		for j, match := range rule.Matches {
+			matchPath := rulePath.Child("matches").Index(j)
+			if match.Path != nil && match.Path.Type != "RegularExpression" && !strings.HasPrefix(match.Path.Value, "/") {
+				allErrs = append(allErrs, field.Invalid(matchPath.Child("path", "value"), match.Path.Value, "must start with /"))
+			}
+			for k, header := range match.Headers {
+				if header.Name == "" {
+					allErrs = append(allErrs, field.Required(matchPath.Child("headers").Index(k).Child("name"), ""))
+				}
+			}
+			for k, param := range match.QueryParams {
+				if param.Name == "" {
+					allErrs = append(allErrs, field.Required(matchPath.Child("queryParams").Index(k).Child("name"), ""))
+				}
+			}
+		}
+		if len(rule.BackendRefs) == 0 {
+			allErrs = append(allErrs, field.Required(rulePath.Child("backendRefs"), "every rule needs at least one backend"))
+		}
+		for j, backend := range rule.BackendRefs {
+			allErrs = append(allErrs, validateHTTPBackendRef(backend, rulePath.Child("backendRefs").Index(j))...)
+		}
+	}
+	return allErrs
+}
+
+func validateHTTPBackendRef(backend HTTPBackendRef, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateDNSLabel(backend.Name, fldPath.Child("name"))...)
+	allErrs = append(allErrs, validateNamespace(backend.Namespace, fldPath.Child("namespace"))...)
+	// InferencePool backends may omit the port, Services may not
+	if backend.Kind == "Service" || backend.Port != 0 {
+		allErrs = append(allErrs, validatePort(backend.Port, fldPath.Child("port"))...)
+	}
+	if backend.Weight != nil && (*backend.Weight < 0 || *backend.Weight > 1000000) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("weight"), *backend.Weight, "must be between 0 and 1000000"))
+	}
+	return allErrs
+}
+
+// ValidateCanaryConfig checks the backends and the step schedule of a defaulted canary
+func ValidateCanaryConfig(canary *SchedulerCanaryConfig, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateHTTPBackendRef(canary.Stable, fldPath.Child("stable"))...)
+	allErrs = append(allErrs, validateHTTPBackendRef(canary.Candidate, fldPath.Child("candidate"))...)
+	if len(canary.Steps) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("steps"), ""))
+	}
+	for i, step := range canary.Steps {
+		switch {
+		case step < 1 || step > 100:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("steps").Index(i), step, "must be between 1 and 100"))
+		case i > 0 && step <= canary.Steps[i-1]:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("steps").Index(i), step, "steps must increase"))
+		}
+	}
+	if canary.StepDuration != nil && canary.StepDuration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("stepDuration"), canary.StepDuration.Duration.String(), "must be positive"))
+	}
+	if canary.Analysis != nil {
+		allErrs = append(allErrs, validateCanaryAnalysis(canary.Analysis, fldPath.Child("analysis"))...)
+	}
+	return allErrs
+}
+
+func validateCanaryAnalysis(analysis *CanaryAnalysis, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, source := range []struct {
+		name   string
+		source CanaryMetricsSource
+	}{{"stable", analysis.Stable}, {"candidate", analysis.Candidate}} {
+		urlsPath := fldPath.Child(source.name, "urls")
+		if len(source.source.URLs) == 0 {
+			allErrs = append(allErrs, field.Required(urlsPath, "at least one metrics endpoint is required"))
+		}
+		for i, raw := range source.source.URLs {
+			if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(urlsPath.Index(i), raw, "must be an http or https URL"))
+			}
+		}
+	}
+	if analysis.Metrics != nil && analysis.Metrics.Requests == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("metrics", "requests"), "the request counter is needed to compare the backends"))
+	}
+	if analysis.MaxErrorRateIncrease != nil && *analysis.MaxErrorRateIncrease < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxErrorRateIncrease"), *analysis.MaxErrorRateIncrease, "must not be negative"))
+	}
+	if analysis.MaxLatencyIncrease != nil && *analysis.MaxLatencyIncrease < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxLatencyIncrease"), *analysis.MaxLatencyIncrease, "must not be negative"))
+	}
+	if analysis.MinRequests < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minRequests"), analysis.MinRequests, "must be at least 1"))
+	}
+	return allErrs
+}
+
+// ValidateEnvoyFilterConfig rejects ext_proc settings the EPP streaming server
+// cannot serve. The EPP schedules a request once it has seen the whole body and
+// only then answers the request headers, and it answers response body chunks
+// only after the response headers, so some mode combinations stall every request.
+func ValidateEnvoyFilterConfig(config *SchedulerEnvoyFilterConfig, fldPath *field.Path) field.ErrorList {
+	return validateExtProc(config.ProcessingMode, config.Timeout, config.MessageTimeout, fldPath)
+}
+
+// validateExtProc checks the ext_proc settings shared by the EnvoyFilter and
+// the simulator gateway, see ValidateEnvoyFilterConfig
+func validateExtProc(mode *ExtProcProcessingMode, timeout, messageTimeout *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if mode != nil {
+		modePath := fldPath.Child("processingMode")
+		switch mode.RequestBodyMode {
+		case "NONE", "BUFFERED_PARTIAL":
+			allErrs = append(allErrs, field.Invalid(modePath.Child("requestBodyMode"), mode.RequestBodyMode, "the EPP needs the complete request body to schedule"))
+		}
+		if mode.RequestHeaderMode != "SKIP" && mode.RequestBodyMode != "FULL_DUPLEX_STREAMED" {
+			allErrs = append(allErrs, field.Invalid(modePath.Child("requestHeaderMode"), mode.RequestHeaderMode, "the EPP answers request headers only after the body, which requires requestBodyMode FULL_DUPLEX_STREAMED"))
+		}
+		if mode.ResponseBodyMode == "BUFFERED_PARTIAL" {
+			allErrs = append(allErrs, field.NotSupported(modePath.Child("responseBodyMode"), mode.ResponseBodyMode, []string{"NONE", "STREAMED", "BUFFERED", "FULL_DUPLEX_STREAMED"}))
+		}
+		if mode.ResponseBodyMode != "" && mode.ResponseBodyMode != "NONE" && mode.ResponseHeaderMode == "SKIP" {
+			allErrs = append(allErrs, field.Invalid(modePath.Child("responseHeaderMode"), mode.ResponseHeaderMode, "the EPP answers the response body only after the response headers"))
+		}
+	}
+	if timeout != nil && timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), timeout.Duration.String(), "must be positive"))
+	}
+	if messageTimeout != nil && messageTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("messageTimeout"), messageTimeout.Duration.String(), "must be positive"))
+	}
+	return allErrs
+}
@@ -0,0 +1,382 @@
+package v1alpha1
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newSchedulerInstall(name string) *SchedulerInstall {
+	return &SchedulerInstall{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: SchedulerInstallSpec{
+			SimulatorNamespace: "llm-d-sim",
+		},
+	}
+}
+
+func TestSchedulerInstallDefault(t *testing.T) {
+	install := newSchedulerInstall("defaults")
+	install.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+	install.Spec.Gateway = &SchedulerGatewayConfig{Enabled: true}
+	install.Spec.Routing = &SchedulerRoutingConfig{Enabled: true, BackendType: "InferencePool"}
+	install.Spec.InferencePool = &SchedulerInferencePoolConfig{Enabled: true, Name: "pool", Namespace: "llm-d-pool"}
+	install.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true}
+	install.Spec.ProxyService.Port = 9000
+	install.Default()
+
+	spec := install.Spec
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"schedulerNamespace", spec.SchedulerNamespace, "default"},
+		{"proxyService.targetPort", spec.ProxyService.TargetPort, int32(9000)},
+		{"epp.image", spec.EPP.Image, DefaultEPPImage},
+		{"epp.poolName", spec.EPP.PoolName, "pool"},
+		{"epp.poolNamespace", spec.EPP.PoolNamespace, "llm-d-pool"},
+		{"inferencePool.targetPorts[0]", spec.InferencePool.TargetPorts[0], int32(9000)},
+		{"inferencePool.endpointPickerRef.name", spec.InferencePool.EndpointPickerRef.Name, spec.EPP.Name},
+		{"inferencePool.endpointPickerRef.failureMode", spec.InferencePool.EndpointPickerRef.FailureMode, "FailClose"},
+		{"routing.parentGateway.name", spec.Routing.ParentGateway.Name, spec.Gateway.Name},
+		{"routing.inferencePool.name", spec.Routing.InferencePool.Name, "pool"},
+		{"routing.inferencePool.namespace", spec.Routing.InferencePool.Namespace, "llm-d-pool"},
+		{"envoyFilter.workloadSelector", spec.EnvoyFilter.WorkloadSelector["gateway.networking.k8s.io/gateway-name"], spec.Gateway.Name},
+		{"envoyFilter.clusterType", spec.EnvoyFilter.ClusterType, EnvoyFilterClusterStatic},
+		{"envoyFilter.processingMode", *spec.EnvoyFilter.ProcessingMode, ExtProcProcessingMode{RequestHeaderMode: "SKIP", RequestBodyMode: "BUFFERED", ResponseHeaderMode: "SKIP", ResponseBodyMode: "NONE"}},
+		{"envoyFilter.timeout", spec.EnvoyFilter.Timeout.Duration, 2 * time.Second},
+		{"envoyFilter.failureModeAllow", *spec.EnvoyFilter.FailureModeAllow, true},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	again := install.DeepCopy()
+	again.Default()
+	if !equalJSON(t, install, again) {
+		t.Errorf("Default is not idempotent")
+	}
+	if err := install.Validate(); err != nil {
+		t.Errorf("defaulted spec is invalid: %v", err)
+	}
+}
+
+func TestSchedulerInstallBindSimulator(t *testing.T) {
+	install := newSchedulerInstall("bound")
+	install.Spec.SimulatorNamespace = ""
+	install.Spec.SimulatorRef = &SimulatorReference{Name: "llm-sim-full"}
+	install.Spec.InferencePool = &SchedulerInferencePoolConfig{Enabled: true, Name: "pool"}
+	install.Default()
+
+	// The fields that follow the simulator are left unset until it is bound
+	if install.Spec.SimulatorRef.Namespace != "default" || install.Spec.SimulatorNamespace != "default" {
+		t.Errorf("simulatorRef.namespace = %q, simulatorNamespace = %q, want the install namespace",
+			install.Spec.SimulatorRef.Namespace, install.Spec.SimulatorNamespace)
+	}
+	if install.Spec.ProxyService.TargetPort != 0 || install.Spec.ProxyService.Selector != nil || install.Spec.InferencePool.TargetPorts != nil {
+		t.Errorf("proxyService = %+v, inferencePool.targetPorts = %v, want them left to the simulator",
+			install.Spec.ProxyService, install.Spec.InferencePool.TargetPorts)
+	}
+
+	simDep := newSimulatorDeployment("llm-sim-full")
+	simDep.Spec.Decode = &StageConfig{Enabled: true, Port: 8300}
+	simDep.Default()
+	install.BindSimulator(simDep)
+
+	want := map[string]string{
+		"llm-d.ai/role":             "decode",
+		"llm-d.ai/inferenceServing": "true",
+		"app.kubernetes.io/name":    "llm-sim-full",
+	}
+	if !reflect.DeepEqual(install.Spec.ProxyService.Selector, want) || !reflect.DeepEqual(install.Spec.InferencePool.Selector, want) {
+		t.Errorf("selectors = %v and %v, want %v", install.Spec.ProxyService.Selector, install.Spec.InferencePool.Selector, want)
+	}
+	if install.Spec.ProxyService.TargetPort != 8300 || !reflect.DeepEqual(install.Spec.InferencePool.TargetPorts, []int32{8300}) {
+		t.Errorf("target ports = %d and %v, want the decode port 8300", install.Spec.ProxyService.TargetPort, install.Spec.InferencePool.TargetPorts)
+	}
+
+	// Values set in the spec win over the simulator
+	install = newSchedulerInstall("overridden")
+	install.Spec.SimulatorRef = &SimulatorReference{Name: "llm-sim-full"}
+	install.Spec.ProxyService.TargetPort = 9000
+	install.Default()
+	install.BindSimulator(simDep)
+	if install.Spec.ProxyService.TargetPort != 9000 {
+		t.Errorf("proxyService.targetPort = %d, want 9000 from the spec", install.Spec.ProxyService.TargetPort)
+	}
+}
+
+func TestSchedulerInstallValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*SchedulerInstall)
+		wantErr string
+	}{
+		{
+			name:    "missing simulatorNamespace",
+			mutate:  func(i *SchedulerInstall) { i.Spec.SimulatorNamespace = "" },
+			wantErr: "spec.simulatorNamespace",
+		},
+		{
+			name: "envoyFilter without epp",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true, WorkloadSelector: map[string]string{"app": "gw"}}
+			},
+			wantErr: "requires epp.enabled=true",
+		},
+		{
+			name: "envoyFilter without selector",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+				i.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true}
+			},
+			wantErr: "spec.envoyFilter.workloadSelector",
+		},
+		{
+			name: "InferencePool backend without pool",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true, BackendType: "InferencePool", ParentGateway: GatewayRef{Name: "gw"}}
+			},
+			wantErr: "spec.routing.inferencePool.name",
+		},
+		{
+			name: "routing without gateway",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true}
+			},
+			wantErr: "spec.routing.parentGateway.name",
+		},
+		{
+			name: "inferencePool without epp",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.InferencePool = &SchedulerInferencePoolConfig{Enabled: true, Name: "pool"}
+			},
+			wantErr: "spec.inferencePool.endpointPickerRef.name",
+		},
+		{
+			name: "envoyFilter request headers without full duplex body",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+				i.Spec.Gateway = &SchedulerGatewayConfig{Enabled: true}
+				i.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true, ProcessingMode: &ExtProcProcessingMode{RequestHeaderMode: "SEND"}}
+			},
+			wantErr: "spec.envoyFilter.processingMode.requestHeaderMode",
+		},
+		{
+			name: "envoyFilter without request body",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+				i.Spec.Gateway = &SchedulerGatewayConfig{Enabled: true}
+				i.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true, ProcessingMode: &ExtProcProcessingMode{RequestBodyMode: "NONE"}}
+			},
+			wantErr: "spec.envoyFilter.processingMode.requestBodyMode",
+		},
+		{
+			name: "envoyFilter response body without response headers",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+				i.Spec.Gateway = &SchedulerGatewayConfig{Enabled: true}
+				i.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true, ProcessingMode: &ExtProcProcessingMode{ResponseBodyMode: "STREAMED"}}
+			},
+			wantErr: "spec.envoyFilter.processingMode.responseHeaderMode",
+		},
+		{
+			name: "envoyFilter negative message timeout",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+				i.Spec.Gateway = &SchedulerGatewayConfig{Enabled: true}
+				i.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true, MessageTimeout: &metav1.Duration{Duration: -time.Second}}
+			},
+			wantErr: "spec.envoyFilter.messageTimeout",
+		},
+		{
+			name: "envoyFilter full duplex streaming",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+				i.Spec.Gateway = &SchedulerGatewayConfig{Enabled: true}
+				i.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true, ProcessingMode: &ExtProcProcessingMode{
+					RequestHeaderMode:  "SEND",
+					RequestBodyMode:    "FULL_DUPLEX_STREAMED",
+					ResponseHeaderMode: "SEND",
+					ResponseBodyMode:   "FULL_DUPLEX_STREAMED",
+				}}
+			},
+		},
+		{
+			name: "routing rule Service backend without port",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true, ParentGateway: GatewayRef{Name: "gw"}, Rules: []HTTPRouteRule{{
+					BackendRefs: []HTTPBackendRef{{Name: "vllm-fs-proxy"}},
+				}}}
+			},
+			wantErr: "spec.routing.rules[0].backendRefs[0].port",
+		},
+		{
+			name: "routing rule relative path",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true, ParentGateway: GatewayRef{Name: "gw"}, Rules: []HTTPRouteRule{{
+					Matches:     []HTTPRouteMatch{{Path: &HTTPPathMatch{Value: "v1"}}},
+					BackendRefs: []HTTPBackendRef{{Kind: "InferencePool", Name: "pool"}},
+				}}}
+			},
+			wantErr: "spec.routing.rules[0].matches[0].path.value",
+		},
+		{
+			name: "routing rules replace the InferencePool backend",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true, BackendType: "InferencePool", ParentGateway: GatewayRef{Name: "gw"}, Rules: []HTTPRouteRule{{
+					Matches:     []HTTPRouteMatch{{Headers: []HTTPValueMatch{{Name: "x-kv-backend", Value: "fs"}}}},
+					BackendRefs: []HTTPBackendRef{{Name: "vllm-fs-proxy", Namespace: "llm-d-vllm", Port: 8080}},
+				}}}
+			},
+		},
+		{
+			name: "canary without routing",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Canary = &SchedulerCanaryConfig{Enabled: true,
+					Stable:    HTTPBackendRef{Kind: "InferencePool", Name: "stable"},
+					Candidate: HTTPBackendRef{Kind: "InferencePool", Name: "candidate"},
+				}
+			},
+			wantErr: "spec.canary.enabled",
+		},
+		{
+			name: "canary with routing rules",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true, ParentGateway: GatewayRef{Name: "gw"}, Rules: []HTTPRouteRule{{
+					BackendRefs: []HTTPBackendRef{{Kind: "InferencePool", Name: "pool"}},
+				}}}
+				i.Spec.Canary = &SchedulerCanaryConfig{Enabled: true,
+					Stable:    HTTPBackendRef{Kind: "InferencePool", Name: "stable"},
+					Candidate: HTTPBackendRef{Kind: "InferencePool", Name: "candidate"},
+				}
+			},
+			wantErr: "cannot be combined with routing.rules",
+		},
+		{
+			name: "canary steps out of order",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true, ParentGateway: GatewayRef{Name: "gw"}}
+				i.Spec.Canary = &SchedulerCanaryConfig{Enabled: true,
+					Stable:    HTTPBackendRef{Name: "proxy-stable", Port: 8200},
+					Candidate: HTTPBackendRef{Name: "proxy-candidate", Port: 8200},
+					Steps:     []int32{50, 25, 100},
+				}
+			},
+			wantErr: "spec.canary.steps[1]",
+		},
+		{
+			name: "canary analysis endpoint without scheme",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true, ParentGateway: GatewayRef{Name: "gw"}}
+				i.Spec.Canary = &SchedulerCanaryConfig{Enabled: true,
+					Stable:    HTTPBackendRef{Name: "proxy-stable", Port: 8200},
+					Candidate: HTTPBackendRef{Name: "proxy-candidate", Port: 8200},
+					Analysis: &CanaryAnalysis{
+						Stable:    CanaryMetricsSource{URLs: []string{"http://proxy-stable.llm-d-sim:8200/metrics"}},
+						Candidate: CanaryMetricsSource{URLs: []string{"proxy-candidate:8200/metrics"}},
+					},
+				}
+			},
+			wantErr: "spec.canary.analysis.candidate.urls[0]",
+		},
+		{
+			name:    "invalid port",
+			mutate:  func(i *SchedulerInstall) { i.Spec.ProxyService.Port = 70000 },
+			wantErr: "spec.proxyService.port",
+		},
+		{
+			name: "simulatorRef in another namespace than simulatorNamespace",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.SimulatorRef = &SimulatorReference{Name: "llm-sim-full", Namespace: "other"}
+			},
+			wantErr: "spec.simulatorRef.namespace",
+		},
+		{
+			name:    "simulatorRef without a name",
+			mutate:  func(i *SchedulerInstall) { i.Spec.SimulatorRef = &SimulatorReference{} },
+			wantErr: "spec.simulatorRef.name",
+		},
+		{
+			name: "simulatorRef replaces simulatorNamespace",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.SimulatorNamespace = ""
+				i.Spec.SimulatorRef = &SimulatorReference{Name: "llm-sim-full", Namespace: "llm-d-sim"}
+				i.Spec.InferencePool = &SchedulerInferencePoolConfig{Enabled: true, Name: "pool"}
+				i.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+			},
+		},
+		{
+			name: "valid",
+			mutate: func(i *SchedulerInstall) {
+				i.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+				i.Spec.Gateway = &SchedulerGatewayConfig{Enabled: true}
+				i.Spec.Routing = &SchedulerRoutingConfig{Enabled: true}
+				i.Spec.EnvoyFilter = &SchedulerEnvoyFilterConfig{Enabled: true}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			install := newSchedulerInstall("validate")
+			tt.mutate(install)
+			install.Default()
+			err := install.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) {
+				t.Fatalf("expected an Invalid error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedulerInstallWebhook(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	invalid := newSchedulerInstall("webhook-invalid")
+	invalid.Spec.SimulatorNamespace = ""
+	if err := k8sClient.Create(ctx, invalid); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected create to be rejected as Invalid, got %v", err)
+	}
+
+	install := newSchedulerInstall("webhook-defaults")
+	install.Spec.EPP = &SchedulerEPPConfig{Enabled: true}
+	install.Spec.InferencePool = &SchedulerInferencePoolConfig{Enabled: true, Name: "pool"}
+	if err := k8sClient.Create(ctx, install); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	t.Cleanup(func() { _ = k8sClient.Delete(context.Background(), install) })
+
+	stored := &SchedulerInstall{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: install.Name, Namespace: install.Namespace}, stored); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stored.Spec.SchedulerNamespace != "default" {
+		t.Errorf("schedulerNamespace = %q, want persisted default %q", stored.Spec.SchedulerNamespace, "default")
+	}
+	if stored.Spec.EPP.PoolName != "pool" {
+		t.Errorf("epp.poolName = %q, want %q from inferencePool.name", stored.Spec.EPP.PoolName, "pool")
+	}
+
+	stored.Spec.SimulatorNamespace = "elsewhere"
+	if err := k8sClient.Update(ctx, stored); !apierrors.IsInvalid(err) {
+		t.Errorf("expected simulatorNamespace change to be rejected, got %v", err)
+	}
+}
@@ -5,15 +5,25 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// SimulatorPoolLabel carries the pool name on the pods of a stage pool
+const SimulatorPoolLabel = "sim.llm-d.io/pool"
+
 // SimulatorDeploymentSpec defines the desired state of SimulatorDeployment
 type SimulatorDeploymentSpec struct {
+	// NamePrefix starts the names of the Deployments, Services and ConfigMaps
+	// created for this SimulatorDeployment, e.g. <prefix>-decode and <prefix>-epp
+	// Defaults to the SimulatorDeployment name
+	// +kubebuilder:validation:MaxLength=30
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	NamePrefix string `json:"namePrefix,omitempty"`
+
 	// Replicas is the number of simulator pods to run (deprecated, use Prefill/Decode replicas)
 	// +kubebuilder:default=2
 	// +kubebuilder:validation:Minimum=1
 	Replicas int32 `json:"replicas,omitempty"`
 
 	// Image is the container image for the simulator
-	// +kubebuilder:default="ghcr.io/llm-d/llm-d-simulator:latest"
+	// +kubebuilder:default="docker.io/library/llm-d-simulator:local"
 	Image string `json:"image,omitempty"`
 
 	// LogVerbosity sets klog verbosity level for simulator pods
@@ -47,8 +57,9 @@ type SimulatorDeploymentSpec struct {
 
 // ServiceConfig defines service configuration
 type ServiceConfig struct {
-	// Name of the service
-	// +kubebuilder:default="gaie-inference-scheduling-proxy"
+	// Name of the Service of the single Deployment run without prefill and
+	// decode stages. Defaults to <namePrefix>-proxy, or to the fixed
+	// gaie-inference-scheduling-proxy of earlier releases while those names are adopted
 	Name string `json:"name,omitempty"`
 
 	// Port for the service
@@ -124,8 +135,24 @@ type EPPConfig struct {
 	// Args are additional arguments to pass to the EPP container
 	Args []string `json:"args,omitempty"`
 
+	// Env are additional environment variables for the EPP container, e.g.
+	// SCHEDULING_DECISIONS_ADDR to serve the scoring decisions of the EPP
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
 	// Resources defines the resource requirements for EPP pods
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Plugins override or extend the plugins of the default preset
+	Plugins []EPPPlugin `json:"plugins,omitempty"`
+
+	// SchedulingProfiles override or extend the scheduling profiles of the default preset
+	SchedulingProfiles []EPPSchedulingProfile `json:"schedulingProfiles,omitempty"`
+	// RestartPolicy controls when the EPP pods are restarted: OnConfigChange
+	// rolls them when the rendered plugin config changes, Manual only through
+	// the sim.llm-d.io/restart-epp annotation
+	// +kubebuilder:validation:Enum=OnConfigChange;Manual
+	// +kubebuilder:default="OnConfigChange"
+	RestartPolicy string `json:"restartPolicy,omitempty"`
 }
 
 // StageConfig defines configuration for prefill or decode stage
@@ -139,6 +166,7 @@ type StageConfig struct {
 	Replicas int32 `json:"replicas,omitempty"`
 
 	// Image is the container image for this stage
+	// Defaults to spec.image
 	Image string `json:"image,omitempty"`
 
 	// Port for the service
@@ -146,16 +174,111 @@ type StageConfig struct {
 	Port int32 `json:"port,omitempty"`
 
 	// LogVerbosity sets klog verbosity level for this stage
-	// +kubebuilder:default=5
+	// Defaults to spec.logVerbosity
 	LogVerbosity int32 `json:"logVerbosity,omitempty"`
 
 	// Resources defines the resource requirements
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// Behavior models how the simulator answers requests; it is rendered into
+	// simulator flags ahead of Args, so Args can still override any of them
+	Behavior *SimulatorBehavior `json:"behavior,omitempty"`
+
 	// Args are additional arguments to pass to the container
 	Args []string `json:"args,omitempty"`
+
+	// Pools split the stage into groups of differently configured pods. Each
+	// pool is its own Deployment behind the shared stage Service; when set,
+	// Replicas of the stage is ignored.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	Pools []SimulatorPool `json:"pools,omitempty"`
 }
 
+// SimulatorPool is a group of identical simulator pods within a stage
+type SimulatorPool struct {
+	// Name of the pool, appended to the stage Deployment name
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Replicas is the number of pods of this pool; 0 scales the pool down
+	// while keeping its Deployment
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Image is the container image for this pool
+	// Defaults to the stage image
+	Image string `json:"image,omitempty"`
+
+	// Resources defines the resource requirements
+	// Defaults to the stage resources
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Behavior of the pool pods; unset fields default to the stage behavior
+	Behavior *SimulatorBehavior `json:"behavior,omitempty"`
+
+	// Args are appended to the stage args
+	Args []string `json:"args,omitempty"`
+
+	// Labels are added to the pool pods, e.g. to tell pools apart in EPP
+	// metrics; they cannot replace the stage role labels
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// SimulatorBehavior is the modelled behavior of the simulator pods of a stage
+type SimulatorBehavior struct {
+	// Mode is random to generate text or echo to repeat the prompt
+	// +kubebuilder:validation:Enum=random;echo
+	// +kubebuilder:default="random"
+	Mode string `json:"mode,omitempty"`
+
+	// Model is the model name the simulator serves
+	// +kubebuilder:default="random"
+	Model string `json:"model,omitempty"`
+
+	// ServedModelNames are the names accepted in requests
+	// Defaults to model
+	ServedModelNames []string `json:"servedModelNames,omitempty"`
+
+	// TimeToFirstToken is the latency until the first token, in whole milliseconds
+	TimeToFirstToken *metav1.Duration `json:"timeToFirstToken,omitempty"`
+
+	// TimeToFirstTokenJitter is the standard deviation of TimeToFirstToken,
+	// at most 30% of it
+	TimeToFirstTokenJitter *metav1.Duration `json:"timeToFirstTokenJitter,omitempty"`
+
+	// InterTokenLatency is the latency between tokens, in whole milliseconds
+	InterTokenLatency *metav1.Duration `json:"interTokenLatency,omitempty"`
+
+	// InterTokenLatencyJitter is the standard deviation of InterTokenLatency,
+	// at most 30% of it
+	InterTokenLatencyJitter *metav1.Duration `json:"interTokenLatencyJitter,omitempty"`
+
+	// KVCacheSize is the number of KV-cache blocks of each pod
+	// +kubebuilder:validation:Minimum=1
+	KVCacheSize *int32 `json:"kvCacheSize,omitempty"`
+
+	// MaxNumSeqs is the number of sequences each pod processes concurrently
+	// +kubebuilder:validation:Minimum=1
+	MaxNumSeqs *int32 `json:"maxNumSeqs,omitempty"`
+
+	// FailureInjectionRate is the percentage of requests answered with an error
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	FailureInjectionRate *int32 `json:"failureInjectionRate,omitempty"`
+
+	// FailureTypes limits the injected errors to these types; all types are
+	// injected when empty
+	FailureTypes []SimulatorFailureType `json:"failureTypes,omitempty"`
+}
+
+// SimulatorFailureType is an error the simulator can inject
+// +kubebuilder:validation:Enum=rate_limit;invalid_api_key;context_length;server_error;invalid_request;model_not_found
+type SimulatorFailureType string
+
 // InferenceGatewayConfig defines inference gateway configuration
 type InferenceGatewayConfig struct {
 	// Enabled determines if inference gateways should be deployed
@@ -189,6 +312,40 @@ type GatewayInstanceConfig struct {
 
 	// Resources defines the resource requirements
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// AdminPort is the Envoy admin port, serving /ready and /stats/prometheus;
+	// it is also exposed on the gateway Service (standard gateway only)
+	// +kubebuilder:default=19000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	AdminPort int32 `json:"adminPort,omitempty"`
+
+	// ExtProc lets the EPP of this SimulatorDeployment pick the endpoint of
+	// every request (standard gateway only)
+	ExtProc *GatewayExtProcConfig `json:"extProc,omitempty"`
+}
+
+// GatewayExtProcConfig inserts an ext_proc filter into the gateway bootstrap
+// that asks the EPP for the destination endpoint
+type GatewayExtProcConfig struct {
+	// Enabled inserts the filter; it requires spec.epp to be enabled
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ProcessingMode selects which parts of requests and responses Envoy sends to the EPP
+	ProcessingMode *ExtProcProcessingMode `json:"processingMode,omitempty"`
+
+	// Timeout of the gRPC stream to the EPP
+	// +kubebuilder:default="2s"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// MessageTimeout is how long Envoy waits for the EPP to answer each message
+	// Defaults to the Envoy default (200ms) when not set
+	MessageTimeout *metav1.Duration `json:"messageTimeout,omitempty"`
+
+	// FailureModeAllow lets requests through unscheduled when the EPP fails
+	// Set to false to fail closed
+	// +kubebuilder:default=true
+	FailureModeAllow *bool `json:"failureModeAllow,omitempty"`
 }
 
 // SimulatorDeploymentStatus defines the observed state of SimulatorDeployment
@@ -205,15 +362,85 @@ type SimulatorDeploymentStatus struct {
 	// GatewayURL is the external URL for the gateway
 	GatewayURL string `json:"gatewayURL,omitempty"`
 
+	// NamePrefix is the prefix of the names of the managed objects; it is
+	// empty while the objects created under the fixed names of earlier
+	// releases are kept
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// Prefill reports the prefill stage status
+	Prefill *ComponentStatus `json:"prefill,omitempty"`
+
+	// Decode reports the decode stage (or legacy deployment) status
+	Decode *ComponentStatus `json:"decode,omitempty"`
+
+	// EPP reports the Endpoint Picker status
+	EPP *ComponentStatus `json:"epp,omitempty"`
+
+	// Gateway reports the standard inference gateway status
+	Gateway *ComponentStatus `json:"gateway,omitempty"`
+
+	// IstioGateway reports the Istio inference gateway status
+	IstioGateway *ComponentStatus `json:"istioGateway,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ComponentStatus defines the observed state of a Deployment-backed component
+type ComponentStatus struct {
+	// DeploymentName is the Deployment backing this component
+	DeploymentName string `json:"deploymentName,omitempty"`
+
+	// Replicas is the desired number of replicas
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of ready replicas
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Endpoints lists the cluster endpoints of the component Service
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// URL is the resolved URL for gateway components
+	URL string `json:"url,omitempty"`
+
+	// Behavior is the simulator behavior the stage pods run with, defaults included
+	Behavior *SimulatorBehavior `json:"behavior,omitempty"`
+
+	// Pools reports each pool of a stage; Replicas and ReadyReplicas of the
+	// stage are their totals
+	Pools []PoolStatus `json:"pools,omitempty"`
+
+	// Conditions represent the latest available observations (Available, Progressing, Degraded)
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// PoolStatus reports one pool of a stage
+type PoolStatus struct {
+	// Name of the pool
+	Name string `json:"name"`
+
+	// DeploymentName is the Deployment backing the pool
+	DeploymentName string `json:"deploymentName,omitempty"`
+
+	// Replicas is the desired number of replicas
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of ready replicas
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Behavior is the simulator behavior the pool pods run with, defaults included
+	Behavior *SimulatorBehavior `json:"behavior,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=simdep
 // +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.replicas`
 // +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyReplicas`
+// +kubebuilder:printcolumn:name="Prefill",type=integer,JSONPath=`.status.prefill.readyReplicas`
+// +kubebuilder:printcolumn:name="Decode",type=integer,JSONPath=`.status.decode.readyReplicas`
+// +kubebuilder:printcolumn:name="EPP",type=integer,JSONPath=`.status.epp.readyReplicas`
+// +kubebuilder:printcolumn:name="Gateway",type=string,JSONPath=`.status.gatewayURL`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // SimulatorDeployment is the Schema for the simulatordeployments API
@@ -0,0 +1,472 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// MaxSimulatorNamePrefixLength keeps <prefix>-inference-gateway-istio and
+// <prefix>-<stage>-<pool> within the 63 characters of a Service name
+const MaxSimulatorNamePrefixLength = 30
+
+// Default images of the webhook and the controllers. The +kubebuilder:default
+// markers repeat them as literals; TestCRDImageDefaults keeps the two in sync.
+const (
+	DefaultSimulatorImage       = "docker.io/library/llm-d-simulator:local"
+	DefaultEPPImage             = "ghcr.io/llm-d/llm-d-inference-scheduler:v0.4.0"
+	DefaultStandardGatewayImage = "cr.kgateway.dev/kgateway-dev/envoy-wrapper:v2.1.1"
+	DefaultIstioGatewayImage    = "docker.io/istio/proxyv2:1.28.1"
+)
+
+// SetupWebhookWithManager registers the SimulatorDeployment defaulting and validating webhooks
+func (r *SimulatorDeployment) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&simulatorDeploymentWebhook{}).
+		WithValidator(&simulatorDeploymentWebhook{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-sim-llm-d-io-v1alpha1-simulatordeployment,mutating=true,failurePolicy=fail,sideEffects=None,groups=sim.llm-d.io,resources=simulatordeployments,verbs=create;update,versions=v1alpha1,name=msimulatordeployment.sim.llm-d.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-sim-llm-d-io-v1alpha1-simulatordeployment,mutating=false,failurePolicy=fail,sideEffects=None,groups=sim.llm-d.io,resources=simulatordeployments,verbs=create;update,versions=v1alpha1,name=vsimulatordeployment.sim.llm-d.io,admissionReviewVersions=v1
+
+// simulatorDeploymentWebhook adapts SimulatorDeployment.Default and Validate to admission
+type simulatorDeploymentWebhook struct{}
+
+var _ webhook.CustomDefaulter = &simulatorDeploymentWebhook{}
+var _ webhook.CustomValidator = &simulatorDeploymentWebhook{}
+
+func (w *simulatorDeploymentWebhook) Default(_ context.Context, obj runtime.Object) error {
+	simDep, ok := obj.(*SimulatorDeployment)
+	if !ok {
+		return fmt.Errorf("expected a SimulatorDeployment but got %T", obj)
+	}
+	simDep.Default()
+	return nil
+}
+
+func (w *simulatorDeploymentWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	simDep, ok := obj.(*SimulatorDeployment)
+	if !ok {
+		return nil, fmt.Errorf("expected a SimulatorDeployment but got %T", obj)
+	}
+	return nil, simDep.Validate()
+}
+
+func (w *simulatorDeploymentWebhook) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	simDep, ok := newObj.(*SimulatorDeployment)
+	if !ok {
+		return nil, fmt.Errorf("expected a SimulatorDeployment but got %T", newObj)
+	}
+	return nil, simDep.Validate()
+}
+
+func (w *simulatorDeploymentWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// Default fills the stages, EPP and gateways of a SimulatorDeployment; stages
+// and pools inherit what they leave unset from the level above
+func (r *SimulatorDeployment) Default() {
+	spec := &r.Spec
+	if spec.Replicas == 0 {
+		spec.Replicas = 2
+	}
+	if spec.Image == "" {
+		spec.Image = DefaultSimulatorImage
+	}
+	if spec.LogVerbosity == 0 {
+		spec.LogVerbosity = 5
+	}
+	if spec.Service.Port == 0 {
+		spec.Service.Port = 8200
+	}
+	if spec.Service.Type == "" {
+		spec.Service.Type = corev1.ServiceTypeClusterIP
+	}
+	if spec.Gateway.ClassName == "" {
+		spec.Gateway.ClassName = "istio"
+	}
+	if spec.Gateway.RouteName == "" {
+		spec.Gateway.RouteName = "infra-sim-inference-gateway"
+	}
+
+	if spec.LoadBalancing != nil {
+		if spec.LoadBalancing.Algorithm == "" {
+			spec.LoadBalancing.Algorithm = "ROUND_ROBIN"
+		}
+		if pool := spec.LoadBalancing.ConnectionPool; pool != nil {
+			if pool.HTTP1MaxPendingRequests == 0 {
+				pool.HTTP1MaxPendingRequests = 1
+			}
+			if pool.MaxRequestsPerConnection == 0 {
+				pool.MaxRequestsPerConnection = 1
+			}
+		}
+	}
+
+	if spec.EPP != nil {
+		if spec.EPP.Replicas == 0 {
+			spec.EPP.Replicas = 1
+		}
+		if spec.EPP.Image == "" {
+			spec.EPP.Image = DefaultEPPImage
+		}
+		if spec.EPP.Port == 0 {
+			spec.EPP.Port = 8100
+		}
+		if spec.EPP.Verbosity == 0 {
+			spec.EPP.Verbosity = 1
+		}
+		if spec.EPP.RestartPolicy == "" {
+			spec.EPP.RestartPolicy = EPPRestartOnConfigChange
+		}
+	}
+
+	for _, stage := range []*StageConfig{spec.Prefill, spec.Decode} {
+		if stage == nil {
+			continue
+		}
+		if stage.Replicas == 0 {
+			stage.Replicas = 2
+		}
+		if stage.Image == "" {
+			stage.Image = spec.Image
+		}
+		if stage.Port == 0 {
+			stage.Port = 8200
+		}
+		if stage.LogVerbosity == 0 {
+			stage.LogVerbosity = spec.LogVerbosity
+		}
+		if stage.Behavior == nil {
+			stage.Behavior = &SimulatorBehavior{}
+		}
+		if stage.Behavior.Mode == "" {
+			stage.Behavior.Mode = "random"
+		}
+		if stage.Behavior.Model == "" {
+			stage.Behavior.Model = "random"
+		}
+		for i := range stage.Pools {
+			pool := &stage.Pools[i]
+			if pool.Replicas == nil {
+				replicas := int32(1)
+				pool.Replicas = &replicas
+			}
+			if pool.Image == "" {
+				pool.Image = stage.Image
+			}
+			if len(pool.Resources.Requests) == 0 && len(pool.Resources.Limits) == 0 {
+				pool.Resources = *stage.Resources.DeepCopy()
+			}
+			if pool.Behavior == nil {
+				pool.Behavior = &SimulatorBehavior{}
+			}
+			defaultSimulatorBehavior(pool.Behavior, stage.Behavior)
+		}
+	}
+
+	if spec.InferenceGateway != nil {
+		defaultGatewayInstance(spec.InferenceGateway.Standard, DefaultStandardGatewayImage)
+		defaultGatewayInstance(spec.InferenceGateway.Istio, DefaultIstioGatewayImage)
+	}
+}
+
+// defaultSimulatorBehavior fills every unset field of behavior from base
+func defaultSimulatorBehavior(behavior, base *SimulatorBehavior) {
+	if behavior.Mode == "" {
+		behavior.Mode = base.Mode
+	}
+	if behavior.Model == "" {
+		behavior.Model = base.Model
+	}
+	if behavior.ServedModelNames == nil {
+		behavior.ServedModelNames = base.ServedModelNames
+	}
+	if behavior.TimeToFirstToken == nil {
+		behavior.TimeToFirstToken = base.TimeToFirstToken
+	}
+	if behavior.TimeToFirstTokenJitter == nil {
+		behavior.TimeToFirstTokenJitter = base.TimeToFirstTokenJitter
+	}
+	if behavior.InterTokenLatency == nil {
+		behavior.InterTokenLatency = base.InterTokenLatency
+	}
+	if behavior.InterTokenLatencyJitter == nil {
+		behavior.InterTokenLatencyJitter = base.InterTokenLatencyJitter
+	}
+	if behavior.KVCacheSize == nil {
+		behavior.KVCacheSize = base.KVCacheSize
+	}
+	if behavior.MaxNumSeqs == nil {
+		behavior.MaxNumSeqs = base.MaxNumSeqs
+	}
+	if behavior.FailureInjectionRate == nil {
+		behavior.FailureInjectionRate = base.FailureInjectionRate
+	}
+	if behavior.FailureTypes == nil {
+		behavior.FailureTypes = base.FailureTypes
+	}
+	// Share nothing with the stage, so that editing one does not change the other
+	*behavior = *behavior.DeepCopy()
+}
+
+func defaultGatewayInstance(config *GatewayInstanceConfig, image string) {
+	if config == nil {
+		return
+	}
+	if config.Replicas == 0 {
+		config.Replicas = 1
+	}
+	if config.Image == "" {
+		config.Image = image
+	}
+	if config.Port == 0 {
+		config.Port = 8080
+	}
+	if config.AdminPort == 0 {
+		config.AdminPort = 19000
+	}
+	if extProc := config.ExtProc; extProc != nil {
+		if extProc.ProcessingMode == nil {
+			extProc.ProcessingMode = &ExtProcProcessingMode{}
+		}
+		defaultExtProcProcessingMode(extProc.ProcessingMode)
+		if extProc.Timeout == nil {
+			extProc.Timeout = &metav1.Duration{Duration: 2 * time.Second}
+		}
+		if extProc.FailureModeAllow == nil {
+			failureModeAllow := true
+			extProc.FailureModeAllow = &failureModeAllow
+		}
+	}
+}
+
+// Validate checks the names, Service, stages and gateways of a SimulatorDeployment
+func (r *SimulatorDeployment) Validate() error {
+	var allErrs field.ErrorList
+	spec := &r.Spec
+	specPath := field.NewPath("spec")
+
+	// Object names are derived from the prefix, so it has to be a short DNS label
+	prefix, prefixPath := spec.NamePrefix, specPath.Child("namePrefix")
+	if prefix == "" {
+		prefix, prefixPath = r.Name, field.NewPath("metadata", "name")
+	}
+	for _, msg := range validation.IsDNS1035Label(prefix) {
+		allErrs = append(allErrs, field.Invalid(prefixPath, prefix, msg))
+	}
+	if len(prefix) > MaxSimulatorNamePrefixLength {
+		allErrs = append(allErrs, field.Invalid(prefixPath, prefix,
+			fmt.Sprintf("must be no more than %d characters to leave room for the object name suffixes; set spec.namePrefix", MaxSimulatorNamePrefixLength)))
+	}
+
+	servicePath := specPath.Child("service")
+	if spec.Service.Name != "" {
+		allErrs = append(allErrs, validateDNSLabel(spec.Service.Name, servicePath.Child("name"))...)
+	}
+	allErrs = append(allErrs, validatePort(spec.Service.Port, servicePath.Child("port"))...)
+	switch spec.Service.Type {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+	default:
+		allErrs = append(allErrs, field.NotSupported(servicePath.Child("type"), spec.Service.Type,
+			[]string{string(corev1.ServiceTypeClusterIP), string(corev1.ServiceTypeNodePort), string(corev1.ServiceTypeLoadBalancer)}))
+	}
+
+	if spec.EPP != nil {
+		eppPath := specPath.Child("epp")
+		allErrs = append(allErrs, validatePort(spec.EPP.Port, eppPath.Child("port"))...)
+		// The EPP health probe listens on port+1
+		if spec.EPP.Port == 65535 {
+			allErrs = append(allErrs, field.Invalid(eppPath.Child("port"), spec.EPP.Port, "must leave room for the health port (port+1)"))
+		}
+		_, configErrs := BuildEndpointPickerConfig("default", spec.EPP.Plugins, spec.EPP.SchedulingProfiles, eppPath)
+		allErrs = append(allErrs, configErrs...)
+	}
+
+	stages := map[string]*StageConfig{"prefill": spec.Prefill, "decode": spec.Decode}
+	for _, name := range []string{"prefill", "decode"} {
+		if stage := stages[name]; stage != nil {
+			allErrs = append(allErrs, validatePort(stage.Port, specPath.Child(name, "port"))...)
+			if stage.Behavior != nil {
+				allErrs = append(allErrs, ValidateSimulatorBehavior(stage.Behavior, specPath.Child(name, "behavior"))...)
+			}
+			allErrs = append(allErrs, ValidateSimulatorPools(stage.Pools, specPath.Child(name, "pools"))...)
+		}
+	}
+
+	if spec.InferenceGateway != nil {
+		gatewayPath := specPath.Child("inferenceGateway")
+		if standard := spec.InferenceGateway.Standard; standard != nil {
+			standardPath := gatewayPath.Child("standard")
+			allErrs = append(allErrs, validatePort(standard.Port, standardPath.Child("port"))...)
+			allErrs = append(allErrs, validatePort(standard.AdminPort, standardPath.Child("adminPort"))...)
+			// Envoy listens for requests on port 80 inside the pod
+			if standard.AdminPort == 80 {
+				allErrs = append(allErrs, field.Invalid(standardPath.Child("adminPort"), standard.AdminPort, "must differ from the listener port 80"))
+			}
+			if extProc := standard.ExtProc; extProc != nil && extProc.Enabled {
+				extProcPath := standardPath.Child("extProc")
+				if spec.EPP == nil || !spec.EPP.Enabled {
+					allErrs = append(allErrs, field.Invalid(extProcPath.Child("enabled"), extProc.Enabled, "requires spec.epp.enabled"))
+				}
+				allErrs = append(allErrs, validateExtProc(extProc.ProcessingMode, extProc.Timeout, extProc.MessageTimeout, extProcPath)...)
+			}
+		}
+		if istio := spec.InferenceGateway.Istio; istio != nil {
+			allErrs = append(allErrs, validatePort(istio.Port, gatewayPath.Child("istio", "port"))...)
+			// The Istio proxy is configured by istiod, not by the rendered bootstrap
+			if istio.ExtProc != nil && istio.ExtProc.Enabled {
+				allErrs = append(allErrs, field.Forbidden(gatewayPath.Child("istio", "extProc"), "only supported on the standard gateway"))
+			}
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("SimulatorDeployment").GroupKind(), r.Name, allErrs)
+}
+
+// ValidateSimulatorBehavior rejects behavior the simulator refuses to start
+// with. Latencies are passed as whole milliseconds and the simulator caps
+// their standard deviation at 30% of the latency.
+func ValidateSimulatorBehavior(behavior *SimulatorBehavior, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch behavior.Mode {
+	case "random", "echo":
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mode"), behavior.Mode, []string{"random", "echo"}))
+	}
+	if behavior.Model == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("model"), ""))
+	}
+	for i, name := range behavior.ServedModelNames {
+		if name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("servedModelNames").Index(i), ""))
+		}
+	}
+
+	latency := func(name string, d *metav1.Duration) {
+		if d == nil {
+			return
+		}
+		if d.Duration < 0 || d.Duration%time.Millisecond != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(name), d.Duration.String(), "must be a non-negative whole number of milliseconds"))
+		}
+	}
+	jitter := func(name string, d *metav1.Duration, base *metav1.Duration) {
+		latency(name, d)
+		if d == nil || d.Duration <= 0 {
+			return
+		}
+		if base == nil || 10*d.Duration > 3*base.Duration {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(name), d.Duration.String(), "must be at most 30% of the latency it varies"))
+		}
+	}
+	latency("timeToFirstToken", behavior.TimeToFirstToken)
+	jitter("timeToFirstTokenJitter", behavior.TimeToFirstTokenJitter, behavior.TimeToFirstToken)
+	latency("interTokenLatency", behavior.InterTokenLatency)
+	jitter("interTokenLatencyJitter", behavior.InterTokenLatencyJitter, behavior.InterTokenLatency)
+
+	if behavior.KVCacheSize != nil && *behavior.KVCacheSize < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("kvCacheSize"), *behavior.KVCacheSize, "must be at least 1"))
+	}
+	if behavior.MaxNumSeqs != nil && *behavior.MaxNumSeqs < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxNumSeqs"), *behavior.MaxNumSeqs, "must be at least 1"))
+	}
+	if rate := behavior.FailureInjectionRate; rate != nil && (*rate < 0 || *rate > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("failureInjectionRate"), *rate, "must be a percentage between 0 and 100"))
+	}
+	for i, failure := range behavior.FailureTypes {
+		switch failure {
+		case "rate_limit", "invalid_api_key", "context_length", "server_error", "invalid_request", "model_not_found":
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("failureTypes").Index(i), failure,
+				[]string{"rate_limit", "invalid_api_key", "context_length", "server_error", "invalid_request", "model_not_found"}))
+		}
+	}
+	return allErrs
+}
+
+// ValidateSimulatorPools checks the pools of a defaulted stage. Pool names
+// become part of Deployment names and pool labels must leave the labels the
+// stage Service selects on alone.
+func ValidateSimulatorPools(pools []SimulatorPool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	reserved := []string{"llm-d.ai/role", "llm-d.ai/inferenceServing", "app.kubernetes.io/name", SimulatorPoolLabel}
+	names := map[string]bool{}
+	for i, pool := range pools {
+		poolPath := fldPath.Index(i)
+		for _, msg := range validation.IsDNS1123Label(pool.Name) {
+			allErrs = append(allErrs, field.Invalid(poolPath.Child("name"), pool.Name, msg))
+		}
+		if len(pool.Name) > 20 {
+			allErrs = append(allErrs, field.TooLong(poolPath.Child("name"), pool.Name, 20))
+		}
+		if names[pool.Name] {
+			allErrs = append(allErrs, field.Duplicate(poolPath.Child("name"), pool.Name))
+		}
+		names[pool.Name] = true
+		if pool.Replicas != nil && *pool.Replicas < 0 {
+			allErrs = append(allErrs, field.Invalid(poolPath.Child("replicas"), *pool.Replicas, "must not be negative"))
+		}
+		for key, value := range pool.Labels {
+			labelPath := poolPath.Child("labels").Key(key)
+			for _, msg := range validation.IsQualifiedName(key) {
+				allErrs = append(allErrs, field.Invalid(labelPath, key, msg))
+			}
+			for _, msg := range validation.IsValidLabelValue(value) {
+				allErrs = append(allErrs, field.Invalid(labelPath, value, msg))
+			}
+			for _, r := range reserved {
+				if key == r {
+					allErrs = append(allErrs, field.Forbidden(labelPath, "is set by the operator"))
+				}
+			}
+		}
+		if pool.Behavior != nil {
+			allErrs = append(allErrs, ValidateSimulatorBehavior(pool.Behavior, poolPath.Child("behavior"))...)
+		}
+	}
+	return allErrs
+}
+
+func validatePort(port int32, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsValidPortNum(int(port)) {
+		allErrs = append(allErrs, field.Invalid(path, port, msg))
+	}
+	return allErrs
+}
+
+func validateDNSLabel(name string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsDNS1035Label(name) {
+		allErrs = append(allErrs, field.Invalid(path, name, msg))
+	}
+	return allErrs
+}
+
+func validateNamespace(namespace string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if namespace == "" {
+		return allErrs
+	}
+	for _, msg := range validation.IsDNS1123Label(namespace) {
+		allErrs = append(allErrs, field.Invalid(path, namespace, msg))
+	}
+	return allErrs
+}
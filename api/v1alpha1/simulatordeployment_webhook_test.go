@@ -0,0 +1,324 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+func newSimulatorDeployment(name string) *SimulatorDeployment {
+	return &SimulatorDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+}
+
+// equalJSON compares two objects by their serialized form
+func equalJSON(t *testing.T, a, b interface{}) bool {
+	t.Helper()
+	var decodedA, decodedB interface{}
+	for _, pair := range []struct {
+		in  interface{}
+		out *interface{}
+	}{{a, &decodedA}, {b, &decodedB}} {
+		raw, err := json.Marshal(pair.in)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		if err := json.Unmarshal(raw, pair.out); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+	}
+	return reflect.DeepEqual(decodedA, decodedB)
+}
+
+func TestSimulatorDeploymentDefault(t *testing.T) {
+	simDep := newSimulatorDeployment("defaults")
+	simDep.Spec.LogVerbosity = 3
+	simDep.Spec.EPP = &EPPConfig{Enabled: true}
+	simDep.Spec.Decode = &StageConfig{Enabled: true}
+	simDep.Spec.Prefill = &StageConfig{Enabled: true, Image: "custom:1", LogVerbosity: 7,
+		Pools: []SimulatorPool{{Name: "echo", Behavior: &SimulatorBehavior{Mode: "echo"}}, {Name: "drained", Replicas: new(int32)}}}
+	simDep.Spec.InferenceGateway = &InferenceGatewayConfig{
+		Enabled:  true,
+		Standard: &GatewayInstanceConfig{Enabled: true},
+		Istio:    &GatewayInstanceConfig{Enabled: true},
+	}
+	simDep.Default()
+
+	spec := simDep.Spec
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"image", spec.Image, DefaultSimulatorImage},
+		{"service.name", spec.Service.Name, ""},
+		{"service.type", spec.Service.Type, corev1.ServiceTypeClusterIP},
+		{"epp.port", spec.EPP.Port, int32(8100)},
+		{"epp.verbosity", spec.EPP.Verbosity, int32(1)},
+		{"decode.image", spec.Decode.Image, DefaultSimulatorImage},
+		{"decode.logVerbosity", spec.Decode.LogVerbosity, int32(3)},
+		{"decode.replicas", spec.Decode.Replicas, int32(2)},
+		{"prefill.image", spec.Prefill.Image, "custom:1"},
+		{"prefill.logVerbosity", spec.Prefill.LogVerbosity, int32(7)},
+		{"decode.behavior.mode", spec.Decode.Behavior.Mode, "random"},
+		{"decode.behavior.model", spec.Decode.Behavior.Model, "random"},
+		{"prefill.pools[0].image", spec.Prefill.Pools[0].Image, "custom:1"},
+		{"prefill.pools[0].replicas", *spec.Prefill.Pools[0].Replicas, int32(1)},
+		{"prefill.pools[1].replicas", *spec.Prefill.Pools[1].Replicas, int32(0)},
+		{"prefill.pools[0].behavior.mode", spec.Prefill.Pools[0].Behavior.Mode, "echo"},
+		{"prefill.pools[0].behavior.model", spec.Prefill.Pools[0].Behavior.Model, "random"},
+		{"inferenceGateway.standard.image", spec.InferenceGateway.Standard.Image, DefaultStandardGatewayImage},
+		{"inferenceGateway.istio.image", spec.InferenceGateway.Istio.Image, DefaultIstioGatewayImage},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	again := simDep.DeepCopy()
+	again.Default()
+	if !equalJSON(t, simDep, again) {
+		t.Errorf("Default is not idempotent")
+	}
+	if err := simDep.Validate(); err != nil {
+		t.Errorf("defaulted spec is invalid: %v", err)
+	}
+}
+
+func TestSimulatorDeploymentValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*SimulatorDeployment)
+		wantErr string
+	}{
+		{
+			name:    "invalid service type",
+			mutate:  func(s *SimulatorDeployment) { s.Spec.Service.Type = "ExternalName" },
+			wantErr: "spec.service.type",
+		},
+		{
+			name:    "invalid service name",
+			mutate:  func(s *SimulatorDeployment) { s.Spec.Service.Name = "Not_A_Name" },
+			wantErr: "spec.service.name",
+		},
+		{
+			name:    "stage port out of range",
+			mutate:  func(s *SimulatorDeployment) { s.Spec.Decode = &StageConfig{Enabled: true, Port: 70000} },
+			wantErr: "spec.decode.port",
+		},
+		{
+			name: "latency not in whole milliseconds",
+			mutate: func(s *SimulatorDeployment) {
+				s.Spec.Decode = &StageConfig{Enabled: true, Behavior: &SimulatorBehavior{
+					InterTokenLatency: &metav1.Duration{Duration: 1500 * time.Microsecond},
+				}}
+			},
+			wantErr: "spec.decode.behavior.interTokenLatency",
+		},
+		{
+			name: "jitter above 30% of the latency",
+			mutate: func(s *SimulatorDeployment) {
+				s.Spec.Prefill = &StageConfig{Enabled: true, Behavior: &SimulatorBehavior{
+					TimeToFirstToken:       &metav1.Duration{Duration: 100 * time.Millisecond},
+					TimeToFirstTokenJitter: &metav1.Duration{Duration: 40 * time.Millisecond},
+				}}
+			},
+			wantErr: "spec.prefill.behavior.timeToFirstTokenJitter",
+		},
+		{
+			name: "unknown failure type",
+			mutate: func(s *SimulatorDeployment) {
+				rate := int32(10)
+				s.Spec.Decode = &StageConfig{Enabled: true, Behavior: &SimulatorBehavior{
+					FailureInjectionRate: &rate,
+					FailureTypes:         []SimulatorFailureType{"timeout"},
+				}}
+			},
+			wantErr: "spec.decode.behavior.failureTypes[0]",
+		},
+		{
+			name: "duplicate pool name",
+			mutate: func(s *SimulatorDeployment) {
+				s.Spec.Decode = &StageConfig{Enabled: true, Pools: []SimulatorPool{{Name: "fast"}, {Name: "fast"}}}
+			},
+			wantErr: "spec.decode.pools[1].name",
+		},
+		{
+			name: "negative pool replicas",
+			mutate: func(s *SimulatorDeployment) {
+				replicas := int32(-1)
+				s.Spec.Decode = &StageConfig{Enabled: true, Pools: []SimulatorPool{{Name: "fast", Replicas: &replicas}}}
+			},
+			wantErr: "spec.decode.pools[0].replicas",
+		},
+		{
+			name: "pool label replaces the stage role",
+			mutate: func(s *SimulatorDeployment) {
+				s.Spec.Decode = &StageConfig{Enabled: true, Pools: []SimulatorPool{
+					{Name: "fast", Labels: map[string]string{"llm-d.ai/role": "prefill"}},
+				}}
+			},
+			wantErr: "spec.decode.pools[0].labels[llm-d.ai/role]",
+		},
+		{
+			name:    "name too long for the derived object names",
+			mutate:  func(s *SimulatorDeployment) { s.Name = "simulator-deployment-with-a-long-name" },
+			wantErr: "metadata.name",
+		},
+		{
+			name:    "name prefix not a DNS label",
+			mutate:  func(s *SimulatorDeployment) { s.Spec.NamePrefix = "1sim" },
+			wantErr: "spec.namePrefix",
+		},
+		{
+			name: "name prefix shortens a long name",
+			mutate: func(s *SimulatorDeployment) {
+				s.Name = "simulator-deployment-with-a-long-name"
+				s.Spec.NamePrefix = "sim"
+			},
+		},
+		{
+			name:    "epp port without room for health port",
+			mutate:  func(s *SimulatorDeployment) { s.Spec.EPP = &EPPConfig{Enabled: true, Port: 65535} },
+			wantErr: "spec.epp.port",
+		},
+		{
+			name: "gateway ext_proc without the epp",
+			mutate: func(s *SimulatorDeployment) {
+				s.Spec.InferenceGateway = &InferenceGatewayConfig{Enabled: true, Standard: &GatewayInstanceConfig{
+					Enabled: true, ExtProc: &GatewayExtProcConfig{Enabled: true},
+				}}
+			},
+			wantErr: "spec.inferenceGateway.standard.extProc.enabled",
+		},
+		{
+			name: "gateway ext_proc on the istio gateway",
+			mutate: func(s *SimulatorDeployment) {
+				s.Spec.EPP = &EPPConfig{Enabled: true}
+				s.Spec.InferenceGateway = &InferenceGatewayConfig{Enabled: true, Istio: &GatewayInstanceConfig{
+					Enabled: true, ExtProc: &GatewayExtProcConfig{Enabled: true},
+				}}
+			},
+			wantErr: "spec.inferenceGateway.istio.extProc",
+		},
+		{
+			name: "gateway admin port on the listener port",
+			mutate: func(s *SimulatorDeployment) {
+				s.Spec.InferenceGateway = &InferenceGatewayConfig{Enabled: true, Standard: &GatewayInstanceConfig{Enabled: true, AdminPort: 80}}
+			},
+			wantErr: "spec.inferenceGateway.standard.adminPort",
+		},
+		{
+			name:   "valid",
+			mutate: func(s *SimulatorDeployment) { s.Spec.Decode = &StageConfig{Enabled: true} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simDep := newSimulatorDeployment("validate")
+			tt.mutate(simDep)
+			simDep.Default()
+			err := simDep.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) {
+				t.Fatalf("expected an Invalid error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSimulatorDeploymentWebhook(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	invalid := newSimulatorDeployment("webhook-invalid")
+	invalid.Spec.Service.Type = "ExternalName"
+	if err := k8sClient.Create(ctx, invalid); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected create to be rejected as Invalid, got %v", err)
+	}
+
+	simDep := newSimulatorDeployment("webhook-defaults")
+	simDep.Spec.LogVerbosity = 3
+	simDep.Spec.Decode = &StageConfig{Enabled: true}
+	if err := k8sClient.Create(ctx, simDep); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	t.Cleanup(func() { _ = k8sClient.Delete(context.Background(), simDep) })
+
+	stored := &SimulatorDeployment{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: simDep.Name, Namespace: simDep.Namespace}, stored); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stored.Spec.Image != DefaultSimulatorImage {
+		t.Errorf("image = %q, want %q", stored.Spec.Image, DefaultSimulatorImage)
+	}
+	if stored.Spec.Decode.Image != DefaultSimulatorImage {
+		t.Errorf("decode.image = %q, want persisted default %q", stored.Spec.Decode.Image, DefaultSimulatorImage)
+	}
+	if stored.Spec.Decode.LogVerbosity != 3 {
+		t.Errorf("decode.logVerbosity = %d, want 3 inherited from spec.logVerbosity", stored.Spec.Decode.LogVerbosity)
+	}
+}
+
+// crdDefault returns the default of the property at path in the spec schema of a CRD in config/crd
+func crdDefault(t *testing.T, file string, path ...string) interface{} {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "config", "crd", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var crd map[string]interface{}
+	if err := yaml.Unmarshal(data, &crd); err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	versions, _ := crd["spec"].(map[string]interface{})["versions"].([]interface{})
+	if len(versions) == 0 {
+		t.Fatalf("%s: no versions", file)
+	}
+	schema := versions[0].(map[string]interface{})["schema"].(map[string]interface{})["openAPIV3Schema"].(map[string]interface{})
+	for _, name := range append([]string{"spec"}, path...) {
+		properties, _ := schema["properties"].(map[string]interface{})
+		if schema, _ = properties[name].(map[string]interface{}); schema == nil {
+			t.Fatalf("%s: no property %s in %v", file, name, path)
+		}
+	}
+	return schema["default"]
+}
+
+// The +kubebuilder:default markers cannot reference the Default*Image constants
+func TestCRDImageDefaults(t *testing.T) {
+	tests := []struct {
+		file string
+		path []string
+		want string
+	}{
+		{file: "sim.llm-d.io_simulatordeployments.yaml", path: []string{"image"}, want: DefaultSimulatorImage},
+		{file: "sim.llm-d.io_simulatordeployments.yaml", path: []string{"epp", "image"}, want: DefaultEPPImage},
+		{file: "sim.llm-d.io_schedulerinstalls.yaml", path: []string{"epp", "image"}, want: DefaultEPPImage},
+	}
+	for _, tt := range tests {
+		if got := crdDefault(t, tt.file, tt.path...); got != tt.want {
+			t.Errorf("%s spec.%s default = %v, want %s", tt.file, strings.Join(tt.path, "."), got, tt.want)
+		}
+	}
+}
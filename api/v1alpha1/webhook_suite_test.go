@@ -0,0 +1,126 @@
+package v1alpha1
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// k8sClient talks to the envtest apiserver; it is nil when the envtest
+// binaries are not available and the admission tests are skipped.
+var k8sClient client.Client
+
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		// envtest needs etcd and kube-apiserver, see `make test`
+		os.Exit(m.Run())
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "config", "webhook")},
+		},
+	}
+	if _, err := testEnv.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start envtest: %v\n", err)
+		os.Exit(1)
+	}
+
+	code, err := runWithWebhooks(m, testEnv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		code = 1
+	}
+	if err := testEnv.Stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to stop envtest: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// runWithWebhooks starts a manager serving the webhooks against testEnv and runs the tests
+func runWithWebhooks(m *testing.M, testEnv *envtest.Environment) (int, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return 0, err
+	}
+	if err := AddToScheme(scheme); err != nil {
+		return 0, err
+	}
+
+	var err error
+	k8sClient, err = client.New(testEnv.Config, client.Options{Scheme: scheme})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	options := testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(testEnv.Config, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    options.LocalServingHost,
+			Port:    options.LocalServingPort,
+			CertDir: options.LocalServingCertDir,
+		}),
+		LeaderElection: false,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create manager: %w", err)
+	}
+	if err := (&SimulatorDeployment{}).SetupWebhookWithManager(mgr); err != nil {
+		return 0, err
+	}
+	if err := (&SchedulerInstall{}).SetupWebhookWithManager(mgr); err != nil {
+		return 0, err
+	}
+	if err := (&LoadTest{}).SetupWebhookWithManager(mgr); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "manager stopped: %v\n", err)
+		}
+	}()
+
+	// Wait for the webhook server to accept TLS connections
+	addr := net.JoinHostPort(options.LocalServingHost, fmt.Sprintf("%d", options.LocalServingPort))
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("webhook server did not become ready: %w", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return m.Run(), nil
+}
+
+func requireEnvtest(t *testing.T) {
+	t.Helper()
+	if k8sClient == nil {
+		t.Skip("KUBEBUILDER_ASSETS is not set; skipping envtest")
+	}
+}
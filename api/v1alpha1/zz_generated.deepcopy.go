@@ -5,404 +5,1100 @@
 package v1alpha1
 
 import (
+	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConnectionPoolConfig) DeepCopyInto(out *ConnectionPoolConfig) {
+func (in *CanaryAnalysis) DeepCopyInto(out *CanaryAnalysis) {
 	*out = *in
+	in.Stable.DeepCopyInto(&out.Stable)
+	in.Candidate.DeepCopyInto(&out.Candidate)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(CanaryMetricNames)
+		**out = **in
+	}
+	if in.MaxErrorRateIncrease != nil {
+		in, out := &in.MaxErrorRateIncrease, &out.MaxErrorRateIncrease
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxLatencyIncrease != nil {
+		in, out := &in.MaxLatencyIncrease, &out.MaxLatencyIncrease
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionPoolConfig.
-func (in *ConnectionPoolConfig) DeepCopy() *ConnectionPoolConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryAnalysis.
+func (in *CanaryAnalysis) DeepCopy() *CanaryAnalysis {
 	if in == nil {
 		return nil
 	}
-	out := new(ConnectionPoolConfig)
+	out := new(CanaryAnalysis)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayConfig) DeepCopyInto(out *GatewayConfig) {
+func (in *CanaryAnalysisSample) DeepCopyInto(out *CanaryAnalysisSample) {
 	*out = *in
+	out.Stable = in.Stable
+	out.Candidate = in.Candidate
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfig.
-func (in *GatewayConfig) DeepCopy() *GatewayConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryAnalysisSample.
+func (in *CanaryAnalysisSample) DeepCopy() *CanaryAnalysisSample {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewayConfig)
+	out := new(CanaryAnalysisSample)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadBalancingConfig) DeepCopyInto(out *LoadBalancingConfig) {
+func (in *CanaryMetricNames) DeepCopyInto(out *CanaryMetricNames) {
 	*out = *in
-	if in.ConnectionPool != nil {
-		in, out := &in.ConnectionPool, &out.ConnectionPool
-		*out = new(ConnectionPoolConfig)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancingConfig.
-func (in *LoadBalancingConfig) DeepCopy() *LoadBalancingConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryMetricNames.
+func (in *CanaryMetricNames) DeepCopy() *CanaryMetricNames {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadBalancingConfig)
+	out := new(CanaryMetricNames)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceConfig) DeepCopyInto(out *ServiceConfig) {
+func (in *CanaryMetricsSample) DeepCopyInto(out *CanaryMetricsSample) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceConfig.
-func (in *ServiceConfig) DeepCopy() *ServiceConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryMetricsSample.
+func (in *CanaryMetricsSample) DeepCopy() *CanaryMetricsSample {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceConfig)
+	out := new(CanaryMetricsSample)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EPPConfig) DeepCopyInto(out *EPPConfig) {
+func (in *CanaryMetricsSource) DeepCopyInto(out *CanaryMetricsSource) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EPPConfig.
-func (in *EPPConfig) DeepCopy() *EPPConfig {
-	if in == nil {
-		return nil
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	out := new(EPPConfig)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayInstanceConfig) DeepCopyInto(out *GatewayInstanceConfig) {
-	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayInstanceConfig.
-func (in *GatewayInstanceConfig) DeepCopy() *GatewayInstanceConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryMetricsSource.
+func (in *CanaryMetricsSource) DeepCopy() *CanaryMetricsSource {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewayInstanceConfig)
+	out := new(CanaryMetricsSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InferenceGatewayConfig) DeepCopyInto(out *InferenceGatewayConfig) {
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
 	*out = *in
-	if in.Standard != nil {
-		in, out := &in.Standard, &out.Standard
-		*out = new(GatewayInstanceConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Istio != nil {
-		in, out := &in.Istio, &out.Istio
-		*out = new(GatewayInstanceConfig)
-		(*in).DeepCopyInto(*out)
+	in.StepStartTime.DeepCopyInto(&out.StepStartTime)
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(CanaryAnalysisSample)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceGatewayConfig.
-func (in *InferenceGatewayConfig) DeepCopy() *InferenceGatewayConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InferenceGatewayConfig)
+	out := new(CanaryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StageConfig) DeepCopyInto(out *StageConfig) {
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(SimulatorBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageConfig.
-func (in *StageConfig) DeepCopy() *StageConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(StageConfig)
+	out := new(ComponentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayRef) DeepCopyInto(out *GatewayRef) {
+func (in *ConnectionPoolConfig) DeepCopyInto(out *ConnectionPoolConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRef.
-func (in *GatewayRef) DeepCopy() *GatewayRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionPoolConfig.
+func (in *ConnectionPoolConfig) DeepCopy() *ConnectionPoolConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewayRef)
+	out := new(ConnectionPoolConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InferencePoolRef) DeepCopyInto(out *InferencePoolRef) {
+func (in *ExtProcProcessingMode) DeepCopyInto(out *ExtProcProcessingMode) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferencePoolRef.
-func (in *InferencePoolRef) DeepCopy() *InferencePoolRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtProcProcessingMode.
+func (in *ExtProcProcessingMode) DeepCopy() *ExtProcProcessingMode {
 	if in == nil {
 		return nil
 	}
-	out := new(InferencePoolRef)
+	out := new(ExtProcProcessingMode)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProxyServiceConfig) DeepCopyInto(out *ProxyServiceConfig) {
+func (in *GatewayConfig) DeepCopyInto(out *GatewayConfig) {
 	*out = *in
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyServiceConfig.
-func (in *ProxyServiceConfig) DeepCopy() *ProxyServiceConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfig.
+func (in *GatewayConfig) DeepCopy() *GatewayConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ProxyServiceConfig)
+	out := new(GatewayConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerEPPConfig) DeepCopyInto(out *SchedulerEPPConfig) {
+func (in *LoadBalancingConfig) DeepCopyInto(out *LoadBalancingConfig) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.ConnectionPool != nil {
+		in, out := &in.ConnectionPool, &out.ConnectionPool
+		*out = new(ConnectionPoolConfig)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerEPPConfig.
-func (in *SchedulerEPPConfig) DeepCopy() *SchedulerEPPConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancingConfig.
+func (in *LoadBalancingConfig) DeepCopy() *LoadBalancingConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SchedulerEPPConfig)
+	out := new(LoadBalancingConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerGatewayConfig) DeepCopyInto(out *SchedulerGatewayConfig) {
+func (in *ServiceConfig) DeepCopyInto(out *ServiceConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerGatewayConfig.
-func (in *SchedulerGatewayConfig) DeepCopy() *SchedulerGatewayConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceConfig.
+func (in *ServiceConfig) DeepCopy() *ServiceConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SchedulerGatewayConfig)
+	out := new(ServiceConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerEnvoyFilterConfig) DeepCopyInto(out *SchedulerEnvoyFilterConfig) {
+func (in *EPPConfig) DeepCopyInto(out *EPPConfig) {
 	*out = *in
-	if in.WorkloadSelector != nil {
-		in, out := &in.WorkloadSelector, &out.WorkloadSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]EPPPlugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SchedulingProfiles != nil {
+		in, out := &in.SchedulingProfiles, &out.SchedulingProfiles
+		*out = make([]EPPSchedulingProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerEnvoyFilterConfig.
-func (in *SchedulerEnvoyFilterConfig) DeepCopy() *SchedulerEnvoyFilterConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EPPConfig.
+func (in *EPPConfig) DeepCopy() *EPPConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SchedulerEnvoyFilterConfig)
+	out := new(EPPConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerRoutingConfig) DeepCopyInto(out *SchedulerRoutingConfig) {
+func (in *EPPPlugin) DeepCopyInto(out *EPPPlugin) {
 	*out = *in
-	out.ParentGateway = in.ParentGateway
-	if in.InferencePool != nil {
-		in, out := &in.InferencePool, &out.InferencePool
-		*out = new(InferencePoolRef)
-		**out = **in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerRoutingConfig.
-func (in *SchedulerRoutingConfig) DeepCopy() *SchedulerRoutingConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EPPPlugin.
+func (in *EPPPlugin) DeepCopy() *EPPPlugin {
 	if in == nil {
 		return nil
 	}
-	out := new(SchedulerRoutingConfig)
+	out := new(EPPPlugin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerInstall) DeepCopyInto(out *SchedulerInstall) {
+func (in *EPPProfilePlugin) DeepCopyInto(out *EPPProfilePlugin) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInstall.
-func (in *SchedulerInstall) DeepCopy() *SchedulerInstall {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EPPProfilePlugin.
+func (in *EPPProfilePlugin) DeepCopy() *EPPProfilePlugin {
 	if in == nil {
 		return nil
 	}
-	out := new(SchedulerInstall)
+	out := new(EPPProfilePlugin)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SchedulerInstall) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerInstallList) DeepCopyInto(out *SchedulerInstallList) {
+func (in *EPPSchedulingProfile) DeepCopyInto(out *EPPSchedulingProfile) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]SchedulerInstall, len(*in))
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]EPPProfilePlugin, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInstallList.
-func (in *SchedulerInstallList) DeepCopy() *SchedulerInstallList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EPPSchedulingProfile.
+func (in *EPPSchedulingProfile) DeepCopy() *EPPSchedulingProfile {
 	if in == nil {
 		return nil
 	}
-	out := new(SchedulerInstallList)
+	out := new(EPPSchedulingProfile)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SchedulerInstallList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerInstallSpec) DeepCopyInto(out *SchedulerInstallSpec) {
+func (in *GatewayExtProcConfig) DeepCopyInto(out *GatewayExtProcConfig) {
 	*out = *in
-	in.ProxyService.DeepCopyInto(&out.ProxyService)
-	if in.EPP != nil {
-		in, out := &in.EPP, &out.EPP
-		*out = new(SchedulerEPPConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Gateway != nil {
-		in, out := &in.Gateway, &out.Gateway
-		*out = new(SchedulerGatewayConfig)
+	if in.ProcessingMode != nil {
+		in, out := &in.ProcessingMode, &out.ProcessingMode
+		*out = new(ExtProcProcessingMode)
 		**out = **in
 	}
-	if in.Routing != nil {
-		in, out := &in.Routing, &out.Routing
-		*out = new(SchedulerRoutingConfig)
-		(*in).DeepCopyInto(*out)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.DestinationRule != nil {
-		in, out := &in.DestinationRule, &out.DestinationRule
-		*out = new(LoadBalancingConfig)
-		(*in).DeepCopyInto(*out)
+	if in.MessageTimeout != nil {
+		in, out := &in.MessageTimeout, &out.MessageTimeout
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.EnvoyFilter != nil {
-		in, out := &in.EnvoyFilter, &out.EnvoyFilter
-		*out = new(SchedulerEnvoyFilterConfig)
-		(*in).DeepCopyInto(*out)
+	if in.FailureModeAllow != nil {
+		in, out := &in.FailureModeAllow, &out.FailureModeAllow
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInstallSpec.
-func (in *SchedulerInstallSpec) DeepCopy() *SchedulerInstallSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayExtProcConfig.
+func (in *GatewayExtProcConfig) DeepCopy() *GatewayExtProcConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SchedulerInstallSpec)
+	out := new(GatewayExtProcConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SchedulerInstallStatus) DeepCopyInto(out *SchedulerInstallStatus) {
+func (in *GatewayInstanceConfig) DeepCopyInto(out *GatewayInstanceConfig) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ExtProc != nil {
+		in, out := &in.ExtProc, &out.ExtProc
+		*out = new(GatewayExtProcConfig)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInstallStatus.
-func (in *SchedulerInstallStatus) DeepCopy() *SchedulerInstallStatus {
-	if in == nil {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayInstanceConfig.
+func (in *GatewayInstanceConfig) DeepCopy() *GatewayInstanceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayInstanceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferenceGatewayConfig) DeepCopyInto(out *InferenceGatewayConfig) {
+	*out = *in
+	if in.Standard != nil {
+		in, out := &in.Standard, &out.Standard
+		*out = new(GatewayInstanceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(GatewayInstanceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceGatewayConfig.
+func (in *InferenceGatewayConfig) DeepCopy() *InferenceGatewayConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(InferenceGatewayConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageConfig) DeepCopyInto(out *StageConfig) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(SimulatorBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]SimulatorPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageConfig.
+func (in *StageConfig) DeepCopy() *StageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SimulatorPool) DeepCopyInto(out *SimulatorPool) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(SimulatorBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatorPool.
+func (in *SimulatorPool) DeepCopy() *SimulatorPool {
+	if in == nil {
+		return nil
+	}
+	out := new(SimulatorPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolStatus) DeepCopyInto(out *PoolStatus) {
+	*out = *in
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(SimulatorBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolStatus.
+func (in *PoolStatus) DeepCopy() *PoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SimulatorBehavior) DeepCopyInto(out *SimulatorBehavior) {
+	*out = *in
+	if in.ServedModelNames != nil {
+		in, out := &in.ServedModelNames, &out.ServedModelNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeToFirstToken != nil {
+		in, out := &in.TimeToFirstToken, &out.TimeToFirstToken
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TimeToFirstTokenJitter != nil {
+		in, out := &in.TimeToFirstTokenJitter, &out.TimeToFirstTokenJitter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InterTokenLatency != nil {
+		in, out := &in.InterTokenLatency, &out.InterTokenLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InterTokenLatencyJitter != nil {
+		in, out := &in.InterTokenLatencyJitter, &out.InterTokenLatencyJitter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.KVCacheSize != nil {
+		in, out := &in.KVCacheSize, &out.KVCacheSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNumSeqs != nil {
+		in, out := &in.MaxNumSeqs, &out.MaxNumSeqs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureInjectionRate != nil {
+		in, out := &in.FailureInjectionRate, &out.FailureInjectionRate
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureTypes != nil {
+		in, out := &in.FailureTypes, &out.FailureTypes
+		*out = make([]SimulatorFailureType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatorBehavior.
+func (in *SimulatorBehavior) DeepCopy() *SimulatorBehavior {
+	if in == nil {
+		return nil
+	}
+	out := new(SimulatorBehavior)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRef) DeepCopyInto(out *GatewayRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRef.
+func (in *GatewayRef) DeepCopy() *GatewayRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPBackendRef) DeepCopyInto(out *HTTPBackendRef) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPBackendRef.
+func (in *HTTPBackendRef) DeepCopy() *HTTPBackendRef {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPBackendRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPPathMatch) DeepCopyInto(out *HTTPPathMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPPathMatch.
+func (in *HTTPPathMatch) DeepCopy() *HTTPPathMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPPathMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteMatch) DeepCopyInto(out *HTTPRouteMatch) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(HTTPPathMatch)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPValueMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.QueryParams != nil {
+		in, out := &in.QueryParams, &out.QueryParams
+		*out = make([]HTTPValueMatch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteMatch.
+func (in *HTTPRouteMatch) DeepCopy() *HTTPRouteMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteRule) DeepCopyInto(out *HTTPRouteRule) {
+	*out = *in
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]HTTPRouteMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BackendRefs != nil {
+		in, out := &in.BackendRefs, &out.BackendRefs
+		*out = make([]HTTPBackendRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteRule.
+func (in *HTTPRouteRule) DeepCopy() *HTTPRouteRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPValueMatch) DeepCopyInto(out *HTTPValueMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPValueMatch.
+func (in *HTTPValueMatch) DeepCopy() *HTTPValueMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPValueMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferencePoolRef) DeepCopyInto(out *InferencePoolRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferencePoolRef.
+func (in *InferencePoolRef) DeepCopy() *InferencePoolRef {
+	if in == nil {
+		return nil
+	}
+	out := new(InferencePoolRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyServiceConfig) DeepCopyInto(out *ProxyServiceConfig) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyServiceConfig.
+func (in *ProxyServiceConfig) DeepCopy() *ProxyServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerCanaryConfig) DeepCopyInto(out *SchedulerCanaryConfig) {
+	*out = *in
+	in.Stable.DeepCopyInto(&out.Stable)
+	in.Candidate.DeepCopyInto(&out.Candidate)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.StepDuration != nil {
+		in, out := &in.StepDuration, &out.StepDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(CanaryAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerCanaryConfig.
+func (in *SchedulerCanaryConfig) DeepCopy() *SchedulerCanaryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerCanaryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerEPPConfig) DeepCopyInto(out *SchedulerEPPConfig) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]EPPPlugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SchedulingProfiles != nil {
+		in, out := &in.SchedulingProfiles, &out.SchedulingProfiles
+		*out = make([]EPPSchedulingProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerEPPConfig.
+func (in *SchedulerEPPConfig) DeepCopy() *SchedulerEPPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerEPPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerGatewayConfig) DeepCopyInto(out *SchedulerGatewayConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerGatewayConfig.
+func (in *SchedulerGatewayConfig) DeepCopy() *SchedulerGatewayConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerGatewayConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerEnvoyFilterConfig) DeepCopyInto(out *SchedulerEnvoyFilterConfig) {
+	*out = *in
+	if in.WorkloadSelector != nil {
+		in, out := &in.WorkloadSelector, &out.WorkloadSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProcessingMode != nil {
+		in, out := &in.ProcessingMode, &out.ProcessingMode
+		*out = new(ExtProcProcessingMode)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MessageTimeout != nil {
+		in, out := &in.MessageTimeout, &out.MessageTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FailureModeAllow != nil {
+		in, out := &in.FailureModeAllow, &out.FailureModeAllow
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerEnvoyFilterConfig.
+func (in *SchedulerEnvoyFilterConfig) DeepCopy() *SchedulerEnvoyFilterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerEnvoyFilterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerInferencePoolConfig) DeepCopyInto(out *SchedulerInferencePoolConfig) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetPorts != nil {
+		in, out := &in.TargetPorts, &out.TargetPorts
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.EndpointPickerRef != nil {
+		in, out := &in.EndpointPickerRef, &out.EndpointPickerRef
+		*out = new(EndpointPickerRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInferencePoolConfig.
+func (in *SchedulerInferencePoolConfig) DeepCopy() *SchedulerInferencePoolConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerInferencePoolConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointPickerRef) DeepCopyInto(out *EndpointPickerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointPickerRef.
+func (in *EndpointPickerRef) DeepCopy() *EndpointPickerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointPickerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerRoutingConfig) DeepCopyInto(out *SchedulerRoutingConfig) {
+	*out = *in
+	out.ParentGateway = in.ParentGateway
+	if in.InferencePool != nil {
+		in, out := &in.InferencePool, &out.InferencePool
+		*out = new(InferencePoolRef)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]HTTPRouteRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerRoutingConfig.
+func (in *SchedulerRoutingConfig) DeepCopy() *SchedulerRoutingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerRoutingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerInstall) DeepCopyInto(out *SchedulerInstall) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInstall.
+func (in *SchedulerInstall) DeepCopy() *SchedulerInstall {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerInstall)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchedulerInstall) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerInstallList) DeepCopyInto(out *SchedulerInstallList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SchedulerInstall, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInstallList.
+func (in *SchedulerInstallList) DeepCopy() *SchedulerInstallList {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerInstallList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchedulerInstallList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerInstallSpec) DeepCopyInto(out *SchedulerInstallSpec) {
+	*out = *in
+	if in.SimulatorRef != nil {
+		in, out := &in.SimulatorRef, &out.SimulatorRef
+		*out = new(SimulatorReference)
+		**out = **in
+	}
+	in.ProxyService.DeepCopyInto(&out.ProxyService)
+	if in.EPP != nil {
+		in, out := &in.EPP, &out.EPP
+		*out = new(SchedulerEPPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(SchedulerGatewayConfig)
+		**out = **in
+	}
+	if in.Routing != nil {
+		in, out := &in.Routing, &out.Routing
+		*out = new(SchedulerRoutingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DestinationRule != nil {
+		in, out := &in.DestinationRule, &out.DestinationRule
+		*out = new(LoadBalancingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnvoyFilter != nil {
+		in, out := &in.EnvoyFilter, &out.EnvoyFilter
+		*out = new(SchedulerEnvoyFilterConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InferencePool != nil {
+		in, out := &in.InferencePool, &out.InferencePool
+		*out = new(SchedulerInferencePoolConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(SchedulerCanaryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInstallSpec.
+func (in *SchedulerInstallSpec) DeepCopy() *SchedulerInstallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerInstallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerInstallStatus) DeepCopyInto(out *SchedulerInstallStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Simulator != nil {
+		in, out := &in.Simulator, &out.Simulator
+		*out = new(SimulatorBindingStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerInstallStatus.
+func (in *SchedulerInstallStatus) DeepCopy() *SchedulerInstallStatus {
+	if in == nil {
 		return nil
 	}
 	out := new(SchedulerInstallStatus)
@@ -410,6 +1106,21 @@ func (in *SchedulerInstallStatus) DeepCopy() *SchedulerInstallStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SimulatorBindingStatus) DeepCopyInto(out *SimulatorBindingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatorBindingStatus.
+func (in *SimulatorBindingStatus) DeepCopy() *SimulatorBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SimulatorBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SimulatorDeployment) DeepCopyInto(out *SimulatorDeployment) {
 	*out = *in
@@ -520,6 +1231,31 @@ func (in *SimulatorDeploymentStatus) DeepCopyInto(out *SimulatorDeploymentStatus
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Prefill != nil {
+		in, out := &in.Prefill, &out.Prefill
+		*out = new(ComponentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Decode != nil {
+		in, out := &in.Decode, &out.Decode
+		*out = new(ComponentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EPP != nil {
+		in, out := &in.EPP, &out.EPP
+		*out = new(ComponentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(ComponentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IstioGateway != nil {
+		in, out := &in.IstioGateway, &out.IstioGateway
+		*out = new(ComponentStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -538,3 +1274,333 @@ func (in *SimulatorDeploymentStatus) DeepCopy() *SimulatorDeploymentStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SimulatorReference) DeepCopyInto(out *SimulatorReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatorReference.
+func (in *SimulatorReference) DeepCopy() *SimulatorReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SimulatorReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencyPercentiles) DeepCopyInto(out *LatencyPercentiles) {
+	*out = *in
+	out.P50 = in.P50
+	out.P90 = in.P90
+	out.P99 = in.P99
+	out.Max = in.Max
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencyPercentiles.
+func (in *LatencyPercentiles) DeepCopy() *LatencyPercentiles {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencyPercentiles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadPhase) DeepCopyInto(out *LoadPhase) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Trace != nil {
+		in, out := &in.Trace, &out.Trace
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadPhase.
+func (in *LoadPhase) DeepCopy() *LoadPhase {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadPhase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadPhaseResults) DeepCopyInto(out *LoadPhaseResults) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	out.Duration = in.Duration
+	if in.ErrorsByReason != nil {
+		in, out := &in.ErrorsByReason, &out.ErrorsByReason
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TimeToFirstToken != nil {
+		in, out := &in.TimeToFirstToken, &out.TimeToFirstToken
+		*out = new(LatencyPercentiles)
+		**out = **in
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(LatencyPercentiles)
+		**out = **in
+	}
+	if in.Routing != nil {
+		in, out := &in.Routing, &out.Routing
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadPhaseResults.
+func (in *LoadPhaseResults) DeepCopy() *LoadPhaseResults {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadPhaseResults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTest) DeepCopyInto(out *LoadTest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTest.
+func (in *LoadTest) DeepCopy() *LoadTest {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadTest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestList) DeepCopyInto(out *LoadTestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LoadTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestList.
+func (in *LoadTestList) DeepCopy() *LoadTestList {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadTestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestResults) DeepCopyInto(out *LoadTestResults) {
+	*out = *in
+	if in.ErrorsByReason != nil {
+		in, out := &in.ErrorsByReason, &out.ErrorsByReason
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TimeToFirstToken != nil {
+		in, out := &in.TimeToFirstToken, &out.TimeToFirstToken
+		*out = new(LatencyPercentiles)
+		**out = **in
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(LatencyPercentiles)
+		**out = **in
+	}
+	if in.Routing != nil {
+		in, out := &in.Routing, &out.Routing
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]LoadPhaseResults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestResults.
+func (in *LoadTestResults) DeepCopy() *LoadTestResults {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestResults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestSpec) DeepCopyInto(out *LoadTestSpec) {
+	*out = *in
+	out.Target = in.Target
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]LoadPhase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PromptLength != nil {
+		in, out := &in.PromptLength, &out.PromptLength
+		*out = new(PromptLengthDistribution)
+		**out = **in
+	}
+	if in.SharedPrefix != nil {
+		in, out := &in.SharedPrefix, &out.SharedPrefix
+		*out = new(SharedPrefixConfig)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestSpec.
+func (in *LoadTestSpec) DeepCopy() *LoadTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestStatus) DeepCopyInto(out *LoadTestStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = new(LoadTestResults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestStatus.
+func (in *LoadTestStatus) DeepCopy() *LoadTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestTarget) DeepCopyInto(out *LoadTestTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestTarget.
+func (in *LoadTestTarget) DeepCopy() *LoadTestTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromptLengthDistribution) DeepCopyInto(out *PromptLengthDistribution) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromptLengthDistribution.
+func (in *PromptLengthDistribution) DeepCopy() *PromptLengthDistribution {
+	if in == nil {
+		return nil
+	}
+	out := new(PromptLengthDistribution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedPrefixConfig) DeepCopyInto(out *SharedPrefixConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedPrefixConfig.
+func (in *SharedPrefixConfig) DeepCopy() *SharedPrefixConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedPrefixConfig)
+	in.DeepCopyInto(out)
+	return out
+}
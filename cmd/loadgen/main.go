@@ -0,0 +1,90 @@
+// Command loadgen runs a LoadTest against an inference gateway. The operator
+// runs it in a Job and reads the results from the termination message; the
+// full results are also printed to stdout.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/llm-d/llm-d-scheduler-sim-operator/loadgen"
+)
+
+func main() {
+	var cfg loadgen.Config
+	var terminationLog, phases string
+	var promptMean, promptMin, promptMax, promptStdDev int
+	var prefixPercent, prefixLength, prefixGroups int
+
+	flag.StringVar(&cfg.URL, "url", "", "Base URL of the inference gateway, e.g. http://gateway.ns.svc:80.")
+	flag.StringVar(&cfg.API, "api", "completions", "OpenAI API to call: completions or chat.")
+	flag.StringVar(&cfg.Model, "model", "random", "Model name sent with every request.")
+	flag.IntVar(&cfg.RequestsPerSecond, "rate", 0, "Requests started per second; 0 sends as fast as the concurrency allows.")
+	flag.IntVar(&cfg.Concurrency, "concurrency", 1, "Maximum number of requests in flight.")
+	flag.DurationVar(&cfg.Duration, "duration", time.Minute, "How long requests are started for.")
+	flag.DurationVar(&cfg.Timeout, "timeout", 30*time.Second, "Timeout of a single request.")
+	flag.IntVar(&cfg.MaxTokens, "max-tokens", 16, "max_tokens of every request.")
+	flag.StringVar(&cfg.PromptLength.Distribution, "prompt-distribution", "Fixed", "Prompt length distribution: Fixed, Uniform or Normal.")
+	flag.IntVar(&promptMean, "prompt-mean", 128, "Mean prompt length in words.")
+	flag.IntVar(&promptMin, "prompt-min", 1, "Minimum prompt length in words.")
+	flag.IntVar(&promptMax, "prompt-max", 256, "Maximum prompt length in words.")
+	flag.IntVar(&promptStdDev, "prompt-stddev", 0, "Standard deviation of a Normal prompt length in words.")
+	flag.IntVar(&prefixPercent, "prefix-percent", 0, "Percent of the prompts that start with a shared prefix.")
+	flag.IntVar(&prefixLength, "prefix-length", 64, "Shared prefix length in words.")
+	flag.IntVar(&prefixGroups, "prefix-groups", 1, "Number of distinct shared prefixes.")
+	flag.StringVar(&phases, "phases", "", "JSON list of LoadTest phases; replaces --rate and --duration. Trace phases read traceFile.")
+	flag.Int64Var(&cfg.Seed, "seed", time.Now().UnixNano(), "Seed of the prompt generator.")
+	flag.StringVar(&terminationLog, "termination-log", "/dev/termination-log", "File the results are written to; empty disables it.")
+	flag.Parse()
+
+	cfg.PromptLength.Mean = int32(promptMean)
+	cfg.PromptLength.Min = int32(promptMin)
+	cfg.PromptLength.Max = int32(promptMax)
+	cfg.PromptLength.StdDev = int32(promptStdDev)
+	cfg.SharedPrefix.Percent = int32(prefixPercent)
+	cfg.SharedPrefix.Length = int32(prefixLength)
+	cfg.SharedPrefix.Groups = int32(prefixGroups)
+	if cfg.URL == "" {
+		fmt.Fprintln(os.Stderr, "--url is required")
+		os.Exit(2)
+	}
+	if phases != "" {
+		if err := json.Unmarshal([]byte(phases), &cfg.Phases); err != nil {
+			fmt.Fprintf(os.Stderr, "--phases: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	results, err := loadgen.Run(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// The logs keep the full results; the termination message may leave out phase details
+	out, err := json.Marshal(results)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(string(out))
+	if terminationLog != "" {
+		message, err := loadgen.TerminationMessage(results)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(terminationLog, message, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// canaryActive reports whether spec.canary drives the HTTPRoute weights
+func canaryActive(install *simv1alpha1.SchedulerInstall) bool {
+	return install.Spec.Canary != nil && install.Spec.Canary.Enabled &&
+		install.Spec.Routing != nil && install.Spec.Routing.Enabled && len(install.Spec.Routing.Rules) == 0
+}
+
+// canaryRevision identifies the backends and steps of a canary, so that
+// changing what is rolled out restarts it from the first step
+func canaryRevision(canary *simv1alpha1.SchedulerCanaryConfig) string {
+	backend := func(ref simv1alpha1.HTTPBackendRef) string {
+		return fmt.Sprintf("%s/%s/%s:%d", ref.Kind, ref.Namespace, ref.Name, ref.Port)
+	}
+	steps := make([]string, 0, len(canary.Steps))
+	for _, step := range canary.Steps {
+		steps = append(steps, fmt.Sprint(step))
+	}
+	return configHash(strings.Join([]string{backend(canary.Stable), backend(canary.Candidate), strings.Join(steps, ",")}, "|"))[:16]
+}
+
+// nextCanaryStatus returns the canary status at now and how long until it
+// should be checked again. A step that has been held for stepDuration moves to
+// the next one; with analysis it only does so when sample shows the candidate
+// healthy, and a breach rolls all traffic back to the stable backend. The
+// wait is zero once the canary is completed or rolled back.
+func nextCanaryStatus(canary *simv1alpha1.SchedulerCanaryConfig, previous *simv1alpha1.CanaryStatus, now time.Time, sample canarySampler) (*simv1alpha1.CanaryStatus, time.Duration) {
+	now = now.Truncate(time.Second)
+	revision := canaryRevision(canary)
+	last := int32(len(canary.Steps) - 1)
+
+	var status *simv1alpha1.CanaryStatus
+	if previous != nil && previous.Revision == revision && previous.Step >= 0 && previous.Step <= last {
+		status = previous.DeepCopy()
+	} else {
+		status = &simv1alpha1.CanaryStatus{Revision: revision, StepStartTime: metav1.NewTime(now)}
+		if sample != nil {
+			// Record the counters the first step is measured against
+			baseline, err := sample()
+			if err != nil {
+				status.Message = fmt.Sprintf("analysis: %v", err)
+			}
+			status.Baseline = baseline
+		}
+	}
+
+	if status.Phase == simv1alpha1.CanaryRolledBack {
+		status.CandidateWeight = 0
+		return status, 0
+	}
+
+	hold := 10 * time.Minute
+	if canary.StepDuration != nil {
+		hold = canary.StepDuration.Duration
+	}
+	if status.Step < last && !now.Before(status.StepStartTime.Add(hold)) {
+		advance := true
+		if sample != nil {
+			advance = false
+			current, err := sample()
+			switch {
+			case err != nil:
+				status.Message = fmt.Sprintf("analysis: %v", err)
+			case status.Baseline == nil:
+				// The first scrape failed; measure the step from now on
+				status.Baseline = current
+				status.StepStartTime = metav1.NewTime(now)
+				status.Message = "analysis baseline recorded"
+			default:
+				verdict := analyzeCanary(canary.Analysis, status.Baseline, current)
+				status.Message = verdict.message
+				if verdict.rollback {
+					status.Phase = simv1alpha1.CanaryRolledBack
+					status.CandidateWeight = 0
+					return status, 0
+				}
+				if verdict.healthy {
+					status.Baseline = current
+					advance = true
+				}
+			}
+		}
+		if advance {
+			status.Step++
+			status.StepStartTime = metav1.NewTime(now)
+			status.Message = ""
+		}
+	}
+
+	status.CandidateWeight = canary.Steps[status.Step]
+	if status.Step == last {
+		status.Phase = simv1alpha1.CanaryCompleted
+		return status, 0
+	}
+	status.Phase = simv1alpha1.CanaryProgressing
+	wait := status.StepStartTime.Add(hold).Sub(now)
+	if wait <= 0 {
+		// Due but held back by the analysis
+		wait = canaryAnalysisRetry
+	}
+	return status, wait
+}
+
+// canaryBackendRefs weights the stable and candidate backends by the current step
+func canaryBackendRefs(canary *simv1alpha1.SchedulerCanaryConfig, status *simv1alpha1.CanaryStatus) []simv1alpha1.HTTPBackendRef {
+	candidateWeight := int32(0)
+	if status != nil {
+		candidateWeight = status.CandidateWeight
+	}
+	stableWeight := 100 - candidateWeight
+	stable := canary.Stable
+	stable.Weight = &stableWeight
+	candidate := canary.Candidate
+	candidate.Weight = &candidateWeight
+	return []simv1alpha1.HTTPBackendRef{stable, candidate}
+}
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// canaryAnalysisRetry is how often a step that is due but cannot be analyzed
+// yet (scrape failed, too few requests) is checked again
+const canaryAnalysisRetry = time.Minute
+
+// canarySampler scrapes both backends of a canary
+type canarySampler func() (*simv1alpha1.CanaryAnalysisSample, error)
+
+// canaryVerdict is the outcome of comparing the traffic of one step
+type canaryVerdict struct {
+	// healthy is set when both backends served enough requests and the candidate is within the thresholds
+	healthy bool
+	// rollback is set when the candidate breached a threshold
+	rollback bool
+	message  string
+}
+
+// newCanarySampler returns a sampler for the analysis of canary, or nil when it has none
+func (r *SchedulerInstallReconciler) newCanarySampler(ctx context.Context, canary *simv1alpha1.SchedulerCanaryConfig) canarySampler {
+	analysis := canary.Analysis
+	if analysis == nil {
+		return nil
+	}
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	return func() (*simv1alpha1.CanaryAnalysisSample, error) {
+		stable, stableErr := scrapeCanaryMetrics(ctx, httpClient, analysis.Stable, analysis.Metrics)
+		candidate, candidateErr := scrapeCanaryMetrics(ctx, httpClient, analysis.Candidate, analysis.Metrics)
+		if err := utilerrors.NewAggregate([]error{stableErr, candidateErr}); err != nil {
+			return nil, err
+		}
+		return &simv1alpha1.CanaryAnalysisSample{Stable: stable, Candidate: candidate}, nil
+	}
+}
+
+// scrapeCanaryMetrics sums the named series over every endpoint of source
+func scrapeCanaryMetrics(ctx context.Context, httpClient *http.Client, source simv1alpha1.CanaryMetricsSource, names *simv1alpha1.CanaryMetricNames) (simv1alpha1.CanaryMetricsSample, error) {
+	var requests, errors, latencyCount, latencySum float64
+	for _, url := range source.URLs {
+		families, err := fetchMetricFamilies(ctx, httpClient, url)
+		if err != nil {
+			return simv1alpha1.CanaryMetricsSample{}, err
+		}
+		requests += sumSeries(families[names.Requests], source.Labels)
+		if names.Errors != "" {
+			errors += sumSeries(families[names.Errors], source.Labels)
+		}
+		if names.Latency != "" {
+			count, sum := sumHistogram(families[names.Latency], source.Labels)
+			latencyCount += count
+			latencySum += sum
+		}
+	}
+	return simv1alpha1.CanaryMetricsSample{
+		Requests:         int64(math.Round(requests)),
+		Errors:           int64(math.Round(errors)),
+		LatencyCount:     int64(math.Round(latencyCount)),
+		LatencySumMillis: int64(math.Round(latencySum * 1000)),
+	}, nil
+}
+
+func fetchMetricFamilies(ctx context.Context, httpClient *http.Client, url string) (map[string]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", string(expfmt.FmtText))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("scrape %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scrape %s: %s", url, resp.Status)
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parse metrics from %s: %w", url, err)
+	}
+	return families, nil
+}
+
+// sumSeries adds up the counter (or untyped) series of family that carry labels
+func sumSeries(family *dto.MetricFamily, labels map[string]string) float64 {
+	if family == nil {
+		return 0
+	}
+	var total float64
+	for _, metric := range family.GetMetric() {
+		if !hasLabels(metric, labels) {
+			continue
+		}
+		switch {
+		case metric.GetCounter() != nil:
+			total += metric.GetCounter().GetValue()
+		case metric.GetUntyped() != nil:
+			total += metric.GetUntyped().GetValue()
+		case metric.GetGauge() != nil:
+			total += metric.GetGauge().GetValue()
+		}
+	}
+	return total
+}
+
+// sumHistogram adds up the observation count and sum of the histogram series of family that carry labels
+func sumHistogram(family *dto.MetricFamily, labels map[string]string) (count, sum float64) {
+	if family == nil {
+		return 0, 0
+	}
+	for _, metric := range family.GetMetric() {
+		if !hasLabels(metric, labels) || metric.GetHistogram() == nil {
+			continue
+		}
+		count += float64(metric.GetHistogram().GetSampleCount())
+		sum += metric.GetHistogram().GetSampleSum()
+	}
+	return count, sum
+}
+
+func hasLabels(metric *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, pair := range metric.GetLabel() {
+		if value, ok := labels[pair.GetName()]; ok {
+			if value != pair.GetValue() {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(labels)
+}
+
+// analyzeCanary compares what each backend served between baseline and current
+func analyzeCanary(analysis *simv1alpha1.CanaryAnalysis, baseline, current *simv1alpha1.CanaryAnalysisSample) canaryVerdict {
+	stable := metricsDelta(baseline.Stable, current.Stable)
+	candidate := metricsDelta(baseline.Candidate, current.Candidate)
+
+	if stable.Requests < analysis.MinRequests || candidate.Requests < analysis.MinRequests {
+		return canaryVerdict{message: fmt.Sprintf("waiting for %d requests per backend, stable served %d and candidate %d",
+			analysis.MinRequests, stable.Requests, candidate.Requests)}
+	}
+
+	if analysis.Metrics != nil && analysis.Metrics.Errors != "" && analysis.MaxErrorRateIncrease != nil {
+		stableRate := 100 * float64(stable.Errors) / float64(stable.Requests)
+		candidateRate := 100 * float64(candidate.Errors) / float64(candidate.Requests)
+		if candidateRate-stableRate > float64(*analysis.MaxErrorRateIncrease) {
+			return canaryVerdict{rollback: true, message: fmt.Sprintf("candidate error rate %.1f%% exceeds stable %.1f%% by more than %d points",
+				candidateRate, stableRate, *analysis.MaxErrorRateIncrease)}
+		}
+	}
+
+	if analysis.Metrics != nil && analysis.Metrics.Latency != "" && analysis.MaxLatencyIncrease != nil &&
+		stable.LatencyCount > 0 && candidate.LatencyCount > 0 {
+		stableMean := float64(stable.LatencySumMillis) / float64(stable.LatencyCount)
+		candidateMean := float64(candidate.LatencySumMillis) / float64(candidate.LatencyCount)
+		if candidateMean > stableMean*(1+float64(*analysis.MaxLatencyIncrease)/100) {
+			return canaryVerdict{rollback: true, message: fmt.Sprintf("candidate mean latency %.0fms exceeds stable %.0fms by more than %d%%",
+				candidateMean, stableMean, *analysis.MaxLatencyIncrease)}
+		}
+	}
+	return canaryVerdict{healthy: true}
+}
+
+// metricsDelta returns the increase of each counter; a counter that went down
+// was reset by a restart, so its current value is the increase
+func metricsDelta(baseline, current simv1alpha1.CanaryMetricsSample) simv1alpha1.CanaryMetricsSample {
+	delta := func(before, after int64) int64 {
+		if after < before {
+			return after
+		}
+		return after - before
+	}
+	return simv1alpha1.CanaryMetricsSample{
+		Requests:         delta(baseline.Requests, current.Requests),
+		Errors:           delta(baseline.Errors, current.Errors),
+		LatencyCount:     delta(baseline.LatencyCount, current.LatencyCount),
+		LatencySumMillis: delta(baseline.LatencySumMillis, current.LatencySumMillis),
+	}
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// fakeMetricsBackend stands in for the /metrics endpoint of one canary backend
+type fakeMetricsBackend struct {
+	mu                sync.Mutex
+	requests, errors  int64
+	latencyCount      int64
+	latencySumSeconds float64
+	*httptest.Server
+}
+
+func newFakeMetricsBackend(t *testing.T) *fakeMetricsBackend {
+	b := &fakeMetricsBackend{}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		fmt.Fprintf(w, `# TYPE inference_objective_request_total counter
+inference_objective_request_total{model_name="m",target_model_name="m"} %d
+inference_objective_request_total{model_name="other",target_model_name="other"} 1000
+# TYPE inference_objective_request_error_total counter
+inference_objective_request_error_total{model_name="m",target_model_name="m",error_code="Internal"} %d
+# TYPE inference_objective_request_duration_seconds histogram
+inference_objective_request_duration_seconds_bucket{model_name="m",target_model_name="m",le="+Inf"} %d
+inference_objective_request_duration_seconds_sum{model_name="m",target_model_name="m"} %g
+inference_objective_request_duration_seconds_count{model_name="m",target_model_name="m"} %d
+`, b.requests, b.errors, b.latencyCount, b.latencySumSeconds, b.latencyCount)
+	}))
+	t.Cleanup(b.Close)
+	return b
+}
+
+// serve records requests served with the given failures and mean latency
+func (b *fakeMetricsBackend) serve(requests, errors int64, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests += requests
+	b.errors += errors
+	b.latencyCount += requests
+	b.latencySumSeconds += float64(requests) * latency.Seconds()
+}
+
+func TestAnalyzeCanary(t *testing.T) {
+	analysis := &simv1alpha1.CanaryAnalysis{
+		Metrics:              &simv1alpha1.CanaryMetricNames{Requests: "r", Errors: "e", Latency: "l"},
+		MaxErrorRateIncrease: ptr.To[int32](1),
+		MaxLatencyIncrease:   ptr.To[int32](20),
+		MinRequests:          20,
+	}
+	sample := func(requests, errors, latencyMillis int64) simv1alpha1.CanaryMetricsSample {
+		return simv1alpha1.CanaryMetricsSample{Requests: requests, Errors: errors, LatencyCount: requests, LatencySumMillis: requests * latencyMillis}
+	}
+	baseline := &simv1alpha1.CanaryAnalysisSample{Stable: sample(500, 5, 100), Candidate: sample(10, 0, 100)}
+
+	tests := []struct {
+		name    string
+		current *simv1alpha1.CanaryAnalysisSample
+		want    canaryVerdict
+		message string
+	}{
+		{
+			name:    "healthy",
+			current: &simv1alpha1.CanaryAnalysisSample{Stable: sample(600, 6, 100), Candidate: sample(110, 1, 110)},
+			want:    canaryVerdict{healthy: true},
+		},
+		{
+			name:    "too few requests",
+			current: &simv1alpha1.CanaryAnalysisSample{Stable: sample(600, 6, 100), Candidate: sample(25, 5, 500)},
+			message: "candidate 15",
+		},
+		{
+			name:    "error rate breach",
+			current: &simv1alpha1.CanaryAnalysisSample{Stable: sample(600, 6, 100), Candidate: sample(110, 5, 100)},
+			want:    canaryVerdict{rollback: true},
+			message: "error rate 5.0%",
+		},
+		{
+			name:    "latency breach",
+			current: &simv1alpha1.CanaryAnalysisSample{Stable: sample(600, 6, 100), Candidate: sample(110, 1, 130)},
+			want:    canaryVerdict{rollback: true},
+			message: "mean latency",
+		},
+		{
+			name: "counters reset by a restart",
+			current: &simv1alpha1.CanaryAnalysisSample{Stable: sample(100, 1, 100),
+				Candidate: simv1alpha1.CanaryMetricsSample{Requests: 5, LatencyCount: 5, LatencySumMillis: 500}},
+			message: "candidate 5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := analyzeCanary(analysis, baseline, tt.current)
+			if got.healthy != tt.want.healthy || got.rollback != tt.want.rollback || !strings.Contains(got.message, tt.message) {
+				t.Errorf("analyzeCanary = %+v, want healthy=%v rollback=%v message containing %q",
+					got, tt.want.healthy, tt.want.rollback, tt.message)
+			}
+		})
+	}
+}
+
+func TestSchedulerInstallReconcileCanaryAnalysis(t *testing.T) {
+	stable, candidate := newFakeMetricsBackend(t), newFakeMetricsBackend(t)
+	labels := map[string]string{"target_model_name": "m"}
+
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Spec.Canary = newCanary()
+	install.Spec.Canary.Analysis = &simv1alpha1.CanaryAnalysis{
+		Stable:    simv1alpha1.CanaryMetricsSource{URLs: []string{stable.URL + "/metrics"}, Labels: labels},
+		Candidate: simv1alpha1.CanaryMetricsSource{URLs: []string{candidate.URL + "/metrics"}, Labels: labels},
+	}
+	clock := clocktesting.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, install, eppService(install)))
+	r.Clock = clock
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(install)}
+	reconcile := func(after time.Duration) *simv1alpha1.CanaryStatus {
+		t.Helper()
+		clock.SetTime(clock.Now().Add(after))
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+		latest := &simv1alpha1.SchedulerInstall{}
+		if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+			t.Fatal(err)
+		}
+		if latest.Status.Canary == nil {
+			t.Fatal("status.canary is not set")
+		}
+		return latest.Status.Canary
+	}
+
+	status := reconcile(0)
+	if status.Step != 0 || status.Baseline == nil {
+		t.Fatalf("first step = %+v, want a baseline", status)
+	}
+
+	// Too little traffic holds the step and retries
+	stable.serve(100, 0, 100*time.Millisecond)
+	candidate.serve(5, 0, 100*time.Millisecond)
+	status = reconcile(10 * time.Minute)
+	if status.Step != 0 || !strings.Contains(status.Message, "waiting for 20 requests") {
+		t.Fatalf("held step = %+v", status)
+	}
+
+	// A healthy candidate moves to the next step
+	candidate.serve(30, 0, 110*time.Millisecond)
+	status = reconcile(canaryAnalysisRetry)
+	if status.Step != 1 || status.CandidateWeight != 25 || status.Phase != simv1alpha1.CanaryProgressing {
+		t.Fatalf("healthy step = %+v, want step 1 at 25%%", status)
+	}
+
+	// Failing requests roll all traffic back to the stable backend and keep it there
+	stable.serve(400, 2, 100*time.Millisecond)
+	candidate.serve(100, 10, 100*time.Millisecond)
+	status = reconcile(10 * time.Minute)
+	if status.Phase != simv1alpha1.CanaryRolledBack || status.CandidateWeight != 0 || !strings.Contains(status.Message, "error rate") {
+		t.Fatalf("breached step = %+v, want RolledBack", status)
+	}
+	status = reconcile(time.Hour)
+	if status.Phase != simv1alpha1.CanaryRolledBack || status.CandidateWeight != 0 {
+		t.Errorf("rolled back canary = %+v", status)
+	}
+	expectRouteWeights(t, r.Client, install, 100, 0)
+}
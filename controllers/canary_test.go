@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+func newCanary() *simv1alpha1.SchedulerCanaryConfig {
+	return &simv1alpha1.SchedulerCanaryConfig{
+		Enabled:      true,
+		Stable:       simv1alpha1.HTTPBackendRef{Kind: "InferencePool", Name: "pool-stable", Namespace: "llm-d-sim"},
+		Candidate:    simv1alpha1.HTTPBackendRef{Kind: "InferencePool", Name: "pool-candidate", Namespace: "llm-d-sim"},
+		Steps:        []int32{5, 25, 50, 100},
+		StepDuration: &metav1.Duration{Duration: 10 * time.Minute},
+	}
+}
+
+func TestNextCanaryStatus(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	canary := newCanary()
+
+	status, wait := nextCanaryStatus(canary, nil, start, nil)
+	if status.Step != 0 || status.CandidateWeight != 5 || status.Phase != simv1alpha1.CanaryProgressing || wait != 10*time.Minute {
+		t.Fatalf("first step = %+v, wait %v", status, wait)
+	}
+
+	held, wait := nextCanaryStatus(canary, status, start.Add(4*time.Minute), nil)
+	if held.Step != 0 || wait != 6*time.Minute {
+		t.Errorf("step moved before the hold expired: %+v, wait %v", held, wait)
+	}
+
+	for i, want := range []int32{25, 50, 100} {
+		start = start.Add(10 * time.Minute)
+		status, wait = nextCanaryStatus(canary, status, start, nil)
+		if status.Step != int32(i+1) || status.CandidateWeight != want {
+			t.Fatalf("step %d = %+v, want weight %d", i+1, status, want)
+		}
+	}
+	if status.Phase != simv1alpha1.CanaryCompleted || wait != 0 {
+		t.Errorf("last step = %+v, wait %v, want Completed without requeue", status, wait)
+	}
+
+	// A new candidate restarts the schedule
+	canary.Candidate.Name = "pool-next"
+	restarted, _ := nextCanaryStatus(canary, status, start.Add(time.Hour), nil)
+	if restarted.Step != 0 || restarted.CandidateWeight != 5 || restarted.Revision == status.Revision {
+		t.Errorf("changed candidate did not restart the canary: %+v", restarted)
+	}
+}
+
+func TestSchedulerInstallReconcileCanary(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Spec.Canary = newCanary()
+	clock := clocktesting.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, install, eppService(install)))
+	r.Clock = clock
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(install)}
+	expectStatus := func(step, weight int32) {
+		t.Helper()
+		latest := &simv1alpha1.SchedulerInstall{}
+		if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+			t.Fatal(err)
+		}
+		if latest.Status.Canary == nil || latest.Status.Canary.Step != step || latest.Status.Canary.CandidateWeight != weight {
+			t.Errorf("status.canary = %+v, want step %d at %d%%", latest.Status.Canary, step, weight)
+		}
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	expectRouteWeights(t, r.Client, install, 95, 5)
+	expectStatus(0, 5)
+
+	clock.SetTime(clock.Now().Add(10 * time.Minute))
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	expectRouteWeights(t, r.Client, install, 75, 25)
+	expectStatus(1, 25)
+
+	// Disabling the canary restores the default backend and clears the status
+	latest := &simv1alpha1.SchedulerInstall{}
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatal(err)
+	}
+	latest.Spec.Canary.Enabled = false
+	if err := r.Update(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatal(err)
+	}
+	if latest.Status.Canary != nil {
+		t.Errorf("status.canary = %+v after disabling the canary", latest.Status.Canary)
+	}
+}
+
+// expectRouteWeights checks the stable and candidate weights of the canary HTTPRoute
+func expectRouteWeights(t *testing.T, c client.Client, install *simv1alpha1.SchedulerInstall, stable, candidate int64) {
+	t.Helper()
+	route := getUnstructured(t, c, httpRouteGVK, install.Namespace, "llm-d-inference-scheduling")
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	backends, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "backendRefs")
+	if len(backends) != 2 {
+		t.Fatalf("backendRefs = %v, want stable and candidate", backends)
+	}
+	got := [2]int64{backends[0].(map[string]interface{})["weight"].(int64), backends[1].(map[string]interface{})["weight"].(int64)}
+	if got != [2]int64{stable, candidate} {
+		t.Errorf("weights = %v, want [%d %d]", got, stable, candidate)
+	}
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+const (
+	// eppConfigHashAnnotation records the hash of the rendered plugin config on the EPP pod template
+	eppConfigHashAnnotation = "sim.llm-d.io/epp-config-hash"
+	// eppRestartedAtAnnotation mirrors simv1alpha1.RestartEPPAnnotation onto the EPP pod template
+	eppRestartedAtAnnotation = "sim.llm-d.io/restartedAt"
+	// kubectlRestartedAtAnnotation is set by `kubectl rollout restart`
+	kubectlRestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// rolloutAnnotations are pod template annotations that trigger a rollout when
+// they change. They are kept on the live Deployment when the desired template
+// does not set them, so that dropping one never restarts the pods by itself.
+var rolloutAnnotations = []string{
+	eppConfigHashAnnotation,
+	gatewayConfigHashAnnotation,
+	eppRestartedAtAnnotation,
+	kubectlRestartedAtAnnotation,
+}
+
+// configHash returns a stable hash of a rendered config file
+func configHash(config string) string {
+	sum := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(sum[:])
+}
+
+// setEPPRolloutAnnotations records the rendered config hash and the requested
+// restart token on the EPP pod template, so that either change rolls the pods
+func setEPPRolloutAnnotations(template *corev1.PodTemplateSpec, owner metav1.Object, restartPolicy, config string) {
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	if restartPolicy != simv1alpha1.EPPRestartManual {
+		template.Annotations[eppConfigHashAnnotation] = configHash(config)
+	}
+	if token := owner.GetAnnotations()[simv1alpha1.RestartEPPAnnotation]; token != "" {
+		template.Annotations[eppRestartedAtAnnotation] = token
+	}
+}
+
+// preserveRolloutAnnotations copies rollout annotations from the live pod
+// template into desired when desired does not set them
+func preserveRolloutAnnotations(live, desired *corev1.PodTemplateSpec) {
+	for _, key := range rolloutAnnotations {
+		value, ok := live.Annotations[key]
+		if !ok {
+			continue
+		}
+		if _, set := desired.Annotations[key]; set {
+			continue
+		}
+		if desired.Annotations == nil {
+			desired.Annotations = map[string]string{}
+		}
+		desired.Annotations[key] = value
+	}
+}
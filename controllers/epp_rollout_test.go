@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+func TestSetEPPRolloutAnnotations(t *testing.T) {
+	owner := &metav1.ObjectMeta{Annotations: map[string]string{simv1alpha1.RestartEPPAnnotation: "1700000000"}}
+
+	template := &corev1.PodTemplateSpec{}
+	setEPPRolloutAnnotations(template, owner, simv1alpha1.EPPRestartOnConfigChange, "a")
+	first := template.Annotations[eppConfigHashAnnotation]
+	if first == "" {
+		t.Fatalf("config hash annotation not set")
+	}
+	if got := template.Annotations[eppRestartedAtAnnotation]; got != "1700000000" {
+		t.Errorf("restartedAt = %q, want the restart token", got)
+	}
+
+	setEPPRolloutAnnotations(template, owner, simv1alpha1.EPPRestartOnConfigChange, "b")
+	if template.Annotations[eppConfigHashAnnotation] == first {
+		t.Errorf("config hash did not change with the config")
+	}
+
+	manual := &corev1.PodTemplateSpec{}
+	setEPPRolloutAnnotations(manual, &metav1.ObjectMeta{}, simv1alpha1.EPPRestartManual, "a")
+	if len(manual.Annotations) != 0 {
+		t.Errorf("Manual policy without a restart token set annotations: %v", manual.Annotations)
+	}
+}
+
+func TestPreserveRolloutAnnotations(t *testing.T) {
+	live := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		eppConfigHashAnnotation:      "old",
+		kubectlRestartedAtAnnotation: "2024-01-01T00:00:00Z",
+		"unrelated":                  "dropped",
+	}}}
+	desired := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		eppConfigHashAnnotation: "new",
+	}}}
+	preserveRolloutAnnotations(live, desired)
+
+	want := map[string]string{
+		eppConfigHashAnnotation:      "new",
+		kubectlRestartedAtAnnotation: "2024-01-01T00:00:00Z",
+	}
+	if len(desired.Annotations) != len(want) {
+		t.Fatalf("annotations = %v, want %v", desired.Annotations, want)
+	}
+	for key, value := range want {
+		if desired.Annotations[key] != value {
+			t.Errorf("%s = %q, want %q", key, desired.Annotations[key], value)
+		}
+	}
+}
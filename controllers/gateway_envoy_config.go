@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+const (
+	// gatewayConfigHashAnnotation records the hash of the rendered Envoy bootstrap on the gateway pod template
+	gatewayConfigHashAnnotation = "sim.llm-d.io/gateway-config-hash"
+	// gatewayListenerPort is where Envoy accepts requests inside the gateway pod
+	gatewayListenerPort = 80
+	// destinationEndpointHeader carries the endpoint the EPP picked, as <ip>:<port>
+	destinationEndpointHeader = "x-gateway-destination-endpoint"
+)
+
+// envoyLBPolicy maps a LoadBalancingConfig algorithm onto an Envoy cluster
+// lb_policy. Envoy has no least-connections policy; least-request is the
+// closest match.
+func envoyLBPolicy(lb *simv1alpha1.LoadBalancingConfig) string {
+	if lb == nil || !lb.Enabled {
+		return "ROUND_ROBIN"
+	}
+	switch lb.Algorithm {
+	case "LEAST_REQUEST", "LEAST_CONN":
+		return "LEAST_REQUEST"
+	case "RANDOM":
+		return "RANDOM"
+	default:
+		return "ROUND_ROBIN"
+	}
+}
+
+// renderGatewayEnvoyConfig renders the Envoy bootstrap of a standard gateway.
+// Requests go to the decode Service, or to the legacy Service without stages,
+// balanced as spec.loadBalancing asks. With ext_proc the EPP picks the decode
+// pod instead and Envoy forwards to the address it returns.
+func renderGatewayEnvoyConfig(simDep *simv1alpha1.SimulatorDeployment, names simulatorNames, config *simv1alpha1.GatewayInstanceConfig) (string, error) {
+	backendName, backendPort := serviceName(simDep, names), simDep.Spec.Service.Port
+	if simDep.Spec.Decode != nil && simDep.Spec.Decode.Enabled {
+		backendName, backendPort = names.stage("decode"), simDep.Spec.Decode.Port
+	}
+	extProc := config.ExtProc != nil && config.ExtProc.Enabled && simDep.Spec.EPP != nil && simDep.Spec.EPP.Enabled
+
+	var httpFilters []interface{}
+	clusters := []interface{}{simulatorCluster(simDep, backendName, backendPort, extProc)}
+	if extProc {
+		externalProcessor := map[string]interface{}{
+			"@type":              "type.googleapis.com/envoy.extensions.filters.http.ext_proc.v3.ExternalProcessor",
+			"grpc_service":       extProcGRPCService("epp_cluster", config.ExtProc.Timeout),
+			"processing_mode":    extProcProcessingMode(config.ExtProc.ProcessingMode),
+			"failure_mode_allow": config.ExtProc.FailureModeAllow == nil || *config.ExtProc.FailureModeAllow,
+		}
+		if config.ExtProc.MessageTimeout != nil {
+			externalProcessor["message_timeout"] = protoDuration(config.ExtProc.MessageTimeout.Duration)
+		}
+		httpFilters = append(httpFilters, map[string]interface{}{
+			"name":         "envoy.filters.http.ext_proc",
+			"typed_config": externalProcessor,
+		})
+		eppHost := fmt.Sprintf("%s.%s.svc.cluster.local", names.epp(), simDep.Namespace)
+		eppCluster := strictDNSCluster("epp_cluster", eppHost, simDep.Spec.EPP.Port, "ROUND_ROBIN")
+		eppCluster["http2_protocol_options"] = map[string]interface{}{}
+		clusters = append(clusters, eppCluster)
+	}
+	httpFilters = append(httpFilters, map[string]interface{}{
+		"name": "envoy.filters.http.router",
+		"typed_config": map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.extensions.filters.http.router.v3.Router",
+		},
+	})
+
+	bootstrap := map[string]interface{}{
+		"admin": map[string]interface{}{
+			"address": socketAddress("0.0.0.0", config.AdminPort),
+		},
+		"static_resources": map[string]interface{}{
+			"listeners": []interface{}{
+				map[string]interface{}{
+					"name":    "listener_0",
+					"address": socketAddress("0.0.0.0", gatewayListenerPort),
+					"filter_chains": []interface{}{
+						map[string]interface{}{
+							"filters": []interface{}{
+								map[string]interface{}{
+									"name": "envoy.filters.network.http_connection_manager",
+									"typed_config": map[string]interface{}{
+										"@type":       "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+										"stat_prefix": "ingress_http",
+										"route_config": map[string]interface{}{
+											"name": "local_route",
+											"virtual_hosts": []interface{}{
+												map[string]interface{}{
+													"name":    "backend",
+													"domains": []interface{}{"*"},
+													"routes": []interface{}{
+														map[string]interface{}{
+															"match": map[string]interface{}{"prefix": "/"},
+															"route": map[string]interface{}{"cluster": "simulator_cluster"},
+														},
+													},
+												},
+											},
+										},
+										"http_filters": httpFilters,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"clusters": clusters,
+		},
+	}
+	out, err := yaml.Marshal(bootstrap)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// simulatorCluster is the cluster requests are routed to. With ext_proc it is
+// an ORIGINAL_DST cluster that connects to the endpoint the EPP put in the
+// destination header.
+func simulatorCluster(simDep *simv1alpha1.SimulatorDeployment, host string, port int32, extProc bool) map[string]interface{} {
+	var cluster map[string]interface{}
+	if extProc {
+		cluster = map[string]interface{}{
+			"name":            "simulator_cluster",
+			"connect_timeout": "5s",
+			"type":            "ORIGINAL_DST",
+			"lb_policy":       "CLUSTER_PROVIDED",
+			"original_dst_lb_config": map[string]interface{}{
+				"use_http_header":  true,
+				"http_header_name": destinationEndpointHeader,
+			},
+		}
+	} else {
+		cluster = strictDNSCluster("simulator_cluster", host, port, envoyLBPolicy(simDep.Spec.LoadBalancing))
+	}
+
+	if lb := simDep.Spec.LoadBalancing; lb != nil && lb.Enabled && lb.ConnectionPool != nil {
+		cluster["circuit_breakers"] = map[string]interface{}{
+			"thresholds": []interface{}{
+				map[string]interface{}{"max_pending_requests": int64(lb.ConnectionPool.HTTP1MaxPendingRequests)},
+			},
+		}
+		cluster["typed_extension_protocol_options"] = map[string]interface{}{
+			"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": map[string]interface{}{
+				"@type": "type.googleapis.com/envoy.extensions.upstreams.http.v3.HttpProtocolOptions",
+				"common_http_protocol_options": map[string]interface{}{
+					"max_requests_per_connection": int64(lb.ConnectionPool.MaxRequestsPerConnection),
+				},
+				"explicit_http_config": map[string]interface{}{
+					"http_protocol_options": map[string]interface{}{},
+				},
+			},
+		}
+	}
+	return cluster
+}
+
+// strictDNSCluster is a STRICT_DNS cluster with a single host
+func strictDNSCluster(name, host string, port int32, lbPolicy string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":              name,
+		"connect_timeout":   "5s",
+		"type":              "STRICT_DNS",
+		"dns_lookup_family": "V4_ONLY",
+		"lb_policy":         lbPolicy,
+		"load_assignment": map[string]interface{}{
+			"cluster_name": name,
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"lb_endpoints": []interface{}{
+						map[string]interface{}{
+							"endpoint": map[string]interface{}{
+								"address": socketAddress(host, port),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func socketAddress(address string, port int32) map[string]interface{} {
+	return map[string]interface{}{
+		"socket_address": map[string]interface{}{
+			"address":    address,
+			"port_value": int64(port),
+		},
+	}
+}
@@ -0,0 +1,426 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+	"github.com/llm-d/llm-d-scheduler-sim-operator/loadgen"
+)
+
+// LoadTest condition types
+const (
+	conditionTargetResolved = "TargetResolved"
+	conditionComplete       = "Complete"
+)
+
+const (
+	// loadTestLabel ties the load generator Job and pods back to their LoadTest
+	loadTestLabel = "sim.llm-d.io/loadTest"
+	// loadGeneratorContainer is the container whose termination message carries the results
+	loadGeneratorContainer = "loadgen"
+	// loadTestGracePeriod is added to duration plus request timeout before the Job is stopped
+	loadTestGracePeriod = time.Minute
+	// loadTestPollInterval polls an unresolved target, whose status is written by other controllers
+	loadTestPollInterval = 15 * time.Second
+	// loadTestTraceDir is where the ConfigMaps of Trace phases are mounted, one directory per phase
+	loadTestTraceDir = "/etc/loadgen/traces"
+)
+
+// LoadTestReconciler reconciles a LoadTest object
+type LoadTestReconciler struct {
+	client.Client
+	Scheme     *runtime.Scheme
+	RESTMapper meta.RESTMapper
+	// Clock stamps the start and completion times; defaults to the real clock
+	Clock clock.PassiveClock
+}
+
+//+kubebuilder:rbac:groups=sim.llm-d.io,resources=loadtests,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=sim.llm-d.io,resources=loadtests/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=sim.llm-d.io,resources=loadtests/finalizers,verbs=update
+//+kubebuilder:rbac:groups=sim.llm-d.io,resources=simulatordeployments;schedulerinstalls,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
+
+// Reconcile resolves the target of a LoadTest, runs the load generator Job
+// once and copies its results into the status. A finished LoadTest is left
+// alone.
+func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	loadTest := &simv1alpha1.LoadTest{}
+	if err := r.Get(ctx, req.NamespacedName, loadTest); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if phase := loadTest.Status.Phase; phase == simv1alpha1.LoadTestSucceeded || phase == simv1alpha1.LoadTestFailed {
+		return ctrl.Result{}, nil
+	}
+
+	// The Job below is built from the defaulted image, phases and request shape
+	loadTest.Default()
+	status := loadTest.Status.DeepCopy()
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: loadTest.Name, Namespace: loadTest.Namespace}, job)
+	switch {
+	case errors.IsNotFound(err):
+		// Without the webhook an invalid spec is only caught here; it never runs
+		if err := loadTest.Validate(); err != nil {
+			status.Phase = simv1alpha1.LoadTestFailed
+			setLoadTestCondition(loadTest, status, metav1.Condition{
+				Type: conditionComplete, Status: metav1.ConditionFalse, Reason: "InvalidSpec", Message: err.Error(),
+			})
+			return ctrl.Result{}, r.updateStatus(ctx, loadTest, status)
+		}
+		targetURL, resolved := r.resolveTarget(ctx, loadTest)
+		setLoadTestCondition(loadTest, status, resolved)
+		if resolved.Status != metav1.ConditionTrue {
+			status.Phase = simv1alpha1.LoadTestPending
+			if err := r.updateStatus(ctx, loadTest, status); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: loadTestPollInterval}, nil
+		}
+
+		job = buildLoadTestJob(loadTest, targetURL)
+		if err := controllerutil.SetControllerReference(loadTest, job, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("starting load test", "job", job.Name, "target", targetURL)
+		if err := r.Create(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+		now := metav1.NewTime(r.now())
+		status.Phase = simv1alpha1.LoadTestRunning
+		status.TargetURL = targetURL
+		status.JobName = job.Name
+		status.StartTime = &now
+		setLoadTestCondition(loadTest, status, metav1.Condition{
+			Type: conditionComplete, Status: metav1.ConditionFalse, Reason: "Running",
+			Message: fmt.Sprintf("Job %s is sending requests", job.Name),
+		})
+		return ctrl.Result{}, r.updateStatus(ctx, loadTest, status)
+	case err != nil:
+		return ctrl.Result{}, err
+	}
+
+	if !metav1.IsControlledBy(job, loadTest) {
+		return ctrl.Result{}, fmt.Errorf("job %s/%s exists and is not owned by the LoadTest", job.Namespace, job.Name)
+	}
+	status.JobName = job.Name
+	finished, failure := jobFinished(job)
+	if !finished {
+		status.Phase = simv1alpha1.LoadTestRunning
+		return ctrl.Result{}, r.updateStatus(ctx, loadTest, status)
+	}
+
+	completion := metav1.NewTime(r.now())
+	if job.Status.CompletionTime != nil {
+		completion = *job.Status.CompletionTime
+	}
+	status.CompletionTime = &completion
+	if failure != "" {
+		status.Phase = simv1alpha1.LoadTestFailed
+		setLoadTestCondition(loadTest, status, metav1.Condition{
+			Type: conditionComplete, Status: metav1.ConditionFalse, Reason: "JobFailed", Message: failure,
+		})
+		logger.Info("load test failed", "job", job.Name, "reason", failure)
+		return ctrl.Result{}, r.updateStatus(ctx, loadTest, status)
+	}
+
+	results, err := r.jobResults(ctx, job)
+	if err != nil {
+		status.Phase = simv1alpha1.LoadTestFailed
+		setLoadTestCondition(loadTest, status, metav1.Condition{
+			Type: conditionComplete, Status: metav1.ConditionFalse, Reason: "ResultsUnavailable", Message: err.Error(),
+		})
+		return ctrl.Result{}, r.updateStatus(ctx, loadTest, status)
+	}
+	status.Phase = simv1alpha1.LoadTestSucceeded
+	status.Results = results
+	setLoadTestCondition(loadTest, status, metav1.Condition{
+		Type: conditionComplete, Status: metav1.ConditionTrue, Reason: "Succeeded",
+		Message: fmt.Sprintf("%d requests, %d errors", results.Requests, results.Errors),
+	})
+	logger.Info("load test finished", "job", job.Name, "requests", results.Requests, "errors", results.Errors)
+	return ctrl.Result{}, r.updateStatus(ctx, loadTest, status)
+}
+
+func (r *LoadTestReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// resolveTarget returns the base URL of the target and the TargetResolved
+// condition; the URL is only set when the condition is true
+func (r *LoadTestReconciler) resolveTarget(ctx context.Context, loadTest *simv1alpha1.LoadTest) (string, metav1.Condition) {
+	pending := func(reason, format string, args ...interface{}) (string, metav1.Condition) {
+		return "", metav1.Condition{Type: conditionTargetResolved, Status: metav1.ConditionFalse, Reason: reason, Message: fmt.Sprintf(format, args...)}
+	}
+	resolved := func(url, format string, args ...interface{}) (string, metav1.Condition) {
+		return url, metav1.Condition{Type: conditionTargetResolved, Status: metav1.ConditionTrue, Reason: "Resolved", Message: fmt.Sprintf(format, args...)}
+	}
+
+	target := loadTest.Spec.Target
+	key := types.NamespacedName{Namespace: loadTest.Namespace}
+	switch {
+	case target.URL != "":
+		return resolved(target.URL, "Sending requests to %s", target.URL)
+
+	case target.SimulatorDeployment != "":
+		key.Name = target.SimulatorDeployment
+		simDep := &simv1alpha1.SimulatorDeployment{}
+		if err := r.Get(ctx, key, simDep); err != nil {
+			if errors.IsNotFound(err) {
+				return pending("TargetNotFound", "SimulatorDeployment %s does not exist", key.Name)
+			}
+			return pending("TargetError", "%v", err)
+		}
+		if simDep.Status.GatewayURL == "" {
+			return pending("TargetNotReady", "SimulatorDeployment %s has not reported a gatewayURL yet", key.Name)
+		}
+		return resolved(simDep.Status.GatewayURL, "Sending requests to the gateway of SimulatorDeployment %s", key.Name)
+
+	case target.SchedulerInstall != "":
+		key.Name = target.SchedulerInstall
+		install := &simv1alpha1.SchedulerInstall{}
+		if err := r.Get(ctx, key, install); err != nil {
+			if errors.IsNotFound(err) {
+				return pending("TargetNotFound", "SchedulerInstall %s does not exist", key.Name)
+			}
+			return pending("TargetError", "%v", err)
+		}
+		install.Default()
+		if install.Spec.Gateway == nil || !install.Spec.Gateway.Enabled {
+			return pending("TargetNotReady", "SchedulerInstall %s does not enable a gateway", key.Name)
+		}
+		if !r.gvkSupported(gatewayGVK) {
+			return pending("CRDNotInstalled", "the %s CRD is not installed", gatewayGVK.GroupVersion())
+		}
+		gateway := &unstructured.Unstructured{}
+		gateway.SetGroupVersionKind(gatewayGVK)
+		gatewayKey := types.NamespacedName{Name: install.Spec.Gateway.Name, Namespace: install.Spec.SchedulerNamespace}
+		if err := r.Get(ctx, gatewayKey, gateway); err != nil {
+			if errors.IsNotFound(err) {
+				return pending("TargetNotReady", "Gateway %s does not exist yet", gatewayKey)
+			}
+			return pending("TargetError", "%v", err)
+		}
+		addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+		for _, address := range addresses {
+			addressMap, ok := address.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if value, _ := addressMap["value"].(string); value != "" {
+				url := fmt.Sprintf("http://%s:%d", value, install.Spec.Gateway.ListenerPort)
+				return resolved(url, "Sending requests to Gateway %s of SchedulerInstall %s", gatewayKey, key.Name)
+			}
+		}
+		return pending("TargetNotReady", "Gateway %s has not reported an address yet", gatewayKey)
+	}
+	return pending("NoTarget", "spec.target sets none of schedulerInstall, simulatorDeployment and url")
+}
+
+func (r *LoadTestReconciler) gvkSupported(gvk schema.GroupVersionKind) bool {
+	if r.RESTMapper == nil {
+		return true
+	}
+	_, err := r.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	return err == nil
+}
+
+// buildLoadTestJob runs the load generator once. The Job is stopped a grace
+// period after the last request, of the last phase, could have timed out.
+func buildLoadTestJob(loadTest *simv1alpha1.LoadTest, targetURL string) *batchv1.Job {
+	spec := loadTest.Spec
+	labels := map[string]string{
+		"app.kubernetes.io/name":      "llm-d-sim-loadgen",
+		"app.kubernetes.io/component": "load-generator",
+		loadTestLabel:                 loadTest.Name,
+	}
+	args := []string{
+		"--url", targetURL,
+		"--api", spec.API,
+		"--model", spec.Model,
+		"--concurrency", strconv.Itoa(int(spec.Concurrency)),
+		"--timeout", spec.RequestTimeout.Duration.String(),
+		"--max-tokens", strconv.Itoa(int(spec.MaxTokens)),
+		"--prompt-distribution", spec.PromptLength.Distribution,
+		"--prompt-mean", strconv.Itoa(int(spec.PromptLength.Mean)),
+		"--prompt-min", strconv.Itoa(int(spec.PromptLength.Min)),
+		"--prompt-max", strconv.Itoa(int(spec.PromptLength.Max)),
+		"--prompt-stddev", strconv.Itoa(int(spec.PromptLength.StdDev)),
+	}
+	if prefix := spec.SharedPrefix; prefix != nil {
+		args = append(args,
+			"--prefix-percent", strconv.Itoa(int(prefix.Percent)),
+			"--prefix-length", strconv.Itoa(int(prefix.Length)),
+			"--prefix-groups", strconv.Itoa(int(prefix.Groups)),
+		)
+	}
+
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	var duration time.Duration
+	if len(spec.Phases) == 0 {
+		duration = spec.Duration.Duration
+		args = append(args,
+			"--rate", strconv.Itoa(int(spec.RequestsPerSecond)),
+			"--duration", duration.String(),
+		)
+	} else {
+		phases := make([]loadgen.Phase, 0, len(spec.Phases))
+		for i, p := range spec.Phases {
+			duration += p.Duration.Duration
+			phase := loadgen.Phase{LoadPhase: *p.DeepCopy()}
+			if p.Trace != nil {
+				// Phase names may be too long for volume names
+				volume := fmt.Sprintf("trace-%d", i)
+				dir := path.Join(loadTestTraceDir, p.Name)
+				volumes = append(volumes, corev1.Volume{
+					Name: volume,
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: p.Trace.LocalObjectReference,
+						Items:                []corev1.KeyToPath{{Key: p.Trace.Key, Path: "arrivals"}},
+						Optional:             p.Trace.Optional,
+					}},
+				})
+				mounts = append(mounts, corev1.VolumeMount{Name: volume, MountPath: dir, ReadOnly: true})
+				phase.Trace = nil
+				phase.TraceFile = path.Join(dir, "arrivals")
+			}
+			phases = append(phases, phase)
+		}
+		// LoadPhase only holds strings, numbers and durations
+		encoded, _ := json.Marshal(phases)
+		args = append(args, "--phases", string(encoded))
+	}
+
+	backoffLimit := int32(0)
+	deadline := int64(math.Ceil((duration + spec.RequestTimeout.Duration + loadTestGracePeriod).Seconds()))
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      loadTest.Name,
+			Namespace: loadTest.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:                     loadGeneratorContainer,
+							Image:                    spec.Image,
+							ImagePullPolicy:          corev1.PullIfNotPresent,
+							Args:                     args,
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+							Resources:                spec.Resources,
+							VolumeMounts:             mounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// jobFinished reports whether the Job has finished and, if it failed, why
+func jobFinished(job *batchv1.Job) (bool, string) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return true, ""
+		case batchv1.JobFailed:
+			if c.Message != "" {
+				return true, fmt.Sprintf("%s: %s", c.Reason, c.Message)
+			}
+			return true, string(c.Reason)
+		}
+	}
+	return false, ""
+}
+
+// jobResults decodes the results the load generator wrote to its termination message
+func (r *LoadTestReconciler) jobResults(ctx context.Context, job *batchv1.Job) (*simv1alpha1.LoadTestResults, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Status.ContainerStatuses {
+			terminated := container.State.Terminated
+			if container.Name != loadGeneratorContainer || terminated == nil || terminated.ExitCode != 0 {
+				continue
+			}
+			results := &simv1alpha1.LoadTestResults{}
+			if err := json.Unmarshal([]byte(terminated.Message), results); err != nil {
+				return nil, fmt.Errorf("pod %s reported unreadable results: %w", pod.Name, err)
+			}
+			return results, nil
+		}
+	}
+	return nil, fmt.Errorf("no pod of job %s reported results", job.Name)
+}
+
+func setLoadTestCondition(loadTest *simv1alpha1.LoadTest, status *simv1alpha1.LoadTestStatus, condition metav1.Condition) {
+	condition.ObservedGeneration = loadTest.Generation
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+func (r *LoadTestReconciler) updateStatus(ctx context.Context, loadTest *simv1alpha1.LoadTest, status *simv1alpha1.LoadTestStatus) error {
+	latest := &simv1alpha1.LoadTest{}
+	if err := r.Get(ctx, types.NamespacedName{Name: loadTest.Name, Namespace: loadTest.Namespace}, latest); err != nil {
+		return err
+	}
+	latest.Status = *status
+	return r.Status().Update(ctx, latest)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LoadTestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.RESTMapper == nil {
+		r.RESTMapper = mgr.GetRESTMapper()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&simv1alpha1.LoadTest{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+	"github.com/llm-d/llm-d-scheduler-sim-operator/loadgen"
+)
+
+// newLoadTest returns a LoadTest against the llm-sim-full SimulatorDeployment, like the sample manifest
+func newLoadTest(namespace string) *simv1alpha1.LoadTest {
+	return &simv1alpha1.LoadTest{
+		ObjectMeta: metav1.ObjectMeta{Name: "prefix-cache", Namespace: namespace, UID: "loadtest-uid"},
+		Spec: simv1alpha1.LoadTestSpec{
+			Target:            simv1alpha1.LoadTestTarget{SimulatorDeployment: "llm-sim-full"},
+			RequestsPerSecond: 20,
+			Concurrency:       8,
+			Duration:          &metav1.Duration{Duration: 2 * time.Minute},
+			PromptLength: &simv1alpha1.PromptLengthDistribution{
+				Distribution: simv1alpha1.PromptLengthNormal, Mean: 512, StdDev: 128, Min: 256, Max: 1024,
+			},
+			SharedPrefix: &simv1alpha1.SharedPrefixConfig{Percent: 50, Length: 200, Groups: 4},
+		},
+	}
+}
+
+func newLoadTestReconciler(scheme *runtime.Scheme, c client.Client) *LoadTestReconciler {
+	return &LoadTestReconciler{
+		Client:     c,
+		Scheme:     scheme,
+		RESTMapper: newRESTMapper(gatewayGVK),
+		Clock:      clocktesting.NewFakePassiveClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)),
+	}
+}
+
+func getLoadTest(t *testing.T, c client.Client, loadTest *simv1alpha1.LoadTest) *simv1alpha1.LoadTest {
+	t.Helper()
+	latest := &simv1alpha1.LoadTest{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(loadTest), latest); err != nil {
+		t.Fatalf("get LoadTest: %v", err)
+	}
+	return latest
+}
+
+func expectLoadTestCondition(t *testing.T, loadTest *simv1alpha1.LoadTest, conditionType string, status metav1.ConditionStatus, reason string) {
+	t.Helper()
+	c := meta.FindStatusCondition(loadTest.Status.Conditions, conditionType)
+	if c == nil || c.Status != status || c.Reason != reason {
+		t.Errorf("condition %s = %+v, want %s/%s", conditionType, c, status, reason)
+	}
+}
+
+func TestLoadTestReconcileSimulatorTarget(t *testing.T) {
+	scheme := newScheme()
+	loadTest := newLoadTest("llm-d-sim")
+	r := newLoadTestReconciler(scheme, newFakeClient(scheme, loadTest))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(loadTest)}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if result.RequeueAfter != loadTestPollInterval {
+		t.Errorf("requeueAfter = %v, want %v while the target is missing", result.RequeueAfter, loadTestPollInterval)
+	}
+	latest := getLoadTest(t, r.Client, loadTest)
+	if latest.Status.Phase != simv1alpha1.LoadTestPending {
+		t.Errorf("phase = %q, want Pending", latest.Status.Phase)
+	}
+	expectLoadTestCondition(t, latest, conditionTargetResolved, metav1.ConditionFalse, "TargetNotFound")
+
+	// The simulator has no gateway address yet
+	simDep := newSimulatorDeployment("llm-d-sim")
+	if err := r.Create(ctx, simDep); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	expectLoadTestCondition(t, getLoadTest(t, r.Client, loadTest), conditionTargetResolved, metav1.ConditionFalse, "TargetNotReady")
+
+	simDep.Status.GatewayURL = "http://llm-sim-full-inference-gateway.llm-d-sim.svc.cluster.local:8080"
+	if err := r.Status().Update(ctx, simDep); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	latest = getLoadTest(t, r.Client, loadTest)
+	if latest.Status.Phase != simv1alpha1.LoadTestRunning || latest.Status.TargetURL != simDep.Status.GatewayURL || latest.Status.JobName != "prefix-cache" {
+		t.Errorf("status = %+v, want Running against the simulator gateway in Job prefix-cache", latest.Status)
+	}
+	if latest.Status.StartTime == nil {
+		t.Errorf("startTime is not set")
+	}
+	expectLoadTestCondition(t, latest, conditionTargetResolved, metav1.ConditionTrue, "Resolved")
+	expectLoadTestCondition(t, latest, conditionComplete, metav1.ConditionFalse, "Running")
+
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "llm-d-sim", Name: "prefix-cache"}, job); err != nil {
+		t.Fatalf("get Job: %v", err)
+	}
+	expectGolden(t, scheme, "loadtest-job", job)
+}
+
+func TestLoadTestReconcileSchedulerInstallTarget(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Default()
+	loadTest := newLoadTest(install.Namespace)
+	loadTest.Spec.Target = simv1alpha1.LoadTestTarget{SchedulerInstall: install.Name}
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	gateway.SetNamespace(install.Namespace)
+	gateway.SetName(install.Spec.Gateway.Name)
+	r := newLoadTestReconciler(scheme, newFakeClient(scheme, loadTest, install, gateway))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(loadTest)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	expectLoadTestCondition(t, getLoadTest(t, r.Client, loadTest), conditionTargetResolved, metav1.ConditionFalse, "TargetNotReady")
+
+	// The Gateway controller reports an address
+	if err := unstructured.SetNestedSlice(gateway.Object, []interface{}{
+		map[string]interface{}{"type": "IPAddress", "value": "10.96.0.30"},
+	}, "status", "addresses"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(ctx, gateway); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got := getLoadTest(t, r.Client, loadTest).Status.TargetURL; got != "http://10.96.0.30:80" {
+		t.Errorf("targetURL = %q, want the Gateway address on the listener port", got)
+	}
+}
+
+func TestLoadTestReconcileResults(t *testing.T) {
+	results := simv1alpha1.LoadTestResults{
+		Requests:         100,
+		Succeeded:        98,
+		Errors:           2,
+		ErrorsByReason:   map[string]int64{"429": 2},
+		Throughput:       "0.82",
+		TimeToFirstToken: &simv1alpha1.LatencyPercentiles{P99: metav1.Duration{Duration: 120 * time.Millisecond}},
+		Latency:          &simv1alpha1.LatencyPercentiles{P99: metav1.Duration{Duration: 900 * time.Millisecond}},
+		Routing:          map[string]int64{"llm-sim-full-decode-abc": 60, "llm-sim-full-decode-def": 38},
+	}
+	message, err := json.Marshal(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		condition batchv1.JobConditionType
+		message   string
+		phase     string
+		reason    string
+	}{
+		{name: "succeeded", condition: batchv1.JobComplete, message: string(message), phase: simv1alpha1.LoadTestSucceeded, reason: "Succeeded"},
+		{name: "job failed", condition: batchv1.JobFailed, phase: simv1alpha1.LoadTestFailed, reason: "JobFailed"},
+		{name: "unreadable results", condition: batchv1.JobComplete, message: "connection refused", phase: simv1alpha1.LoadTestFailed, reason: "ResultsUnavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := newScheme()
+			loadTest := newLoadTest("llm-d-sim")
+			loadTest.Spec.Target = simv1alpha1.LoadTestTarget{URL: "http://gateway.llm-d-sim.svc:80"}
+			loadTest.Default()
+			job := buildLoadTestJob(loadTest, loadTest.Spec.Target.URL)
+			if err := controllerutil.SetControllerReference(loadTest, job, scheme); err != nil {
+				t.Fatal(err)
+			}
+			job.Status.Conditions = []batchv1.JobCondition{{Type: tt.condition, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "prefix-cache-x7k2p", Namespace: "llm-d-sim", Labels: map[string]string{"job-name": job.Name}},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  loadGeneratorContainer,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: tt.message}},
+				}}},
+			}
+			r := newLoadTestReconciler(scheme, newFakeClient(scheme, loadTest, job, pod))
+
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(loadTest)}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile: %v", err)
+			}
+			latest := getLoadTest(t, r.Client, loadTest)
+			if latest.Status.Phase != tt.phase || latest.Status.CompletionTime == nil {
+				t.Errorf("phase = %q completionTime = %v, want %s with a completion time", latest.Status.Phase, latest.Status.CompletionTime, tt.phase)
+			}
+			expectLoadTestCondition(t, latest, conditionComplete, conditionStatus(tt.phase == simv1alpha1.LoadTestSucceeded), tt.reason)
+			if tt.phase == simv1alpha1.LoadTestSucceeded && !equalResults(t, latest.Status.Results, &results) {
+				t.Errorf("results = %+v, want %+v", latest.Status.Results, results)
+			}
+
+			// A finished LoadTest is not run again
+			if err := r.Delete(context.Background(), job); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile: %v", err)
+			}
+			if err := r.Get(context.Background(), client.ObjectKeyFromObject(job), &batchv1.Job{}); err == nil {
+				t.Errorf("the Job of a finished LoadTest was recreated")
+			}
+		})
+	}
+}
+
+func TestLoadTestReconcileInvalidSpec(t *testing.T) {
+	scheme := newScheme()
+	loadTest := newLoadTest("llm-d-sim")
+	loadTest.Spec.Target.URL = "http://gateway.llm-d-sim.svc:80"
+	r := newLoadTestReconciler(scheme, newFakeClient(scheme, loadTest))
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(loadTest)}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	latest := getLoadTest(t, r.Client, loadTest)
+	if latest.Status.Phase != simv1alpha1.LoadTestFailed {
+		t.Errorf("phase = %q, want Failed for two targets", latest.Status.Phase)
+	}
+	expectLoadTestCondition(t, latest, conditionComplete, metav1.ConditionFalse, "InvalidSpec")
+}
+
+func TestBuildLoadTestJobPhases(t *testing.T) {
+	scheme := newScheme()
+	loadTest := newLoadTest("llm-d-sim")
+	loadTest.Spec.RequestsPerSecond = 0
+	loadTest.Spec.Duration = nil
+	loadTest.Spec.Phases = []simv1alpha1.LoadPhase{
+		{Name: "ramp-up", Shape: simv1alpha1.LoadShapeRamp, Duration: metav1.Duration{Duration: time.Minute}, From: 1, To: 40},
+		{Name: "diurnal", Shape: simv1alpha1.LoadShapeSinusoidal, Duration: metav1.Duration{Duration: 5 * time.Minute}, RequestsPerSecond: 20, Amplitude: 15},
+		{Name: "replay", Shape: simv1alpha1.LoadShapeTrace, Duration: metav1.Duration{Duration: 2 * time.Minute},
+			Trace: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "arrivals"}, Key: "production"}},
+	}
+	loadTest.Default()
+	if err := loadTest.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	job := buildLoadTestJob(loadTest, "http://gateway.llm-d-sim.svc:80")
+	// 8 minutes of phases, the 30s request timeout and the grace period
+	if got := *job.Spec.ActiveDeadlineSeconds; got != 570 {
+		t.Errorf("activeDeadlineSeconds = %d, want 570", got)
+	}
+	args := job.Spec.Template.Spec.Containers[0].Args
+	var phases []loadgen.Phase
+	for i, arg := range args {
+		if arg == "--rate" || arg == "--duration" {
+			t.Errorf("%s is passed with phases", arg)
+		}
+		if arg == "--phases" && i+1 < len(args) {
+			if err := json.Unmarshal([]byte(args[i+1]), &phases); err != nil {
+				t.Fatalf("--phases: %v", err)
+			}
+		}
+	}
+	if len(phases) != 3 || phases[1].Period == nil || phases[2].Trace != nil || phases[2].TraceFile != "/etc/loadgen/traces/replay/arrivals" {
+		t.Errorf("phases = %+v, want the defaulted phases with the trace read from its mount", phases)
+	}
+	expectGolden(t, scheme, "loadtest-job-phases", job)
+}
+
+func conditionStatus(ok bool) metav1.ConditionStatus {
+	if ok {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
+
+func equalResults(t *testing.T, got, want *simv1alpha1.LoadTestResults) bool {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(gotJSON) == string(wantJSON)
+}
@@ -3,7 +3,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -15,30 +18,101 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
 )
 
+const schedulerInstallFinalizer = "sim.llm-d.io/schedulerinstall-cleanup"
+
+// Labels that tie objects created outside the SchedulerInstall's namespace back to it
+const (
+	schedulerInstallLabel   = "sim.llm-d.io/schedulerInstall"
+	schedulerNamespaceLabel = "sim.llm-d.io/schedulerNamespace"
+)
+
+// GVKs of the optional resources SchedulerInstall manages through unstructured objects
+var (
+	gatewayGVK         = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+	httpRouteGVK       = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	referenceGrantGVK  = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "ReferenceGrant"}
+	destinationRuleGVK = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "DestinationRule"}
+	envoyFilterGVK     = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "EnvoyFilter"}
+	inferencePoolGVK   = schema.GroupVersionKind{Group: "inference.networking.k8s.io", Version: "v1", Kind: "InferencePool"}
+)
+
+// crossNamespaceKinds lists the kinds SchedulerInstall creates outside its own
+// namespace. They cannot carry owner references, so they are tracked by
+// crossNamespaceLabels and removed explicitly.
+var crossNamespaceKinds = []schema.GroupVersionKind{
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+	referenceGrantGVK,
+	destinationRuleGVK,
+	inferencePoolGVK,
+}
+
+// watchedGVKs lists the optional kinds SchedulerInstall watches for drift when their CRDs are installed
+var watchedGVKs = []schema.GroupVersionKind{
+	gatewayGVK,
+	httpRouteGVK,
+	referenceGrantGVK,
+	destinationRuleGVK,
+	envoyFilterGVK,
+	inferencePoolGVK,
+}
+
+// SchedulerInstall condition types, one per managed area
+const (
+	conditionReady                = "Ready"
+	conditionEPPReady             = "EPPReady"
+	conditionGatewayProgrammed    = "GatewayProgrammed"
+	conditionRouteAccepted        = "RouteAccepted"
+	conditionReferenceGrantReady  = "ReferenceGrantReady"
+	conditionDestinationRuleReady = "DestinationRuleReady"
+	conditionEnvoyFilterApplied   = "EnvoyFilterApplied"
+	conditionInferencePoolReady   = "InferencePoolReady"
+	conditionSimulatorReady       = "SimulatorReady"
+	conditionCRDsMissing          = "CRDsMissing"
+)
+
+// crossNamespaceKey identifies a cross-namespace object by kind and name
+type crossNamespaceKey struct {
+	Kind string
+	types.NamespacedName
+}
+
 // SchedulerInstallReconciler reconciles a SchedulerInstall object
 type SchedulerInstallReconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
 	RESTMapper meta.RESTMapper
+	// Clock times canary steps; defaults to the real clock
+	Clock clock.PassiveClock
+	// HTTPClient scrapes the metrics of canary analysis; defaults to a client with a 5s timeout
+	HTTPClient *http.Client
 }
 
 //+kubebuilder:rbac:groups=sim.llm-d.io,resources=schedulerinstalls,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=sim.llm-d.io,resources=schedulerinstalls/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sim.llm-d.io,resources=schedulerinstalls/finalizers,verbs=update
+//+kubebuilder:rbac:groups=sim.llm-d.io,resources=simulatordeployments,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=services;configmaps;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways;httproutes;referencegrants,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.istio.io,resources=destinationrules;envoyfilters,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=inference.networking.k8s.io,resources=inferencepools,verbs=get;list;watch;create;update;patch;delete
 
 func (r *SchedulerInstallReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -51,183 +125,206 @@ func (r *SchedulerInstallReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
-	r.setDefaults(install)
-	if install.Spec.SimulatorNamespace == "" {
-		logger.Error(fmt.Errorf("spec.simulatorNamespace is required"), "invalid SchedulerInstall")
-		return ctrl.Result{}, fmt.Errorf("spec.simulatorNamespace is required")
+	if !install.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, install)
 	}
 
-	if install.Spec.EPP != nil && install.Spec.EPP.Enabled {
-		if err := r.reconcileSchedulerEPP(ctx, install); err != nil {
+	if !controllerutil.ContainsFinalizer(install, schedulerInstallFinalizer) {
+		controllerutil.AddFinalizer(install, schedulerInstallFinalizer)
+		if err := r.Update(ctx, install); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
-	if install.Spec.Gateway != nil && install.Spec.Gateway.Enabled {
-		if err := r.reconcileGateway(ctx, install); err != nil {
-			return ctrl.Result{}, err
+	// Resolve the namespaces and object names the spec leaves to the defaults
+	install.Default()
+	conditions := &installConditions{}
+	if install.Spec.SimulatorNamespace == "" {
+		err := fmt.Errorf("spec.simulatorNamespace is required")
+		logger.Error(err, "invalid SchedulerInstall")
+		conditions.errs = append(conditions.errs, err)
+		if statusErr := r.updateStatus(ctx, install, conditions); statusErr != nil {
+			logger.Error(statusErr, "failed to update status")
 		}
-	}
-
-	if err := r.reconcileProxyService(ctx, install); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if install.Spec.Routing != nil && install.Spec.Routing.Enabled {
-		if install.Spec.Routing.BackendType == "InferencePool" {
-			if install.Spec.Routing.InferencePool == nil || install.Spec.Routing.InferencePool.Name == "" {
-				return ctrl.Result{}, fmt.Errorf("routing.backendType=InferencePool requires routing.inferencePool.name")
+	// The simulator supplies the selectors and target ports the spec leaves unset
+	if install.Spec.SimulatorRef != nil {
+		bound, err := r.bindSimulator(ctx, install, conditions)
+		if err != nil {
+			conditions.failed(conditionSimulatorReady, err)
+		}
+		if !bound {
+			// Nothing would select the simulator pods; the SimulatorDeployment watch retries once it exists
+			if statusErr := r.updateStatus(ctx, install, conditions); statusErr != nil {
+				logger.Error(statusErr, "failed to update status")
 			}
-		}
-		if err := r.reconcileReferenceGrant(ctx, install); err != nil {
-			return ctrl.Result{}, err
-		}
-		if err := r.reconcileHTTPRoute(ctx, install); err != nil {
 			return ctrl.Result{}, err
 		}
+	} else {
+		install.Status.Simulator = nil
+		conditions.disabled(conditionSimulatorReady)
 	}
 
-	if install.Spec.DestinationRule != nil && install.Spec.DestinationRule.Enabled {
-		if err := r.reconcileDestinationRule(ctx, install); err != nil {
-			return ctrl.Result{}, err
+	// Each area is reconciled independently so that one failure does not hide the state of the others
+	if install.Spec.EPP != nil && install.Spec.EPP.Enabled {
+		if err := r.reconcileSchedulerEPP(ctx, install); err != nil {
+			conditions.failed(conditionEPPReady, err)
+		} else if err := r.eppCondition(ctx, install, conditions); err != nil {
+			conditions.failed(conditionEPPReady, err)
 		}
+	} else {
+		conditions.disabled(conditionEPPReady)
 	}
 
-	if install.Spec.EnvoyFilter != nil && install.Spec.EnvoyFilter.Enabled {
-		if install.Spec.EPP == nil || !install.Spec.EPP.Enabled {
-			return ctrl.Result{}, fmt.Errorf("envoyFilter requires epp.enabled=true")
-		}
-		if len(install.Spec.EnvoyFilter.WorkloadSelector) == 0 {
-			return ctrl.Result{}, fmt.Errorf("envoyFilter requires workloadSelector (or gateway configured for default selector)")
-		}
-		if err := r.reconcileEnvoyFilter(ctx, install); err != nil {
-			return ctrl.Result{}, err
-		}
-	} else if install.Spec.EnvoyFilter != nil && !install.Spec.EnvoyFilter.Enabled {
-		if err := r.deleteEnvoyFilter(ctx, install); err != nil {
-			return ctrl.Result{}, err
-		}
+	if install.Spec.Gateway != nil && install.Spec.Gateway.Enabled {
+		if !r.gvkSupported(gatewayGVK) {
+			conditions.skipped(conditionGatewayProgrammed, gatewayGVK)
+		} else if err := r.reconcileGateway(ctx, install); err != nil {
+			conditions.failed(conditionGatewayProgrammed, err)
+		} else if err := r.gatewayCondition(ctx, install, conditions); err != nil {
+			conditions.failed(conditionGatewayProgrammed, err)
+		}
+	} else {
+		conditions.disabled(conditionGatewayProgrammed)
 	}
 
-	if err := r.updateStatus(ctx, install, true, "Reconciled", "SchedulerInstall resources are ready"); err != nil {
-		logger.Error(err, "failed to update status")
-		return ctrl.Result{}, err
+	if err := r.reconcileProxyService(ctx, install); err != nil {
+		conditions.errs = append(conditions.errs, err)
 	}
 
-	return ctrl.Result{}, nil
-}
-
-func (r *SchedulerInstallReconciler) setDefaults(install *simv1alpha1.SchedulerInstall) {
-	if install.Spec.SchedulerNamespace == "" {
-		install.Spec.SchedulerNamespace = install.Namespace
-	}
-	if install.Spec.ProxyService.Name == "" {
-		install.Spec.ProxyService.Name = "gaie-inference-scheduling-proxy"
-	}
-	if install.Spec.ProxyService.Port == 0 {
-		install.Spec.ProxyService.Port = 8200
-	}
-	if install.Spec.ProxyService.TargetPort == 0 {
-		install.Spec.ProxyService.TargetPort = install.Spec.ProxyService.Port
-	}
-	if len(install.Spec.ProxyService.Selector) == 0 {
-		install.Spec.ProxyService.Selector = map[string]string{
-			"llm-d.ai/role":             "decode",
-			"llm-d.ai/inferenceServing": "true",
+	if install.Spec.InferencePool != nil && install.Spec.InferencePool.Enabled {
+		switch {
+		case install.Spec.InferencePool.Name == "":
+			conditions.failed(conditionInferencePoolReady, fmt.Errorf("inferencePool requires name (or epp.poolName)"))
+		case install.Spec.InferencePool.EndpointPickerRef.Name == "":
+			conditions.failed(conditionInferencePoolReady, fmt.Errorf("inferencePool requires endpointPickerRef.name (or epp configured)"))
+		case !r.gvkSupported(inferencePoolGVK):
+			conditions.skipped(conditionInferencePoolReady, inferencePoolGVK)
+		default:
+			if err := r.reconcileInferencePool(ctx, install); err != nil {
+				conditions.failed(conditionInferencePoolReady, err)
+			} else {
+				conditions.ready(conditionInferencePoolReady, "InferencePool is reconciled")
+			}
 		}
+	} else {
+		conditions.disabled(conditionInferencePoolReady)
 	}
 
-	if install.Spec.EPP != nil {
-		if install.Spec.EPP.Name == "" {
-			install.Spec.EPP.Name = "gaie-inference-scheduling-epp"
-		}
-		if install.Spec.EPP.Replicas == 0 {
-			install.Spec.EPP.Replicas = 1
-		}
-		if install.Spec.EPP.Image == "" {
-			install.Spec.EPP.Image = "ghcr.io/llm-d/llm-d-inference-scheduler:v0.4.0"
-		}
-		if install.Spec.EPP.Port == 0 {
-			install.Spec.EPP.Port = 9002
-		}
-		if install.Spec.EPP.PoolName == "" {
-			install.Spec.EPP.PoolName = "gaie-inference-scheduling"
-		}
-		if install.Spec.EPP.PoolNamespace == "" {
-			install.Spec.EPP.PoolNamespace = install.Spec.SimulatorNamespace
+	// Canary status is recomputed with the HTTPRoute and dropped when the canary is off
+	previousCanary := install.Status.Canary
+	install.Status.Canary = nil
+	var canaryRequeue time.Duration
+	if install.Spec.Routing != nil && install.Spec.Routing.Enabled {
+		if !r.gvkSupported(referenceGrantGVK) {
+			conditions.skipped(conditionReferenceGrantReady, referenceGrantGVK)
+		} else if err := r.reconcileReferenceGrant(ctx, install); err != nil {
+			conditions.failed(conditionReferenceGrantReady, err)
+		} else {
+			conditions.ready(conditionReferenceGrantReady, "ReferenceGrant is reconciled")
 		}
-		if install.Spec.EPP.ConfigProfile == "" {
-			install.Spec.EPP.ConfigProfile = "default"
+
+		ruleErrs := simv1alpha1.ValidateHTTPRouteRules(install.Spec.Routing.Rules, field.NewPath("spec", "routing", "rules"))
+		if canaryActive(install) {
+			ruleErrs = append(ruleErrs, simv1alpha1.ValidateCanaryConfig(install.Spec.Canary, field.NewPath("spec", "canary"))...)
+		}
+		switch {
+		case len(install.Spec.Routing.Rules) == 0 && install.Spec.Routing.BackendType == "InferencePool" &&
+			(install.Spec.Routing.InferencePool == nil || install.Spec.Routing.InferencePool.Name == ""):
+			conditions.failed(conditionRouteAccepted, fmt.Errorf("routing.backendType=InferencePool requires routing.inferencePool.name"))
+		case len(ruleErrs) > 0:
+			conditions.failed(conditionRouteAccepted, ruleErrs.ToAggregate())
+		case !r.gvkSupported(httpRouteGVK):
+			conditions.skipped(conditionRouteAccepted, httpRouteGVK)
+		default:
+			if canaryActive(install) {
+				install.Status.Canary, canaryRequeue = nextCanaryStatus(install.Spec.Canary, previousCanary, r.now(), r.newCanarySampler(ctx, install.Spec.Canary))
+				switch {
+				case install.Status.Canary.Phase == simv1alpha1.CanaryRolledBack && (previousCanary == nil || previousCanary.Phase != simv1alpha1.CanaryRolledBack):
+					logger.Info("canary rolled back", "reason", install.Status.Canary.Message)
+				case previousCanary == nil || previousCanary.CandidateWeight != install.Status.Canary.CandidateWeight:
+					logger.Info("canary step applied", "step", install.Status.Canary.Step, "candidateWeight", install.Status.Canary.CandidateWeight)
+				}
+			}
+			if err := r.reconcileHTTPRoute(ctx, install); err != nil {
+				// The weights were not applied; retry the same step
+				install.Status.Canary = previousCanary
+				conditions.failed(conditionRouteAccepted, err)
+			} else if err := r.routeCondition(ctx, install, conditions); err != nil {
+				conditions.failed(conditionRouteAccepted, err)
+			}
 		}
+	} else {
+		conditions.disabled(conditionReferenceGrantReady)
+		conditions.disabled(conditionRouteAccepted)
 	}
 
-	if install.Spec.Gateway != nil {
-		if install.Spec.Gateway.Name == "" {
-			install.Spec.Gateway.Name = "infra-inference-scheduling-inference-gateway"
-		}
-		if install.Spec.Gateway.ClassName == "" {
-			install.Spec.Gateway.ClassName = "istio"
-		}
-		if install.Spec.Gateway.ListenerPort == 0 {
-			install.Spec.Gateway.ListenerPort = 80
-		}
-		if install.Spec.Gateway.ListenerProtocol == "" {
-			install.Spec.Gateway.ListenerProtocol = "HTTP"
-		}
+	if install.Spec.DestinationRule != nil && install.Spec.DestinationRule.Enabled {
+		if !r.gvkSupported(destinationRuleGVK) {
+			conditions.skipped(conditionDestinationRuleReady, destinationRuleGVK)
+		} else if err := r.reconcileDestinationRule(ctx, install); err != nil {
+			conditions.failed(conditionDestinationRuleReady, err)
+		} else {
+			conditions.ready(conditionDestinationRuleReady, "DestinationRule is reconciled")
+		}
+	} else {
+		conditions.disabled(conditionDestinationRuleReady)
 	}
 
-	if install.Spec.Routing != nil {
-		if install.Spec.Routing.BackendType == "" {
-			install.Spec.Routing.BackendType = "Service"
-		}
-		if install.Spec.Routing.HTTPRouteName == "" {
-			install.Spec.Routing.HTTPRouteName = "llm-d-inference-scheduling"
-		}
-		if install.Spec.Routing.ParentGateway.Name == "" && install.Spec.Gateway != nil {
-			install.Spec.Routing.ParentGateway.Name = install.Spec.Gateway.Name
-		}
-		if install.Spec.Routing.ParentGateway.Namespace == "" {
-			install.Spec.Routing.ParentGateway.Namespace = install.Spec.SchedulerNamespace
-		}
-		if install.Spec.Routing.BackendType == "InferencePool" {
-			if install.Spec.Routing.InferencePool == nil {
-				install.Spec.Routing.InferencePool = &simv1alpha1.InferencePoolRef{}
-			}
-			if install.Spec.Routing.InferencePool.Name == "" && install.Spec.EPP != nil {
-				install.Spec.Routing.InferencePool.Name = install.Spec.EPP.PoolName
+	if install.Spec.EnvoyFilter != nil && install.Spec.EnvoyFilter.Enabled {
+		configErrs := simv1alpha1.ValidateEnvoyFilterConfig(install.Spec.EnvoyFilter, field.NewPath("spec", "envoyFilter"))
+		switch {
+		case install.Spec.EPP == nil || !install.Spec.EPP.Enabled:
+			conditions.failed(conditionEnvoyFilterApplied, fmt.Errorf("envoyFilter requires epp.enabled=true"))
+		case len(install.Spec.EnvoyFilter.WorkloadSelector) == 0:
+			conditions.failed(conditionEnvoyFilterApplied, fmt.Errorf("envoyFilter requires workloadSelector (or gateway configured for default selector)"))
+		case len(configErrs) > 0:
+			conditions.failed(conditionEnvoyFilterApplied, configErrs.ToAggregate())
+		case !r.gvkSupported(envoyFilterGVK):
+			conditions.skipped(conditionEnvoyFilterApplied, envoyFilterGVK)
+		default:
+			if address, err := r.reconcileEnvoyFilter(ctx, install); err != nil {
+				conditions.failed(conditionEnvoyFilterApplied, err)
+			} else {
+				conditions.ready(conditionEnvoyFilterApplied, fmt.Sprintf("EnvoyFilter is applied, epp-cluster points at %s", address))
 			}
-			if install.Spec.Routing.InferencePool.Namespace == "" {
-				if install.Spec.EPP != nil && install.Spec.EPP.PoolNamespace != "" {
-					install.Spec.Routing.InferencePool.Namespace = install.Spec.EPP.PoolNamespace
-				} else {
-					install.Spec.Routing.InferencePool.Namespace = install.Spec.SimulatorNamespace
-				}
+		}
+	} else {
+		if install.Spec.EnvoyFilter != nil {
+			if err := r.deleteEnvoyFilter(ctx, install); err != nil {
+				conditions.errs = append(conditions.errs, err)
 			}
 		}
+		conditions.disabled(conditionEnvoyFilterApplied)
 	}
 
-	if install.Spec.DestinationRule != nil {
-		if install.Spec.DestinationRule.Algorithm == "" {
-			install.Spec.DestinationRule.Algorithm = "ROUND_ROBIN"
-		}
-		if install.Spec.DestinationRule.ConnectionPool == nil {
-			install.Spec.DestinationRule.ConnectionPool = &simv1alpha1.ConnectionPoolConfig{
-				HTTP1MaxPendingRequests:  1,
-				MaxRequestsPerConnection: 1,
-			}
-		}
+	if err := r.cleanupCrossNamespaceResources(ctx, install, r.desiredCrossNamespaceResources(install)); err != nil {
+		conditions.errs = append(conditions.errs, err)
 	}
 
-	if install.Spec.EnvoyFilter != nil {
-		if install.Spec.EnvoyFilter.Name == "" {
-			install.Spec.EnvoyFilter.Name = "epp-ext-proc"
-		}
-		if len(install.Spec.EnvoyFilter.WorkloadSelector) == 0 && install.Spec.Gateway != nil {
-			install.Spec.EnvoyFilter.WorkloadSelector = map[string]string{
-				"gateway.networking.k8s.io/gateway-name": install.Spec.Gateway.Name,
-			}
-		}
+	if err := r.updateStatus(ctx, install, conditions); err != nil {
+		logger.Error(err, "failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	if len(conditions.errs) > 0 {
+		return ctrl.Result{}, utilerrors.NewAggregate(conditions.errs)
+	}
+	result := ctrl.Result{RequeueAfter: canaryRequeue}
+	if !conditions.allReady() && (result.RequeueAfter == 0 || result.RequeueAfter > 15*time.Second) {
+		// Gateway and HTTPRoute status is written by other controllers; poll until it settles
+		result.RequeueAfter = 15 * time.Second
+	}
+	return result, nil
+}
+
+func (r *SchedulerInstallReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
 	}
+	return r.Clock.Now()
 }
 
 func (r *SchedulerInstallReconciler) reconcileSchedulerEPP(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
@@ -242,10 +339,11 @@ func (r *SchedulerInstallReconciler) reconcileSchedulerEPP(ctx context.Context,
 	if err := r.reconcileEPPRBAC(ctx, install); err != nil {
 		return err
 	}
-	if err := r.reconcileEPPConfigMap(ctx, install); err != nil {
+	pluginsConfig, err := r.reconcileEPPConfigMap(ctx, install)
+	if err != nil {
 		return err
 	}
-	if err := r.reconcileEPPDeployment(ctx, install); err != nil {
+	if err := r.reconcileEPPDeployment(ctx, install, pluginsConfig); err != nil {
 		return err
 	}
 	return r.reconcileEPPService(ctx, install)
@@ -322,71 +420,12 @@ func (r *SchedulerInstallReconciler) reconcileEPPRBAC(ctx context.Context, insta
 	return err
 }
 
-func (r *SchedulerInstallReconciler) reconcileEPPConfigMap(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
+// reconcileEPPConfigMap renders the plugin config into the EPP ConfigMap and returns it
+func (r *SchedulerInstallReconciler) reconcileEPPConfigMap(ctx context.Context, install *simv1alpha1.SchedulerInstall) (string, error) {
 	configName := fmt.Sprintf("%s-config", install.Spec.EPP.Name)
-	pluginsConfig := `apiVersion: inference.networking.x-k8s.io/v1alpha1
-kind: EndpointPickerConfig
-plugins:
-- type: load-aware-scorer
-- type: prefix-cache-scorer
-  parameters:
-    hashBlockSize: 5
-    maxPrefixBlocksToMatch: 256
-    lruCapacityPerServer: 31250
-- type: kv-cache-utilization-scorer
-- type: decode-filter
-- type: max-score-picker
-- type: single-profile-handler
-schedulingProfiles:
-- name: default
-  plugins:
-  - pluginRef: decode-filter
-  - pluginRef: max-score-picker
-  - pluginRef: load-aware-scorer
-    weight: 1
-  - pluginRef: prefix-cache-scorer
-    weight: 2
-  - pluginRef: kv-cache-utilization-scorer
-    weight: 1
-`
-	if install.Spec.EPP.ConfigProfile == "proxy-performance" {
-		pluginsConfig = `apiVersion: inference.networking.x-k8s.io/v1alpha1
-kind: EndpointPickerConfig
-plugins:
-- type: active-request-scorer
-  parameters:
-    requestTimeout: "2m"
-- type: max-score-picker
-- type: single-profile-handler
-schedulingProfiles:
-- name: default
-  plugins:
-  - pluginRef: max-score-picker
-  - pluginRef: active-request-scorer
-    weight: 1
-`
-	} else if install.Spec.EPP.ConfigProfile == "proxy-performance-by-backend" {
-		pluginsConfig = `apiVersion: inference.networking.x-k8s.io/v1alpha1
-kind: EndpointPickerConfig
-plugins:
-- type: active-request-scorer
-  parameters:
-    requestTimeout: "2m"
-- type: by-label-selector
-  name: by-kv-backend
-  parameters:
-    matchLabels:
-      llm-d.ai/kv-backend: nvlink
-- type: max-score-picker
-- type: single-profile-handler
-schedulingProfiles:
-- name: default
-  plugins:
-  - pluginRef: by-kv-backend
-  - pluginRef: max-score-picker
-  - pluginRef: active-request-scorer
-    weight: 1
-`
+	pluginsConfig, err := simv1alpha1.RenderEndpointPickerConfig(install.Spec.EPP.ConfigProfile, install.Spec.EPP.Plugins, install.Spec.EPP.SchedulingProfiles)
+	if err != nil {
+		return "", err
 	}
 
 	configMap := &corev1.ConfigMap{
@@ -396,9 +435,9 @@ schedulingProfiles:
 		},
 	}
 	if err := controllerutil.SetControllerReference(install, configMap, r.Scheme); err != nil {
-		return err
+		return "", err
 	}
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
 		if configMap.Labels == nil {
 			configMap.Labels = map[string]string{}
 		}
@@ -408,10 +447,10 @@ schedulingProfiles:
 		}
 		return nil
 	})
-	return err
+	return pluginsConfig, err
 }
 
-func (r *SchedulerInstallReconciler) reconcileEPPDeployment(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
+func (r *SchedulerInstallReconciler) reconcileEPPDeployment(ctx context.Context, install *simv1alpha1.SchedulerInstall, pluginsConfig string) error {
 	epp := install.Spec.EPP
 	configName := fmt.Sprintf("%s-config", epp.Name)
 	deployment := &appsv1.Deployment{
@@ -432,11 +471,8 @@ func (r *SchedulerInstallReconciler) reconcileEPPDeployment(ctx context.Context,
 		deployment.Spec.Replicas = &epp.Replicas
 		deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
 		deployment.Spec.Template.ObjectMeta.Labels = labels
+		setEPPRolloutAnnotations(&deployment.Spec.Template, install, epp.RestartPolicy, pluginsConfig)
 		deployment.Spec.Template.Spec.ServiceAccountName = epp.Name
-		verbosity := epp.Verbosity
-		if verbosity == 0 {
-			verbosity = 1
-		}
 		grpcPort := epp.Port
 		if grpcPort == 0 {
 			grpcPort = 9002
@@ -452,7 +488,7 @@ func (r *SchedulerInstallReconciler) reconcileEPPDeployment(ctx context.Context,
 			"--secure-serving=false",
 			"--grpc-port", strconv.Itoa(int(grpcPort)),
 			"--grpc-health-port", strconv.Itoa(int(healthPort)),
-			"--v", strconv.Itoa(int(verbosity)),
+			"--v", strconv.Itoa(int(epp.Verbosity)),
 			"--tracing=false",
 		}
 		if len(epp.Args) > 0 {
@@ -465,6 +501,7 @@ func (r *SchedulerInstallReconciler) reconcileEPPDeployment(ctx context.Context,
 				Image:           epp.Image,
 				ImagePullPolicy: corev1.PullIfNotPresent,
 				Args:            args,
+				Env:             epp.Env,
 				Ports: []corev1.ContainerPort{
 					{Name: "grpc", ContainerPort: grpcPort, Protocol: corev1.ProtocolTCP},
 					{Name: "grpc-health", ContainerPort: healthPort, Protocol: corev1.ProtocolTCP},
@@ -564,8 +601,92 @@ func (r *SchedulerInstallReconciler) reconcileProxyService(ctx context.Context,
 	return err
 }
 
+func (r *SchedulerInstallReconciler) reconcileInferencePool(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
+	gvk := inferencePoolGVK
+	if !r.gvkSupported(gvk) {
+		return nil
+	}
+
+	pool := install.Spec.InferencePool
+	ref := pool.EndpointPickerRef
+
+	// endpointPickerRef cannot cross namespaces, so alias the EPP Service into the pool namespace
+	if install.Spec.EPP != nil && install.Spec.EPP.Enabled && ref.Name == install.Spec.EPP.Name &&
+		pool.Namespace != install.Spec.SchedulerNamespace {
+		if err := r.reconcileEPPAliasService(ctx, install); err != nil {
+			return err
+		}
+	}
+
+	inferencePool := &unstructured.Unstructured{}
+	inferencePool.SetGroupVersionKind(gvk)
+	inferencePool.SetName(pool.Name)
+	inferencePool.SetNamespace(pool.Namespace)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, inferencePool, func() error {
+		if err := r.ownObject(install, inferencePool); err != nil {
+			return err
+		}
+
+		matchLabels := map[string]interface{}{}
+		for k, v := range pool.Selector {
+			matchLabels[k] = v
+		}
+		targetPorts := []interface{}{}
+		for _, port := range pool.TargetPorts {
+			targetPorts = append(targetPorts, map[string]interface{}{
+				"number": int64(port),
+			})
+		}
+		spec := map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": matchLabels,
+			},
+			"targetPorts": targetPorts,
+			"endpointPickerRef": map[string]interface{}{
+				"group": "",
+				"kind":  "Service",
+				"name":  ref.Name,
+				"port": map[string]interface{}{
+					"number": int64(ref.Port),
+				},
+				"failureMode": ref.FailureMode,
+			},
+		}
+		return unstructured.SetNestedField(inferencePool.Object, spec, "spec")
+	})
+	return err
+}
+
+func (r *SchedulerInstallReconciler) reconcileEPPAliasService(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
+	pool := install.Spec.InferencePool
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pool.EndpointPickerRef.Name,
+			Namespace: pool.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		if err := r.ownObject(install, service); err != nil {
+			return err
+		}
+		service.Spec.Type = corev1.ServiceTypeExternalName
+		service.Spec.ExternalName = fmt.Sprintf("%s.%s.svc.cluster.local", install.Spec.EPP.Name, install.Spec.SchedulerNamespace)
+		service.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       "grpc",
+				Port:       pool.EndpointPickerRef.Port,
+				TargetPort: intstr.FromInt(int(pool.EndpointPickerRef.Port)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		}
+		return nil
+	})
+	return err
+}
+
 func (r *SchedulerInstallReconciler) reconcileGateway(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
-	gvk := schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+	gvk := gatewayGVK
 	if !r.gvkSupported(gvk) {
 		return nil
 	}
@@ -595,7 +716,7 @@ func (r *SchedulerInstallReconciler) reconcileGateway(ctx context.Context, insta
 }
 
 func (r *SchedulerInstallReconciler) reconcileHTTPRoute(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
-	gvk := schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	gvk := httpRouteGVK
 	if !r.gvkSupported(gvk) {
 		return nil
 	}
@@ -609,27 +730,6 @@ func (r *SchedulerInstallReconciler) reconcileHTTPRoute(ctx context.Context, ins
 	}
 
 	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
-		backendRef := map[string]interface{}{
-			"group":     "",
-			"kind":      "Service",
-			"name":      install.Spec.ProxyService.Name,
-			"namespace": install.Spec.SimulatorNamespace,
-			"port":      int64(install.Spec.ProxyService.Port),
-			"weight":    int64(1),
-		}
-		if install.Spec.Routing.BackendType == "InferencePool" && install.Spec.Routing.InferencePool != nil {
-			backendRef = map[string]interface{}{
-				"group":     "inference.networking.k8s.io",
-				"kind":      "InferencePool",
-				"name":      install.Spec.Routing.InferencePool.Name,
-				"namespace": install.Spec.Routing.InferencePool.Namespace,
-				"weight":    int64(1),
-			}
-			if install.Spec.Routing.InferencePool.Port != 0 {
-				backendRef["port"] = int64(install.Spec.Routing.InferencePool.Port)
-			}
-		}
-
 		spec := map[string]interface{}{
 			"parentRefs": []interface{}{
 				map[string]interface{}{
@@ -639,41 +739,173 @@ func (r *SchedulerInstallReconciler) reconcileHTTPRoute(ctx context.Context, ins
 					"namespace": install.Spec.Routing.ParentGateway.Namespace,
 				},
 			},
-			"rules": []interface{}{
-				map[string]interface{}{
-					"matches": []interface{}{
-						map[string]interface{}{
-							"path": map[string]interface{}{
-								"type":  "PathPrefix",
-								"value": "/",
-							},
-						},
-					},
-					"backendRefs": []interface{}{
-						backendRef,
-					},
-					"timeouts": map[string]interface{}{
-						"backendRequest": "0s",
-						"request":        "0s",
-					},
-				},
-			},
+			"rules": httpRouteRules(install),
 		}
 		return unstructured.SetNestedField(route.Object, spec, "spec")
 	})
 	return err
 }
 
+// httpRouteRules renders routing.rules, or a single PathPrefix / rule to the
+// backend selected by backendType when no rules are configured
+func httpRouteRules(install *simv1alpha1.SchedulerInstall) []interface{} {
+	routing := install.Spec.Routing
+	rules := routing.Rules
+	if len(rules) == 0 {
+		backend := simv1alpha1.HTTPBackendRef{
+			Kind:      "Service",
+			Name:      install.Spec.ProxyService.Name,
+			Namespace: install.Spec.SimulatorNamespace,
+			Port:      install.Spec.ProxyService.Port,
+		}
+		if routing.BackendType == "InferencePool" && routing.InferencePool != nil {
+			backend = simv1alpha1.HTTPBackendRef{
+				Kind:      "InferencePool",
+				Name:      routing.InferencePool.Name,
+				Namespace: routing.InferencePool.Namespace,
+				Port:      routing.InferencePool.Port,
+			}
+		}
+		backends := []simv1alpha1.HTTPBackendRef{backend}
+		if canaryActive(install) {
+			// Reconcile sets status.canary to the step being applied
+			backends = canaryBackendRefs(install.Spec.Canary, install.Status.Canary)
+		}
+		rules = []simv1alpha1.HTTPRouteRule{{
+			Matches:     []simv1alpha1.HTTPRouteMatch{{Path: &simv1alpha1.HTTPPathMatch{Type: "PathPrefix", Value: "/"}}},
+			BackendRefs: backends,
+		}}
+	}
+
+	rendered := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		matches := make([]interface{}, 0, len(rule.Matches))
+		for _, match := range rule.Matches {
+			m := map[string]interface{}{}
+			if match.Path != nil {
+				m["path"] = map[string]interface{}{
+					"type":  match.Path.Type,
+					"value": match.Path.Value,
+				}
+			}
+			if len(match.Headers) > 0 {
+				m["headers"] = httpValueMatches(match.Headers)
+			}
+			if len(match.QueryParams) > 0 {
+				m["queryParams"] = httpValueMatches(match.QueryParams)
+			}
+			matches = append(matches, m)
+		}
+
+		backendRefs := make([]interface{}, 0, len(rule.BackendRefs))
+		for _, backend := range rule.BackendRefs {
+			ref := map[string]interface{}{
+				"group":     "",
+				"kind":      "Service",
+				"name":      backend.Name,
+				"namespace": backend.Namespace,
+				"weight":    int64(1),
+			}
+			if backend.Kind == "InferencePool" {
+				ref["group"] = "inference.networking.k8s.io"
+				ref["kind"] = "InferencePool"
+			}
+			if backend.Port != 0 {
+				ref["port"] = int64(backend.Port)
+			}
+			if backend.Weight != nil {
+				ref["weight"] = int64(*backend.Weight)
+			}
+			backendRefs = append(backendRefs, ref)
+		}
+
+		out := map[string]interface{}{
+			"backendRefs": backendRefs,
+			"timeouts": map[string]interface{}{
+				"backendRequest": "0s",
+				"request":        "0s",
+			},
+		}
+		if len(matches) > 0 {
+			out["matches"] = matches
+		}
+		rendered = append(rendered, out)
+	}
+	return rendered
+}
+
+func httpValueMatches(values []simv1alpha1.HTTPValueMatch) []interface{} {
+	matches := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		matchType := value.Type
+		if matchType == "" {
+			matchType = "Exact"
+		}
+		matches = append(matches, map[string]interface{}{
+			"type":  matchType,
+			"name":  value.Name,
+			"value": value.Value,
+		})
+	}
+	return matches
+}
+
+// referenceGrantNamespaces returns the namespaces the HTTPRoute references
+// backends in, which each need a ReferenceGrant. The simulator namespace is
+// always included for the proxy Service.
+func referenceGrantNamespaces(install *simv1alpha1.SchedulerInstall) []string {
+	namespaces := []string{install.Spec.SimulatorNamespace}
+	seen := map[string]bool{install.Spec.SimulatorNamespace: true}
+	add := func(namespace string) {
+		if namespace == "" || namespace == install.Spec.SchedulerNamespace || seen[namespace] {
+			return
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	if routing := install.Spec.Routing; routing != nil {
+		if routing.InferencePool != nil {
+			add(routing.InferencePool.Namespace)
+		}
+		for _, rule := range routing.Rules {
+			for _, backend := range rule.BackendRefs {
+				add(backend.Namespace)
+			}
+		}
+	}
+	if canaryActive(install) {
+		add(install.Spec.Canary.Stable.Namespace)
+		add(install.Spec.Canary.Candidate.Namespace)
+	}
+	return namespaces
+}
+
 func (r *SchedulerInstallReconciler) reconcileReferenceGrant(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
-	gvk := schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "ReferenceGrant"}
+	gvk := referenceGrantGVK
 	if !r.gvkSupported(gvk) {
 		return nil
 	}
 
+	for _, namespace := range referenceGrantNamespaces(install) {
+		if err := r.reconcileReferenceGrantIn(ctx, install, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// referenceGrantName names the ReferenceGrant of an install. Every install
+// routing into a namespace writes a grant there, so the name is per install:
+// deleting one install must not revoke the grant another HTTPRoute relies on.
+func referenceGrantName(install *simv1alpha1.SchedulerInstall) string {
+	return fmt.Sprintf("%s-%s-httproute", install.Namespace, install.Name)
+}
+
+func (r *SchedulerInstallReconciler) reconcileReferenceGrantIn(ctx context.Context, install *simv1alpha1.SchedulerInstall, namespace string) error {
 	grant := &unstructured.Unstructured{}
-	grant.SetGroupVersionKind(gvk)
-	grant.SetName("allow-scheduler-httproute")
-	grant.SetNamespace(install.Spec.SimulatorNamespace)
+	grant.SetGroupVersionKind(referenceGrantGVK)
+	grant.SetName(referenceGrantName(install))
+	grant.SetNamespace(namespace)
 
 	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, grant, func() error {
 		if grant.GetLabels() == nil {
@@ -709,7 +941,7 @@ func (r *SchedulerInstallReconciler) reconcileReferenceGrant(ctx context.Context
 }
 
 func (r *SchedulerInstallReconciler) reconcileDestinationRule(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
-	gvk := schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "DestinationRule"}
+	gvk := destinationRuleGVK
 	if !r.gvkSupported(gvk) {
 		return nil
 	}
@@ -751,10 +983,11 @@ func (r *SchedulerInstallReconciler) reconcileDestinationRule(ctx context.Contex
 	return err
 }
 
-func (r *SchedulerInstallReconciler) reconcileEnvoyFilter(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
-	gvk := schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "EnvoyFilter"}
+// reconcileEnvoyFilter applies the ext_proc EnvoyFilter and returns the address epp-cluster points at
+func (r *SchedulerInstallReconciler) reconcileEnvoyFilter(ctx context.Context, install *simv1alpha1.SchedulerInstall) (string, error) {
+	gvk := envoyFilterGVK
 	if !r.gvkSupported(gvk) {
-		return nil
+		return "", nil
 	}
 
 	ef := &unstructured.Unstructured{}
@@ -762,18 +995,12 @@ func (r *SchedulerInstallReconciler) reconcileEnvoyFilter(ctx context.Context, i
 	ef.SetName(install.Spec.EnvoyFilter.Name)
 	ef.SetNamespace(install.Spec.SchedulerNamespace)
 
-	// Get EPP Service to use ClusterIP (fix for DNS issues)
-	eppSvc := &corev1.Service{}
-	if err := r.Get(ctx, types.NamespacedName{Name: install.Spec.EPP.Name, Namespace: install.Spec.SchedulerNamespace}, eppSvc); err != nil {
-		return err
-	}
-	eppIP := eppSvc.Spec.ClusterIP
-	if eppIP == "" {
-		return fmt.Errorf("EPP service has no ClusterIP")
+	eppCluster, eppAddress, err := r.eppCluster(ctx, install)
+	if err != nil {
+		return "", err
 	}
-	eppPort := install.Spec.EPP.Port
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, ef, func() error {
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, ef, func() error {
 		if ef.GetLabels() == nil {
 			ef.SetLabels(map[string]string{})
 		}
@@ -798,6 +1025,19 @@ func (r *SchedulerInstallReconciler) reconcileEnvoyFilter(ctx context.Context, i
 				"labels": labels,
 			}
 		}
+		config := install.Spec.EnvoyFilter
+		externalProcessor := map[string]interface{}{
+			"@type":              "type.googleapis.com/envoy.extensions.filters.http.ext_proc.v3.ExternalProcessor",
+			"grpc_service":       extProcGRPCService("epp-cluster", config.Timeout),
+			"processing_mode":    extProcProcessingMode(config.ProcessingMode),
+			"failure_mode_allow": config.FailureModeAllow == nil || *config.FailureModeAllow,
+		}
+		if config.MessageTimeout != nil {
+			externalProcessor["message_timeout"] = protoDuration(config.MessageTimeout.Duration)
+		}
+		if config.AllowModeOverride {
+			externalProcessor["allow_mode_override"] = true
+		}
 		spec["configPatches"] = []interface{}{
 			// Fix 1 & 4: Replace ext_proc filter, set global mode to SKIP
 			map[string]interface{}{
@@ -818,27 +1058,12 @@ func (r *SchedulerInstallReconciler) reconcileEnvoyFilter(ctx context.Context, i
 				"patch": map[string]interface{}{
 					"operation": "REPLACE",
 					"value": map[string]interface{}{
-						"name": "envoy.filters.http.ext_proc",
-						"typed_config": map[string]interface{}{
-							"@type": "type.googleapis.com/envoy.extensions.filters.http.ext_proc.v3.ExternalProcessor",
-							"grpc_service": map[string]interface{}{
-								"envoy_grpc": map[string]interface{}{
-									"cluster_name": "epp-cluster",
-								},
-								"timeout": "2s",
-							},
-							"processing_mode": map[string]interface{}{
-								"request_header_mode":  "SKIP",
-								"request_body_mode":    "BUFFERED",
-								"response_header_mode": "SKIP",
-								"response_body_mode":   "NONE",
-							},
-							"failure_mode_allow": true,
-						},
+						"name":         "envoy.filters.http.ext_proc",
+						"typed_config": externalProcessor,
 					},
 				},
 			},
-			// Fix 3: Use STATIC cluster with IP
+			// Fix 3: Add the cluster ext_proc talks to, see eppCluster
 			map[string]interface{}{
 				"applyTo": "CLUSTER",
 				"match": map[string]interface{}{
@@ -846,32 +1071,7 @@ func (r *SchedulerInstallReconciler) reconcileEnvoyFilter(ctx context.Context, i
 				},
 				"patch": map[string]interface{}{
 					"operation": "ADD",
-					"value": map[string]interface{}{
-						"name":                   "epp-cluster",
-						"type":                   "STATIC",
-						"connect_timeout":        "2s",
-						"lb_policy":              "ROUND_ROBIN",
-						"http2_protocol_options": map[string]interface{}{},
-						"load_assignment": map[string]interface{}{
-							"cluster_name": "epp-cluster",
-							"endpoints": []interface{}{
-								map[string]interface{}{
-									"lb_endpoints": []interface{}{
-										map[string]interface{}{
-											"endpoint": map[string]interface{}{
-												"address": map[string]interface{}{
-													"socket_address": map[string]interface{}{
-														"address":    eppIP,
-														"port_value": int64(eppPort),
-													},
-												},
-											},
-										},
-									},
-								},
-							},
-						},
-					},
+					"value":     eppCluster,
 				},
 			},
 			// Fix 2 & 4: Route override to SEND, body mode NONE
@@ -887,18 +1087,9 @@ func (r *SchedulerInstallReconciler) reconcileEnvoyFilter(ctx context.Context, i
 							"envoy.filters.http.ext_proc": map[string]interface{}{
 								"@type": "type.googleapis.com/envoy.extensions.filters.http.ext_proc.v3.ExtProcPerRoute",
 								"overrides": map[string]interface{}{
-									"grpc_service": map[string]interface{}{
-										"envoy_grpc": map[string]interface{}{
-											"cluster_name": "epp-cluster",
-										},
-									},
-									"processing_mode": map[string]interface{}{
-										"request_header_mode":  "SKIP",
-										"request_body_mode":    "BUFFERED",
-										"response_header_mode": "SKIP",
-										"response_body_mode":   "NONE",
-									},
-									"failure_mode_allow": true,
+									"grpc_service":       extProcGRPCService("epp-cluster", config.Timeout),
+									"processing_mode":    extProcProcessingMode(config.ProcessingMode),
+									"failure_mode_allow": config.FailureModeAllow == nil || *config.FailureModeAllow,
 								},
 							},
 						},
@@ -908,11 +1099,111 @@ func (r *SchedulerInstallReconciler) reconcileEnvoyFilter(ctx context.Context, i
 		}
 		return unstructured.SetNestedField(ef.Object, spec, "spec")
 	})
-	return err
+	return eppAddress, err
+}
+
+// extProcGRPCService points ext_proc at the cluster of the EPP
+func extProcGRPCService(clusterName string, timeout *metav1.Duration) map[string]interface{} {
+	rendered := "2s"
+	if timeout != nil {
+		rendered = protoDuration(timeout.Duration)
+	}
+	return map[string]interface{}{
+		"envoy_grpc": map[string]interface{}{
+			"cluster_name": clusterName,
+		},
+		"timeout": rendered,
+	}
+}
+
+// protoDuration renders d as google.protobuf.Duration JSON, seconds with an
+// "s" suffix; Envoy rejects Go durations such as 500ms or 1m0s
+func protoDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// extProcProcessingMode renders the configured processing mode, see
+// ValidateEnvoyFilterConfig for the combinations the EPP can serve
+func extProcProcessingMode(configured *simv1alpha1.ExtProcProcessingMode) map[string]interface{} {
+	mode := simv1alpha1.ExtProcProcessingMode{
+		RequestHeaderMode:  "SKIP",
+		RequestBodyMode:    "BUFFERED",
+		ResponseHeaderMode: "SKIP",
+		ResponseBodyMode:   "NONE",
+	}
+	if configured != nil {
+		mode = *configured
+	}
+	rendered := map[string]interface{}{
+		"request_header_mode":  mode.RequestHeaderMode,
+		"request_body_mode":    mode.RequestBodyMode,
+		"response_header_mode": mode.ResponseHeaderMode,
+		"response_body_mode":   mode.ResponseBodyMode,
+	}
+	// Envoy rejects FULL_DUPLEX_STREAMED bodies unless trailers are sent too
+	if mode.RequestBodyMode == "FULL_DUPLEX_STREAMED" {
+		rendered["request_trailer_mode"] = "SEND"
+	}
+	if mode.ResponseBodyMode == "FULL_DUPLEX_STREAMED" {
+		rendered["response_trailer_mode"] = "SEND"
+	}
+	return rendered
+}
+
+// eppCluster returns the epp-cluster definition and the address it points at.
+// STATIC clusters use the EPP Service ClusterIP to work around DNS issues in
+// the gateway. The Service is owned by the SchedulerInstall, so recreating it
+// with a new ClusterIP triggers a reconcile that re-renders the cluster.
+func (r *SchedulerInstallReconciler) eppCluster(ctx context.Context, install *simv1alpha1.SchedulerInstall) (map[string]interface{}, string, error) {
+	clusterType := install.Spec.EnvoyFilter.ClusterType
+	host := fmt.Sprintf("%s.%s.svc.cluster.local", install.Spec.EPP.Name, install.Spec.SchedulerNamespace)
+	if clusterType != simv1alpha1.EnvoyFilterClusterStrictDNS {
+		clusterType = simv1alpha1.EnvoyFilterClusterStatic
+		eppSvc := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{Name: install.Spec.EPP.Name, Namespace: install.Spec.SchedulerNamespace}, eppSvc); err != nil {
+			return nil, "", err
+		}
+		if eppSvc.Spec.ClusterIP == "" || eppSvc.Spec.ClusterIP == corev1.ClusterIPNone {
+			return nil, "", fmt.Errorf("EPP service has no ClusterIP")
+		}
+		host = eppSvc.Spec.ClusterIP
+	}
+	eppPort := install.Spec.EPP.Port
+
+	cluster := map[string]interface{}{
+		"name":                   "epp-cluster",
+		"type":                   clusterType,
+		"connect_timeout":        "2s",
+		"lb_policy":              "ROUND_ROBIN",
+		"http2_protocol_options": map[string]interface{}{},
+		"load_assignment": map[string]interface{}{
+			"cluster_name": "epp-cluster",
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"lb_endpoints": []interface{}{
+						map[string]interface{}{
+							"endpoint": map[string]interface{}{
+								"address": map[string]interface{}{
+									"socket_address": map[string]interface{}{
+										"address":    host,
+										"port_value": int64(eppPort),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if clusterType == simv1alpha1.EnvoyFilterClusterStrictDNS {
+		cluster["dns_lookup_family"] = "V4_ONLY"
+	}
+	return cluster, fmt.Sprintf("%s:%d", host, eppPort), nil
 }
 
 func (r *SchedulerInstallReconciler) deleteEnvoyFilter(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
-	gvk := schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "EnvoyFilter"}
+	gvk := envoyFilterGVK
 	if !r.gvkSupported(gvk) {
 		return nil
 	}
@@ -931,36 +1222,315 @@ func (r *SchedulerInstallReconciler) deleteEnvoyFilter(ctx context.Context, inst
 	return nil
 }
 
-func (r *SchedulerInstallReconciler) updateStatus(ctx context.Context, install *simv1alpha1.SchedulerInstall, ready bool, reason, message string) error {
-	condition := metav1.Condition{
-		Type:               "Ready",
-		Status:             metav1.ConditionFalse,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: metav1.Now(),
+// finalize removes cross-namespace resources before the SchedulerInstall is deleted
+func (r *SchedulerInstallReconciler) finalize(ctx context.Context, install *simv1alpha1.SchedulerInstall) error {
+	if !controllerutil.ContainsFinalizer(install, schedulerInstallFinalizer) {
+		return nil
 	}
-	if ready {
-		condition.Status = metav1.ConditionTrue
+
+	// Resolve namespaces from defaults without persisting them to the spec
+	defaulted := install.DeepCopy()
+	defaulted.Default()
+	if err := r.cleanupCrossNamespaceResources(ctx, defaulted, nil); err != nil {
+		return err
+	}
+
+	controllerutil.RemoveFinalizer(install, schedulerInstallFinalizer)
+	return r.Update(ctx, install)
+}
+
+// desiredCrossNamespaceResources returns the cross-namespace objects the current spec still needs
+func (r *SchedulerInstallReconciler) desiredCrossNamespaceResources(install *simv1alpha1.SchedulerInstall) map[crossNamespaceKey]bool {
+	keep := map[crossNamespaceKey]bool{}
+	add := func(kind, namespace, name string) {
+		keep[crossNamespaceKey{Kind: kind, NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}] = true
 	}
 
+	add("Service", install.Spec.SimulatorNamespace, install.Spec.ProxyService.Name)
+	if install.Spec.EPP != nil && install.Spec.EPP.Enabled {
+		add("Role", install.Spec.SimulatorNamespace, install.Spec.EPP.Name)
+		add("RoleBinding", install.Spec.SimulatorNamespace, install.Spec.EPP.Name)
+	}
+	if install.Spec.Routing != nil && install.Spec.Routing.Enabled {
+		for _, namespace := range referenceGrantNamespaces(install) {
+			add("ReferenceGrant", namespace, referenceGrantName(install))
+		}
+	}
+	if install.Spec.DestinationRule != nil && install.Spec.DestinationRule.Enabled {
+		add("DestinationRule", install.Spec.SimulatorNamespace, fmt.Sprintf("%s-lb", install.Spec.ProxyService.Name))
+	}
+	if install.Spec.InferencePool != nil && install.Spec.InferencePool.Enabled {
+		pool := install.Spec.InferencePool
+		add("InferencePool", pool.Namespace, pool.Name)
+		add("Service", pool.Namespace, pool.EndpointPickerRef.Name)
+	}
+	return keep
+}
+
+// cleanupCrossNamespaceResources deletes objects labelled for install that are not in keep.
+// A nil keep set removes everything, which is used when the SchedulerInstall is deleted.
+func (r *SchedulerInstallReconciler) cleanupCrossNamespaceResources(ctx context.Context, install *simv1alpha1.SchedulerInstall, keep map[crossNamespaceKey]bool) error {
+	logger := log.FromContext(ctx)
+
+	// List across all namespaces: the labels identify the install, and objects
+	// left in a namespace the spec no longer references must be found too
+	for _, gvk := range crossNamespaceKinds {
+		if !r.gvkSupported(gvk) {
+			continue
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.List(ctx, list, client.MatchingLabels(r.crossNamespaceLabels(install))); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			key := crossNamespaceKey{Kind: gvk.Kind, NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+			if keep[key] {
+				continue
+			}
+			logger.Info("deleting cross-namespace resource", "kind", gvk.Kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+			if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *SchedulerInstallReconciler) updateStatus(ctx context.Context, install *simv1alpha1.SchedulerInstall, conditions *installConditions) error {
 	latest := &simv1alpha1.SchedulerInstall{}
 	if err := r.Get(ctx, types.NamespacedName{Name: install.Name, Namespace: install.Namespace}, latest); err != nil {
 		return err
 	}
-	found := false
-	for i, c := range latest.Status.Conditions {
-		if c.Type == "Ready" {
-			latest.Status.Conditions[i] = condition
-			found = true
-			break
-		}
+
+	for _, condition := range conditions.all() {
+		condition.ObservedGeneration = latest.Generation
+		meta.SetStatusCondition(&latest.Status.Conditions, condition)
 	}
-	if !found {
-		latest.Status.Conditions = append(latest.Status.Conditions, condition)
+	for _, conditionType := range conditions.cleared {
+		meta.RemoveStatusCondition(&latest.Status.Conditions, conditionType)
 	}
+	latest.Status.Canary = install.Status.Canary
+	latest.Status.Simulator = install.Status.Simulator
 	return r.Status().Update(ctx, latest)
 }
 
+// bindSimulator resolves spec.simulatorRef, derives the fields it leaves unset
+// and reports SimulatorReady from the Ready condition of the SimulatorDeployment.
+// It returns false when the SimulatorDeployment does not exist.
+func (r *SchedulerInstallReconciler) bindSimulator(ctx context.Context, install *simv1alpha1.SchedulerInstall, conditions *installConditions) (bool, error) {
+	ref := install.Spec.SimulatorRef
+	install.Status.Simulator = &simv1alpha1.SimulatorBindingStatus{Name: ref.Name, Namespace: ref.Namespace}
+
+	simDep := &simv1alpha1.SimulatorDeployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, simDep); err != nil {
+		if errors.IsNotFound(err) {
+			conditions.add(conditionSimulatorReady, metav1.ConditionFalse, "SimulatorNotFound",
+				fmt.Sprintf("SimulatorDeployment %s/%s does not exist", ref.Namespace, ref.Name))
+			return false, nil
+		}
+		return false, err
+	}
+	simDep.Default()
+	install.BindSimulator(simDep)
+
+	ready := meta.FindStatusCondition(simDep.Status.Conditions, "Ready")
+	switch {
+	case ready == nil:
+		conditions.add(conditionSimulatorReady, metav1.ConditionUnknown, "Pending", "SimulatorDeployment has not reported a Ready condition yet")
+	case ready.Status == metav1.ConditionTrue:
+		install.Status.Simulator.Ready = true
+		conditions.add(conditionSimulatorReady, metav1.ConditionTrue, ready.Reason, ready.Message)
+	default:
+		conditions.add(conditionSimulatorReady, ready.Status, ready.Reason, ready.Message)
+	}
+	return true, nil
+}
+
+// eppCondition reports EPPReady from the EPP Deployment's ready replicas
+func (r *SchedulerInstallReconciler) eppCondition(ctx context.Context, install *simv1alpha1.SchedulerInstall, conditions *installConditions) error {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: install.Spec.EPP.Name, Namespace: install.Spec.SchedulerNamespace}, deployment); err != nil {
+		return err
+	}
+	desired := install.Spec.EPP.Replicas
+	message := fmt.Sprintf("%d/%d EPP replicas ready", deployment.Status.ReadyReplicas, desired)
+	if deployment.Status.ReadyReplicas >= desired {
+		conditions.add(conditionEPPReady, metav1.ConditionTrue, "ReplicasReady", message)
+	} else {
+		conditions.add(conditionEPPReady, metav1.ConditionFalse, "ReplicasNotReady", message)
+	}
+	return nil
+}
+
+// gatewayCondition copies the Gateway's Programmed condition into GatewayProgrammed
+func (r *SchedulerInstallReconciler) gatewayCondition(ctx context.Context, install *simv1alpha1.SchedulerInstall, conditions *installConditions) error {
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: install.Spec.Gateway.Name, Namespace: install.Spec.SchedulerNamespace}, gateway); err != nil {
+		return err
+	}
+	statusConditions, _, _ := unstructured.NestedSlice(gateway.Object, "status", "conditions")
+	if c, ok := findUnstructuredCondition(statusConditions, "Programmed"); ok {
+		conditions.add(conditionGatewayProgrammed, c.Status, c.Reason, c.Message)
+		return nil
+	}
+	conditions.add(conditionGatewayProgrammed, metav1.ConditionUnknown, "Pending", "Gateway has not reported a Programmed condition yet")
+	return nil
+}
+
+// routeCondition copies the HTTPRoute's per-parent Accepted conditions into RouteAccepted
+func (r *SchedulerInstallReconciler) routeCondition(ctx context.Context, install *simv1alpha1.SchedulerInstall, conditions *installConditions) error {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: install.Spec.Routing.HTTPRouteName, Namespace: install.Spec.SchedulerNamespace}, route); err != nil {
+		return err
+	}
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	var accepted *metav1.Condition
+	for _, parent := range parents {
+		parentMap, ok := parent.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		statusConditions, _, _ := unstructured.NestedSlice(parentMap, "conditions")
+		c, ok := findUnstructuredCondition(statusConditions, "Accepted")
+		if !ok {
+			continue
+		}
+		// Report the first parent that has not accepted the route
+		if c.Status != metav1.ConditionTrue {
+			conditions.add(conditionRouteAccepted, c.Status, c.Reason, c.Message)
+			return nil
+		}
+		accepted = &c
+	}
+	if accepted != nil {
+		conditions.add(conditionRouteAccepted, accepted.Status, accepted.Reason, accepted.Message)
+		return nil
+	}
+	conditions.add(conditionRouteAccepted, metav1.ConditionUnknown, "Pending", "HTTPRoute has not been accepted by a parent Gateway yet")
+	return nil
+}
+
+// findUnstructuredCondition returns the condition of type conditionType from an unstructured conditions list
+func findUnstructuredCondition(statusConditions []interface{}, conditionType string) (metav1.Condition, bool) {
+	for _, item := range statusConditions {
+		c, ok := item.(map[string]interface{})
+		if !ok || c["type"] != conditionType {
+			continue
+		}
+		condition := metav1.Condition{Type: conditionType, Status: metav1.ConditionUnknown, Reason: "Unknown"}
+		if status, ok := c["status"].(string); ok {
+			condition.Status = metav1.ConditionStatus(status)
+		}
+		if reason, ok := c["reason"].(string); ok && reason != "" {
+			condition.Reason = reason
+		}
+		if message, ok := c["message"].(string); ok {
+			condition.Message = message
+		}
+		return condition, true
+	}
+	return metav1.Condition{}, false
+}
+
+// installConditions collects the per-area conditions written at the end of a reconcile
+type installConditions struct {
+	conditions []metav1.Condition
+	cleared    []string
+	missing    []string
+	errs       []error
+}
+
+func (c *installConditions) add(conditionType string, status metav1.ConditionStatus, reason, message string) {
+	c.conditions = append(c.conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+func (c *installConditions) ready(conditionType, message string) {
+	c.add(conditionType, metav1.ConditionTrue, "Reconciled", message)
+}
+
+func (c *installConditions) failed(conditionType string, err error) {
+	c.errs = append(c.errs, err)
+	c.add(conditionType, metav1.ConditionFalse, "ReconcileFailed", err.Error())
+}
+
+// skipped reports a resource that was not created because its CRD is not installed
+func (c *installConditions) skipped(conditionType string, gvk schema.GroupVersionKind) {
+	c.missing = append(c.missing, fmt.Sprintf("%s (%s)", gvk.Kind, gvk.GroupVersion()))
+	c.add(conditionType, metav1.ConditionFalse, "CRDNotInstalled",
+		fmt.Sprintf("%s was skipped because the %s CRD is not installed", gvk.Kind, gvk.GroupVersion()))
+}
+
+// disabled drops the condition of an area that is switched off in the spec
+func (c *installConditions) disabled(conditionType string) {
+	c.cleared = append(c.cleared, conditionType)
+}
+
+// allReady reports whether every enabled area is ready and nothing failed
+func (c *installConditions) allReady() bool {
+	if len(c.errs) > 0 {
+		return false
+	}
+	for _, condition := range c.conditions {
+		if condition.Status != metav1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// all returns the area conditions plus the derived CRDsMissing and Ready conditions
+func (c *installConditions) all() []metav1.Condition {
+	result := append([]metav1.Condition{}, c.conditions...)
+
+	crds := metav1.Condition{
+		Type:    conditionCRDsMissing,
+		Status:  metav1.ConditionFalse,
+		Reason:  "AllCRDsInstalled",
+		Message: "All CRDs required by the spec are installed",
+	}
+	if len(c.missing) > 0 {
+		crds.Status = metav1.ConditionTrue
+		crds.Reason = "CRDsNotInstalled"
+		crds.Message = fmt.Sprintf("Skipped resources whose CRDs are not installed: %s", strings.Join(c.missing, ", "))
+	}
+	result = append(result, crds)
+
+	ready := metav1.Condition{
+		Type:    conditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciled",
+		Message: "SchedulerInstall resources are ready",
+	}
+	if len(c.errs) > 0 {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "ReconcileFailed"
+		ready.Message = utilerrors.NewAggregate(c.errs).Error()
+	} else if !c.allReady() {
+		var pending []string
+		for _, condition := range c.conditions {
+			if condition.Status != metav1.ConditionTrue {
+				pending = append(pending, condition.Type)
+			}
+		}
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "NotReady"
+		ready.Message = fmt.Sprintf("Waiting for: %s", strings.Join(pending, ", "))
+	}
+	return append(result, ready)
+}
+
 func (r *SchedulerInstallReconciler) gvkSupported(gvk schema.GroupVersionKind) bool {
 	if r.RESTMapper == nil {
 		return true
@@ -969,11 +1539,64 @@ func (r *SchedulerInstallReconciler) gvkSupported(gvk schema.GroupVersionKind) b
 	return err == nil
 }
 
+// ownObject marks obj as owned by install. Owner references cannot cross
+// namespaces, so every object also carries crossNamespaceLabels, which the
+// cleanup finalizer uses to find it.
+func (r *SchedulerInstallReconciler) ownObject(install *simv1alpha1.SchedulerInstall, obj client.Object) error {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range r.crossNamespaceLabels(install) {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+	if obj.GetNamespace() == install.Namespace {
+		return controllerutil.SetControllerReference(install, obj, r.Scheme)
+	}
+	return nil
+}
+
 func (r *SchedulerInstallReconciler) crossNamespaceLabels(install *simv1alpha1.SchedulerInstall) map[string]string {
 	return map[string]string{
-		"sim.llm-d.io/schedulerInstall":   install.Name,
-		"sim.llm-d.io/schedulerNamespace": install.Namespace,
+		schedulerInstallLabel:   install.Name,
+		schedulerNamespaceLabel: install.Namespace,
+	}
+}
+
+// schedulerInstallForObject maps an object created for a SchedulerInstall back
+// to it, through crossNamespaceLabels or the controller reference
+func (r *SchedulerInstallReconciler) schedulerInstallForObject(ctx context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if name, namespace := labels[schedulerInstallLabel], labels[schedulerNamespaceLabel]; name != "" && namespace != "" {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+	}
+	owner := metav1.GetControllerOf(obj)
+	if owner != nil && owner.Kind == "SchedulerInstall" && owner.APIVersion == simv1alpha1.GroupVersion.String() {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: owner.Name, Namespace: obj.GetNamespace()}}}
+	}
+	return nil
+}
+
+// schedulerInstallsForSimulator maps a SimulatorDeployment to the
+// SchedulerInstalls whose simulatorRef names it
+func (r *SchedulerInstallReconciler) schedulerInstallsForSimulator(ctx context.Context, obj client.Object) []reconcile.Request {
+	installs := &simv1alpha1.SchedulerInstallList{}
+	if err := r.List(ctx, installs); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list SchedulerInstalls")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range installs.Items {
+		install := &installs.Items[i]
+		// The reference namespace may be defaulted in memory only
+		install.Default()
+		ref := install.Spec.SimulatorRef
+		if ref != nil && ref.Name == obj.GetName() && ref.Namespace == obj.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(install)})
+		}
 	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -981,11 +1604,31 @@ func (r *SchedulerInstallReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.RESTMapper == nil {
 		r.RESTMapper = mgr.GetRESTMapper()
 	}
-	return ctrl.NewControllerManagedBy(mgr).
+	logger := mgr.GetLogger().WithValues("controller", "schedulerinstall")
+	mapToInstall := handler.EnqueueRequestsFromMapFunc(r.schedulerInstallForObject)
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&simv1alpha1.SchedulerInstall{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
-		Complete(r)
+		Watches(&simv1alpha1.SimulatorDeployment{}, handler.EnqueueRequestsFromMapFunc(r.schedulerInstallsForSimulator))
+
+	// Objects in the simulator namespace cannot carry an owner reference, so map them by label
+	for _, obj := range []client.Object{&corev1.Service{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+		b = b.Watches(obj, mapToInstall)
+	}
+
+	// Optional kinds can only be watched when their CRDs exist; CRDs installed later need an operator restart
+	for _, gvk := range watchedGVKs {
+		if !r.gvkSupported(gvk) {
+			logger.Info("not watching kind, CRD is not installed", "kind", gvk.Kind, "groupVersion", gvk.GroupVersion().String())
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		b = b.Watches(obj, mapToInstall)
+	}
+	return b.Complete(r)
 }
@@ -0,0 +1,733 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// newSchedulerInstall returns a SchedulerInstall with every area enabled, like the sample manifest
+func newSchedulerInstall(namespace, simulatorNamespace string) *simv1alpha1.SchedulerInstall {
+	return &simv1alpha1.SchedulerInstall{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm-sched-install", Namespace: namespace, UID: "install-uid"},
+		Spec: simv1alpha1.SchedulerInstallSpec{
+			SimulatorNamespace: simulatorNamespace,
+			EPP:                &simv1alpha1.SchedulerEPPConfig{Enabled: true},
+			Gateway:            &simv1alpha1.SchedulerGatewayConfig{Enabled: true},
+			Routing:            &simv1alpha1.SchedulerRoutingConfig{Enabled: true},
+			DestinationRule: &simv1alpha1.LoadBalancingConfig{
+				Enabled:   true,
+				Algorithm: "ROUND_ROBIN",
+				ConnectionPool: &simv1alpha1.ConnectionPoolConfig{
+					HTTP1MaxPendingRequests:  1,
+					MaxRequestsPerConnection: 1,
+				},
+			},
+			EnvoyFilter:   &simv1alpha1.SchedulerEnvoyFilterConfig{Enabled: true},
+			InferencePool: &simv1alpha1.SchedulerInferencePoolConfig{Enabled: true},
+		},
+	}
+}
+
+func newSchedulerInstallReconciler(scheme *runtime.Scheme, c client.Client) *SchedulerInstallReconciler {
+	return &SchedulerInstallReconciler{Client: c, Scheme: scheme, RESTMapper: newRESTMapper(watchedGVKs...)}
+}
+
+// eppService returns the EPP Service as the apiserver would have allocated it
+func eppService(install *simv1alpha1.SchedulerInstall) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "gaie-inference-scheduling-epp", Namespace: install.Namespace},
+		Spec:       corev1.ServiceSpec{ClusterIP: "10.96.0.20"},
+	}
+}
+
+func TestSchedulerInstallReconcileEPPDeployment(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Default()
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme))
+
+	ctx := context.Background()
+	if err := r.reconcileEPPDeployment(ctx, install, "rendered config"); err != nil {
+		t.Fatalf("reconcileEPPDeployment: %v", err)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: install.Namespace, Name: install.Spec.EPP.Name}, deployment); err != nil {
+		t.Fatalf("get EPP Deployment: %v", err)
+	}
+	expectGolden(t, scheme, "schedulerinstall-epp-deployment", deployment)
+}
+
+func TestSchedulerInstallReconcileEPPEnv(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Spec.EPP.Env = []corev1.EnvVar{{Name: "SCHEDULING_DECISIONS_ADDR", Value: ":9005"}}
+	install.Default()
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme))
+
+	ctx := context.Background()
+	if err := r.reconcileEPPDeployment(ctx, install, "rendered config"); err != nil {
+		t.Fatalf("reconcileEPPDeployment: %v", err)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: install.Namespace, Name: install.Spec.EPP.Name}, deployment); err != nil {
+		t.Fatalf("get EPP Deployment: %v", err)
+	}
+	if env := deployment.Spec.Template.Spec.Containers[0].Env; !reflect.DeepEqual(env, install.Spec.EPP.Env) {
+		t.Errorf("EPP env = %v, want %v", env, install.Spec.EPP.Env)
+	}
+}
+
+func TestSchedulerInstallReconcileHTTPRoute(t *testing.T) {
+	tests := []struct {
+		name        string
+		backendType string
+	}{
+		{name: "service", backendType: "Service"},
+		{name: "inferencepool", backendType: "InferencePool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := newScheme()
+			install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+			install.Spec.Routing.BackendType = tt.backendType
+			install.Default()
+			r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme))
+
+			if err := r.reconcileHTTPRoute(context.Background(), install); err != nil {
+				t.Fatalf("reconcileHTTPRoute: %v", err)
+			}
+			route := getUnstructured(t, r.Client, httpRouteGVK, install.Namespace, install.Spec.Routing.HTTPRouteName)
+			expectGolden(t, scheme, "schedulerinstall-httproute-"+tt.name, route)
+		})
+	}
+}
+
+// abRules splits traffic between two proxies by the x-kv-backend header, like
+// disaggregated-serving/gateway-routing/httproute-ab.yaml
+func abRules() []simv1alpha1.HTTPRouteRule {
+	headerRule := func(value, proxy string) simv1alpha1.HTTPRouteRule {
+		return simv1alpha1.HTTPRouteRule{
+			Matches: []simv1alpha1.HTTPRouteMatch{{
+				Path:    &simv1alpha1.HTTPPathMatch{},
+				Headers: []simv1alpha1.HTTPValueMatch{{Name: "x-kv-backend", Value: value}},
+			}},
+			BackendRefs: []simv1alpha1.HTTPBackendRef{{Name: proxy, Namespace: "llm-d-vllm", Port: 8080}},
+		}
+	}
+	weight := int32(90)
+	canary := int32(10)
+	return []simv1alpha1.HTTPRouteRule{
+		headerRule("nvlink", "vllm-p2p-proxy"),
+		headerRule("fs", "vllm-fs-proxy"),
+		{
+			Matches: []simv1alpha1.HTTPRouteMatch{{
+				QueryParams: []simv1alpha1.HTTPValueMatch{{Type: "RegularExpression", Name: "backend", Value: "pool-.*"}},
+			}},
+			BackendRefs: []simv1alpha1.HTTPBackendRef{
+				{Kind: "InferencePool", Name: "gaie-inference-scheduling", Weight: &weight},
+				{Name: "gaie-inference-scheduling-proxy", Port: 8200, Weight: &canary},
+			},
+		},
+	}
+}
+
+func TestSchedulerInstallReconcileHTTPRouteRules(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Spec.Routing.Rules = abRules()
+	install.Default()
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme))
+
+	if err := r.reconcileHTTPRoute(context.Background(), install); err != nil {
+		t.Fatalf("reconcileHTTPRoute: %v", err)
+	}
+	route := getUnstructured(t, r.Client, httpRouteGVK, install.Namespace, install.Spec.Routing.HTTPRouteName)
+	expectGolden(t, scheme, "schedulerinstall-httproute-rules", route)
+}
+
+func TestSchedulerInstallReferenceGrantsFollowRules(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Spec.Routing.Rules = abRules()
+	install.Default()
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme))
+
+	ctx := context.Background()
+	if err := r.reconcileReferenceGrant(ctx, install); err != nil {
+		t.Fatalf("reconcileReferenceGrant: %v", err)
+	}
+	for _, namespace := range []string{"llm-d-sim", "llm-d-vllm"} {
+		getUnstructured(t, r.Client, referenceGrantGVK, namespace, referenceGrantName(install))
+	}
+
+	// Dropping the rules leaves the llm-d-vllm grant unreferenced
+	install.Spec.Routing.Rules = nil
+	if err := r.cleanupCrossNamespaceResources(ctx, install, r.desiredCrossNamespaceResources(install)); err != nil {
+		t.Fatalf("cleanupCrossNamespaceResources: %v", err)
+	}
+	getUnstructured(t, r.Client, referenceGrantGVK, "llm-d-sim", referenceGrantName(install))
+	grant := &unstructured.Unstructured{}
+	grant.SetGroupVersionKind(referenceGrantGVK)
+	err := r.Get(ctx, types.NamespacedName{Namespace: "llm-d-vllm", Name: referenceGrantName(install)}, grant)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("ReferenceGrant in llm-d-vllm was not removed: %v", err)
+	}
+}
+
+func TestSchedulerInstallReferenceGrantsPerInstall(t *testing.T) {
+	scheme := newScheme()
+	first := newSchedulerInstall("team-a", "llm-d-sim")
+	first.Default()
+	second := newSchedulerInstall("team-b", "llm-d-sim")
+	second.Default()
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme))
+
+	ctx := context.Background()
+	for _, install := range []*simv1alpha1.SchedulerInstall{first, second} {
+		if err := r.reconcileReferenceGrant(ctx, install); err != nil {
+			t.Fatalf("reconcileReferenceGrant %s: %v", install.Namespace, err)
+		}
+	}
+
+	// Deleting the install that wrote last leaves the grant of the other in place
+	if err := r.cleanupCrossNamespaceResources(ctx, second, nil); err != nil {
+		t.Fatalf("cleanupCrossNamespaceResources: %v", err)
+	}
+	remaining := getUnstructured(t, r.Client, referenceGrantGVK, "llm-d-sim", referenceGrantName(first))
+	from, _, _ := unstructured.NestedSlice(remaining.Object, "spec", "from")
+	if len(from) != 1 || from[0].(map[string]interface{})["namespace"] != first.Spec.SchedulerNamespace {
+		t.Errorf("remaining ReferenceGrant from = %v, want the HTTPRoute of the first install", from)
+	}
+}
+
+func TestSchedulerInstallReconcileEnvoyFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterType string
+		mutate      func(*simv1alpha1.SchedulerEnvoyFilterConfig)
+		golden      string
+		wantAddress string
+	}{
+		{
+			name:        "static",
+			clusterType: simv1alpha1.EnvoyFilterClusterStatic,
+			golden:      "schedulerinstall-envoyfilter",
+			wantAddress: "10.96.0.20:9002",
+		},
+		{
+			name:        "strict dns",
+			clusterType: simv1alpha1.EnvoyFilterClusterStrictDNS,
+			golden:      "schedulerinstall-envoyfilter-strict-dns",
+			wantAddress: "gaie-inference-scheduling-epp.llm-d-inference-scheduler.svc.cluster.local:9002",
+		},
+		{
+			name:        "full duplex streaming",
+			clusterType: simv1alpha1.EnvoyFilterClusterStatic,
+			mutate: func(config *simv1alpha1.SchedulerEnvoyFilterConfig) {
+				failureModeAllow := false
+				config.ProcessingMode = &simv1alpha1.ExtProcProcessingMode{
+					RequestHeaderMode:  "SEND",
+					RequestBodyMode:    "FULL_DUPLEX_STREAMED",
+					ResponseHeaderMode: "SEND",
+					ResponseBodyMode:   "FULL_DUPLEX_STREAMED",
+				}
+				config.Timeout = &metav1.Duration{Duration: 10 * time.Second}
+				config.MessageTimeout = &metav1.Duration{Duration: 500 * time.Millisecond}
+				config.FailureModeAllow = &failureModeAllow
+				config.AllowModeOverride = true
+			},
+			golden:      "schedulerinstall-envoyfilter-full-duplex",
+			wantAddress: "10.96.0.20:9002",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := newScheme()
+			install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+			install.Spec.EnvoyFilter.ClusterType = tt.clusterType
+			if tt.mutate != nil {
+				tt.mutate(install.Spec.EnvoyFilter)
+			}
+			install.Default()
+			r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, eppService(install)))
+
+			address, err := r.reconcileEnvoyFilter(context.Background(), install)
+			if err != nil {
+				t.Fatalf("reconcileEnvoyFilter: %v", err)
+			}
+			if address != tt.wantAddress {
+				t.Errorf("address = %q, want %q", address, tt.wantAddress)
+			}
+			filter := getUnstructured(t, r.Client, envoyFilterGVK, install.Namespace, install.Spec.EnvoyFilter.Name)
+			expectGolden(t, scheme, tt.golden, filter)
+		})
+	}
+}
+
+func TestProtoDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{duration: 2 * time.Second, want: "2s"},
+		{duration: 500 * time.Millisecond, want: "0.5s"},
+		{duration: 90 * time.Second, want: "90s"},
+		{duration: time.Minute + 250*time.Millisecond, want: "60.25s"},
+	}
+	for _, tt := range tests {
+		if got := protoDuration(tt.duration); got != tt.want {
+			t.Errorf("protoDuration(%v) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestSchedulerInstallReconcileEnvoyFilterFollowsClusterIP(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Default()
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, eppService(install)))
+
+	ctx := context.Background()
+	if _, err := r.reconcileEnvoyFilter(ctx, install); err != nil {
+		t.Fatalf("reconcileEnvoyFilter: %v", err)
+	}
+
+	// Recreate the EPP Service with a new ClusterIP, as happens after `kubectl delete svc`
+	if err := r.Delete(ctx, eppService(install)); err != nil {
+		t.Fatalf("delete EPP Service: %v", err)
+	}
+	recreated := eppService(install)
+	recreated.Spec.ClusterIP = "10.96.0.99"
+	if err := r.Create(ctx, recreated); err != nil {
+		t.Fatalf("recreate EPP Service: %v", err)
+	}
+
+	if _, err := r.reconcileEnvoyFilter(ctx, install); err != nil {
+		t.Fatalf("reconcileEnvoyFilter: %v", err)
+	}
+	filter := getUnstructured(t, r.Client, envoyFilterGVK, install.Namespace, install.Spec.EnvoyFilter.Name)
+	patches, _, _ := unstructured.NestedSlice(filter.Object, "spec", "configPatches")
+	cluster, _ := patches[1].(map[string]interface{})
+	endpoints, _, _ := unstructured.NestedSlice(cluster, "patch", "value", "load_assignment", "endpoints")
+	lbEndpoints, _, _ := unstructured.NestedSlice(endpoints[0].(map[string]interface{}), "lb_endpoints")
+	address, _, _ := unstructured.NestedString(lbEndpoints[0].(map[string]interface{}), "endpoint", "address", "socket_address", "address")
+	if address != "10.96.0.99" {
+		t.Errorf("epp-cluster address = %q, want the new ClusterIP 10.96.0.99", address)
+	}
+}
+
+func TestSchedulerInstallReconcileEnvoyFilterWithoutClusterIP(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Default()
+	service := eppService(install)
+	service.Spec.ClusterIP = ""
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, service))
+
+	_, err := r.reconcileEnvoyFilter(context.Background(), install)
+	if err == nil || !strings.Contains(err.Error(), "ClusterIP") {
+		t.Fatalf("reconcileEnvoyFilter error = %v, want a missing ClusterIP error", err)
+	}
+}
+
+func TestSchedulerInstallReconcileStatus(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, install, eppService(install)))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(install)}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if result.RequeueAfter != 15*time.Second {
+		t.Errorf("RequeueAfter = %v, want 15s while areas are pending", result.RequeueAfter)
+	}
+	expectConditions(t, r.Client, install, map[string]metav1.ConditionStatus{
+		conditionReady:                metav1.ConditionFalse,
+		conditionEPPReady:             metav1.ConditionFalse,
+		conditionGatewayProgrammed:    metav1.ConditionUnknown,
+		conditionRouteAccepted:        metav1.ConditionUnknown,
+		conditionReferenceGrantReady:  metav1.ConditionTrue,
+		conditionDestinationRuleReady: metav1.ConditionTrue,
+		conditionEnvoyFilterApplied:   metav1.ConditionTrue,
+		conditionInferencePoolReady:   metav1.ConditionTrue,
+		conditionCRDsMissing:          metav1.ConditionFalse,
+	})
+
+	// Report the EPP, Gateway and HTTPRoute as ready, as their controllers would
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: install.Namespace, Name: "gaie-inference-scheduling-epp"}, deployment); err != nil {
+		t.Fatalf("get EPP Deployment: %v", err)
+	}
+	deployment.Status.ReadyReplicas = 1
+	if err := r.Status().Update(ctx, deployment); err != nil {
+		t.Fatalf("update EPP Deployment status: %v", err)
+	}
+	accepted := []interface{}{map[string]interface{}{"type": "Programmed", "status": "True", "reason": "Programmed"}}
+	gateway := getUnstructured(t, r.Client, gatewayGVK, install.Namespace, "infra-inference-scheduling-inference-gateway")
+	if err := unstructured.SetNestedSlice(gateway.Object, accepted, "status", "conditions"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(ctx, gateway); err != nil {
+		t.Fatalf("update Gateway status: %v", err)
+	}
+	route := getUnstructured(t, r.Client, httpRouteGVK, install.Namespace, "llm-d-inference-scheduling")
+	parents := []interface{}{map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{"type": "Accepted", "status": "True", "reason": "Accepted"}},
+	}}
+	if err := unstructured.SetNestedSlice(route.Object, parents, "status", "parents"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(ctx, route); err != nil {
+		t.Fatalf("update HTTPRoute status: %v", err)
+	}
+
+	result, err = r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("RequeueAfter = %v, want no requeue once ready", result.RequeueAfter)
+	}
+	expectConditions(t, r.Client, install, map[string]metav1.ConditionStatus{
+		conditionReady:             metav1.ConditionTrue,
+		conditionEPPReady:          metav1.ConditionTrue,
+		conditionGatewayProgrammed: metav1.ConditionTrue,
+		conditionRouteAccepted:     metav1.ConditionTrue,
+	})
+}
+
+func TestSchedulerInstallReconcileMissingCRDs(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, install, eppService(install)))
+	r.RESTMapper = newRESTMapper()
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(install)}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	latest := expectConditions(t, r.Client, install, map[string]metav1.ConditionStatus{
+		conditionCRDsMissing:          metav1.ConditionTrue,
+		conditionGatewayProgrammed:    metav1.ConditionFalse,
+		conditionRouteAccepted:        metav1.ConditionFalse,
+		conditionReferenceGrantReady:  metav1.ConditionFalse,
+		conditionDestinationRuleReady: metav1.ConditionFalse,
+		conditionEnvoyFilterApplied:   metav1.ConditionFalse,
+		conditionInferencePoolReady:   metav1.ConditionFalse,
+	})
+	if c := meta.FindStatusCondition(latest.Status.Conditions, conditionGatewayProgrammed); c.Reason != "CRDNotInstalled" {
+		t.Errorf("GatewayProgrammed reason = %q, want CRDNotInstalled", c.Reason)
+	}
+
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	err := r.Get(context.Background(), types.NamespacedName{Namespace: install.Namespace, Name: "infra-inference-scheduling-inference-gateway"}, gateway)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Gateway was created although its CRD is missing: %v", err)
+	}
+}
+
+func TestSchedulerInstallReconcileDisabledAreas(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	install.Status.Conditions = []metav1.Condition{{
+		Type: conditionGatewayProgrammed, Status: metav1.ConditionTrue, Reason: "Programmed", LastTransitionTime: metav1.Now(),
+	}}
+	install.Spec.Gateway.Enabled = false
+	install.Spec.Routing.Enabled = false
+	install.Spec.EnvoyFilter.Enabled = false
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, install))
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(install)}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	latest := expectConditions(t, r.Client, install, map[string]metav1.ConditionStatus{})
+	for _, conditionType := range []string{conditionGatewayProgrammed, conditionRouteAccepted, conditionReferenceGrantReady, conditionEnvoyFilterApplied} {
+		if meta.FindStatusCondition(latest.Status.Conditions, conditionType) != nil {
+			t.Errorf("condition %s is still reported for a disabled area", conditionType)
+		}
+	}
+}
+
+func TestSchedulerInstallReconcileSimulatorRef(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "")
+	install.Spec.SimulatorRef = &simv1alpha1.SimulatorReference{Name: "llm-sim-full", Namespace: "llm-d-sim"}
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, install, eppService(install)))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(install)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	latest := expectConditions(t, r.Client, install, map[string]metav1.ConditionStatus{
+		conditionReady:          metav1.ConditionFalse,
+		conditionSimulatorReady: metav1.ConditionFalse,
+	})
+	if got := latest.Status.Simulator; got == nil || got.Name != "llm-sim-full" || got.Ready {
+		t.Errorf("status.simulator = %+v, want llm-sim-full not ready", got)
+	}
+	proxyKey := types.NamespacedName{Namespace: "llm-d-sim", Name: "gaie-inference-scheduling-proxy"}
+	if err := r.Get(ctx, proxyKey, &corev1.Service{}); !apierrors.IsNotFound(err) {
+		t.Errorf("get proxy Service = %v, want NotFound while the simulator is missing", err)
+	}
+
+	// Creating the simulator requeues the install
+	simDep := newSimulatorDeployment("llm-d-sim")
+	simDep.Spec.Decode.Port = 8300
+	if err := r.Create(ctx, simDep); err != nil {
+		t.Fatal(err)
+	}
+	requests := r.schedulerInstallsForSimulator(ctx, simDep)
+	if len(requests) != 1 || requests[0].NamespacedName != req.NamespacedName {
+		t.Fatalf("requests for the simulator = %v, want %v", requests, req.NamespacedName)
+	}
+	if requests := r.schedulerInstallsForSimulator(ctx, newSimulatorDeployment("other")); len(requests) != 0 {
+		t.Errorf("requests for an unrelated simulator = %v", requests)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	selector := map[string]string{
+		"llm-d.ai/role":             "decode",
+		"llm-d.ai/inferenceServing": "true",
+		"app.kubernetes.io/name":    "llm-sim-full",
+	}
+	proxy := &corev1.Service{}
+	if err := r.Get(ctx, proxyKey, proxy); err != nil {
+		t.Fatalf("get proxy Service: %v", err)
+	}
+	if !reflect.DeepEqual(proxy.Spec.Selector, selector) || proxy.Spec.Ports[0].TargetPort.IntValue() != 8300 {
+		t.Errorf("proxy Service selects %v on %s, want the simulator decode pods on 8300", proxy.Spec.Selector, proxy.Spec.Ports[0].TargetPort.String())
+	}
+	pool := getUnstructured(t, r.Client, inferencePoolGVK, "llm-d-sim", "gaie-inference-scheduling")
+	matchLabels, _, _ := unstructured.NestedStringMap(pool.Object, "spec", "selector", "matchLabels")
+	targetPorts, _, _ := unstructured.NestedSlice(pool.Object, "spec", "targetPorts")
+	if !reflect.DeepEqual(matchLabels, selector) || len(targetPorts) != 1 || targetPorts[0].(map[string]interface{})["number"] != int64(8300) {
+		t.Errorf("InferencePool selects %v on %v, want the simulator decode pods on 8300", matchLabels, targetPorts)
+	}
+	expectConditions(t, r.Client, install, map[string]metav1.ConditionStatus{conditionSimulatorReady: metav1.ConditionUnknown})
+
+	// The simulator becoming ready is reflected in the status
+	meta.SetStatusCondition(&simDep.Status.Conditions, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "DeploymentReady"})
+	if err := r.Status().Update(ctx, simDep); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	latest = expectConditions(t, r.Client, install, map[string]metav1.ConditionStatus{conditionSimulatorReady: metav1.ConditionTrue})
+	if !latest.Status.Simulator.Ready {
+		t.Errorf("status.simulator.ready = false, want true")
+	}
+}
+
+func TestInstallConditions(t *testing.T) {
+	tests := []struct {
+		name        string
+		record      func(c *installConditions)
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:       "all ready",
+			record:     func(c *installConditions) { c.ready(conditionEPPReady, "ok") },
+			wantReason: "Reconciled",
+		},
+		{
+			name: "pending areas",
+			record: func(c *installConditions) {
+				c.ready(conditionEPPReady, "ok")
+				c.add(conditionGatewayProgrammed, metav1.ConditionUnknown, "Pending", "")
+			},
+			wantReason:  "NotReady",
+			wantMessage: "Waiting for: GatewayProgrammed",
+		},
+		{
+			name: "failure wins over pending",
+			record: func(c *installConditions) {
+				c.add(conditionGatewayProgrammed, metav1.ConditionUnknown, "Pending", "")
+				c.failed(conditionEPPReady, apierrors.NewBadRequest("boom"))
+			},
+			wantReason:  "ReconcileFailed",
+			wantMessage: "boom",
+		},
+		{
+			name:       "skipped area",
+			record:     func(c *installConditions) { c.skipped(conditionGatewayProgrammed, gatewayGVK) },
+			wantReason: "NotReady",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &installConditions{}
+			tt.record(c)
+			all := c.all()
+			ready := meta.FindStatusCondition(all, conditionReady)
+			if ready == nil || ready.Reason != tt.wantReason {
+				t.Fatalf("Ready = %+v, want reason %s", ready, tt.wantReason)
+			}
+			if !strings.Contains(ready.Message, tt.wantMessage) {
+				t.Errorf("Ready message %q does not contain %q", ready.Message, tt.wantMessage)
+			}
+			if crds := meta.FindStatusCondition(all, conditionCRDsMissing); (crds.Status == metav1.ConditionTrue) != (len(c.missing) > 0) {
+				t.Errorf("CRDsMissing = %s with missing %v", crds.Status, c.missing)
+			}
+		})
+	}
+}
+
+func TestSchedulerInstallReconcileEnvtest(t *testing.T) {
+	requireEnvtest(t)
+
+	ctx := context.Background()
+	schedulerNamespace := createNamespace(t, "scheduler")
+	simulatorNamespace := createNamespace(t, "sim")
+	install := newSchedulerInstall(schedulerNamespace, simulatorNamespace)
+	install.UID = ""
+	if err := k8sClient.Create(ctx, install); err != nil {
+		t.Fatalf("create SchedulerInstall: %v", err)
+	}
+
+	r := &SchedulerInstallReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), RESTMapper: k8sRESTMapper}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(install)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	getUnstructured(t, k8sClient, gatewayGVK, schedulerNamespace, "infra-inference-scheduling-inference-gateway")
+	getUnstructured(t, k8sClient, httpRouteGVK, schedulerNamespace, "llm-d-inference-scheduling")
+	getUnstructured(t, k8sClient, envoyFilterGVK, schedulerNamespace, "epp-ext-proc")
+	getUnstructured(t, k8sClient, referenceGrantGVK, simulatorNamespace, referenceGrantName(install))
+	getUnstructured(t, k8sClient, destinationRuleGVK, simulatorNamespace, "gaie-inference-scheduling-proxy-lb")
+	getUnstructured(t, k8sClient, inferencePoolGVK, simulatorNamespace, "gaie-inference-scheduling")
+
+	// Deleting the SchedulerInstall removes the cross-namespace objects through the finalizer
+	if err := k8sClient.Delete(ctx, install); err != nil {
+		t.Fatalf("delete SchedulerInstall: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile after delete: %v", err)
+	}
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(destinationRuleGVK)
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: simulatorNamespace, Name: "gaie-inference-scheduling-proxy-lb"}, rule)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("DestinationRule survived the SchedulerInstall: %v", err)
+	}
+}
+
+// expectConditions checks the stored condition statuses of install and returns the latest object
+func expectConditions(t *testing.T, c client.Client, install *simv1alpha1.SchedulerInstall, want map[string]metav1.ConditionStatus) *simv1alpha1.SchedulerInstall {
+	t.Helper()
+	latest := &simv1alpha1.SchedulerInstall{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(install), latest); err != nil {
+		t.Fatalf("get SchedulerInstall: %v", err)
+	}
+	for conditionType, status := range want {
+		condition := meta.FindStatusCondition(latest.Status.Conditions, conditionType)
+		if condition == nil {
+			t.Errorf("condition %s is missing", conditionType)
+			continue
+		}
+		if condition.Status != status {
+			t.Errorf("condition %s = %s (%s: %s), want %s", conditionType, condition.Status, condition.Reason, condition.Message, status)
+		}
+	}
+	return latest
+}
+
+func TestSchedulerInstallForObject(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme))
+	want := client.ObjectKeyFromObject(install)
+
+	labelled := &unstructured.Unstructured{}
+	labelled.SetGroupVersionKind(destinationRuleGVK)
+	labelled.SetNamespace("llm-d-sim")
+	labelled.SetLabels(r.crossNamespaceLabels(install))
+
+	owned := &unstructured.Unstructured{}
+	owned.SetGroupVersionKind(envoyFilterGVK)
+	owned.SetNamespace(install.Namespace)
+	if err := controllerutil.SetControllerReference(install, owned, scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	foreign := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "llm-d-sim", Name: "other"}}
+
+	for name, tt := range map[string]struct {
+		obj  client.Object
+		want []types.NamespacedName
+	}{
+		"labelled":  {obj: labelled, want: []types.NamespacedName{want}},
+		"owned":     {obj: owned, want: []types.NamespacedName{want}},
+		"unrelated": {obj: foreign},
+	} {
+		t.Run(name, func(t *testing.T) {
+			requests := r.schedulerInstallForObject(context.Background(), tt.obj)
+			if len(requests) != len(tt.want) {
+				t.Fatalf("requests = %v, want %v", requests, tt.want)
+			}
+			for i := range requests {
+				if requests[i].NamespacedName != tt.want[i] {
+					t.Errorf("request = %v, want %v", requests[i].NamespacedName, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerInstallReconcileRevertsDrift(t *testing.T) {
+	scheme := newScheme()
+	install := newSchedulerInstall("llm-d-inference-scheduler", "llm-d-sim")
+	r := newSchedulerInstallReconciler(scheme, newFakeClient(scheme, install, eppService(install)))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(install)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	filter := getUnstructured(t, r.Client, envoyFilterGVK, install.Namespace, "epp-ext-proc")
+	if err := r.Delete(ctx, filter); err != nil {
+		t.Fatalf("delete EnvoyFilter: %v", err)
+	}
+	rule := getUnstructured(t, r.Client, destinationRuleGVK, "llm-d-sim", "gaie-inference-scheduling-proxy-lb")
+	if err := unstructured.SetNestedField(rule.Object, "LEAST_REQUEST", "spec", "trafficPolicy", "loadBalancer", "simple"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(ctx, rule); err != nil {
+		t.Fatalf("edit DestinationRule: %v", err)
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	getUnstructured(t, r.Client, envoyFilterGVK, install.Namespace, "epp-ext-proc")
+	rule = getUnstructured(t, r.Client, destinationRuleGVK, "llm-d-sim", "gaie-inference-scheduling-proxy-lb")
+	if algorithm, _, _ := unstructured.NestedString(rule.Object, "spec", "trafficPolicy", "loadBalancer", "simple"); algorithm != "ROUND_ROBIN" {
+		t.Errorf("DestinationRule algorithm = %q, want the edit reverted to ROUND_ROBIN", algorithm)
+	}
+}
@@ -4,14 +4,20 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -25,25 +31,82 @@ func stringPtr(s string) *string {
 	return &s
 }
 
-func (r *SimulatorDeploymentReconciler) buildSimulatorArgs(verbosity int32, port int32, extraArgs []string) []string {
+// buildSimulatorArgs renders the simulator command line; a nil behavior keeps
+// the simulator defaults of a random model in random mode
+func (r *SimulatorDeploymentReconciler) buildSimulatorArgs(verbosity int32, port int32, behavior *simv1alpha1.SimulatorBehavior, extraArgs []string) []string {
+	if behavior == nil {
+		behavior = &simv1alpha1.SimulatorBehavior{Mode: "random", Model: "random"}
+	}
 	args := []string{
-		"--model", "random",
-		"--mode", "random",
+		"--model", behavior.Model,
+		"--mode", behavior.Mode,
+	}
+	for _, name := range behavior.ServedModelNames {
+		args = append(args, "--served-model-name", name)
 	}
 	if verbosity > 0 {
 		args = append(args, "--v", fmt.Sprintf("%d", verbosity))
 	}
 	args = append(args, "--port", fmt.Sprintf("%d", port))
+
+	millis := func(flag string, d *metav1.Duration) {
+		if d != nil {
+			args = append(args, flag, fmt.Sprintf("%d", d.Milliseconds()))
+		}
+	}
+	millis("--time-to-first-token", behavior.TimeToFirstToken)
+	millis("--time-to-first-token-std-dev", behavior.TimeToFirstTokenJitter)
+	millis("--inter-token-latency", behavior.InterTokenLatency)
+	millis("--inter-token-latency-std-dev", behavior.InterTokenLatencyJitter)
+	if behavior.KVCacheSize != nil {
+		args = append(args, "--kv-cache-size", fmt.Sprintf("%d", *behavior.KVCacheSize))
+	}
+	if behavior.MaxNumSeqs != nil {
+		args = append(args, "--max-num-seqs", fmt.Sprintf("%d", *behavior.MaxNumSeqs))
+	}
+	if behavior.FailureInjectionRate != nil {
+		args = append(args, "--failure-injection-rate", fmt.Sprintf("%d", *behavior.FailureInjectionRate))
+	}
+	for _, failure := range behavior.FailureTypes {
+		args = append(args, "--failure-types", string(failure))
+	}
+
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
 	return args
 }
 
+// simulatorEnv passes the pod identity to the simulator, which reports the
+// pod that served a request in the x-inference-pod response header
+func simulatorEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "metadata.name",
+				},
+			},
+		},
+		{
+			Name: "POD_NAMESPACE",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "metadata.namespace",
+				},
+			},
+		},
+	}
+}
+
 // SimulatorDeploymentReconciler reconciles a SimulatorDeployment object
 type SimulatorDeploymentReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme     *runtime.Scheme
+	RESTMapper meta.RESTMapper
 }
 
 //+kubebuilder:rbac:groups=sim.llm-d.io,resources=simulatordeployments,verbs=get;list;watch;create;update;patch;delete
@@ -51,8 +114,13 @@ type SimulatorDeploymentReconciler struct {
 //+kubebuilder:rbac:groups=sim.llm-d.io,resources=simulatordeployments/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=configmaps;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.istio.io,resources=destinationrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=inference.networking.k8s.io,resources=inferencepools,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=inference.networking.k8s.io,resources=inferenceobjectives,verbs=get;list;watch
+//+kubebuilder:rbac:groups=inference.networking.x-k8s.io,resources=inferencepools;inferenceobjectives,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *SimulatorDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -70,12 +138,18 @@ func (r *SimulatorDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, err
 	}
 
-	// Set defaults
-	r.setDefaults(simDep)
+	// Stages and pools inherit their unset settings before the names are resolved
+	simDep.Default()
+
+	names, err := r.resolveNames(ctx, simDep)
+	if err != nil {
+		logger.Error(err, "Failed to resolve object names")
+		return ctrl.Result{}, err
+	}
 
 	// Reconcile EPP if enabled
 	if simDep.Spec.EPP != nil && simDep.Spec.EPP.Enabled {
-		if err := r.reconcileEPP(ctx, simDep); err != nil {
+		if err := r.reconcileEPP(ctx, simDep, names); err != nil {
 			logger.Error(err, "Failed to reconcile EPP")
 			return ctrl.Result{}, err
 		}
@@ -83,7 +157,7 @@ func (r *SimulatorDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	// Reconcile Inference Gateways if enabled
 	if simDep.Spec.InferenceGateway != nil && simDep.Spec.InferenceGateway.Enabled {
-		if err := r.reconcileInferenceGateways(ctx, simDep); err != nil {
+		if err := r.reconcileInferenceGateways(ctx, simDep, names); err != nil {
 			logger.Error(err, "Failed to reconcile Inference Gateways")
 			return ctrl.Result{}, err
 		}
@@ -91,7 +165,7 @@ func (r *SimulatorDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	// Reconcile Prefill stage if enabled
 	if simDep.Spec.Prefill != nil && simDep.Spec.Prefill.Enabled {
-		if err := r.reconcilePrefillStage(ctx, simDep); err != nil {
+		if err := r.reconcilePrefillStage(ctx, simDep, names); err != nil {
 			logger.Error(err, "Failed to reconcile Prefill stage")
 			return ctrl.Result{}, err
 		}
@@ -99,7 +173,7 @@ func (r *SimulatorDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	// Reconcile Decode stage if enabled
 	if simDep.Spec.Decode != nil && simDep.Spec.Decode.Enabled {
-		if err := r.reconcileDecodeStage(ctx, simDep); err != nil {
+		if err := r.reconcileDecodeStage(ctx, simDep, names); err != nil {
 			logger.Error(err, "Failed to reconcile Decode stage")
 			return ctrl.Result{}, err
 		}
@@ -114,7 +188,7 @@ func (r *SimulatorDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.
 		}
 
 		// Reconcile Service
-		if err := r.reconcileService(ctx, simDep); err != nil {
+		if err := r.reconcileService(ctx, simDep, names); err != nil {
 			logger.Error(err, "Failed to reconcile Service")
 			return ctrl.Result{}, err
 		}
@@ -122,14 +196,20 @@ func (r *SimulatorDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	// Reconcile DestinationRule if load balancing is enabled
 	if simDep.Spec.LoadBalancing != nil && simDep.Spec.LoadBalancing.Enabled {
-		if err := r.reconcileDestinationRule(ctx, simDep); err != nil {
+		if err := r.reconcileDestinationRule(ctx, simDep, names); err != nil {
 			logger.Error(err, "Failed to reconcile DestinationRule")
 			return ctrl.Result{}, err
 		}
 	}
 
+	// Remove what was created under the previous names
+	if err := r.pruneRenamedObjects(ctx, simDep, simulatorNames{prefix: simDep.Status.NamePrefix}, names); err != nil {
+		logger.Error(err, "Failed to remove renamed objects")
+		return ctrl.Result{}, err
+	}
+
 	// Update status
-	if err := r.updateStatus(ctx, simDep); err != nil {
+	if err := r.updateStatus(ctx, simDep, names); err != nil {
 		logger.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
 	}
@@ -137,27 +217,6 @@ func (r *SimulatorDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.
 	return ctrl.Result{}, nil
 }
 
-func (r *SimulatorDeploymentReconciler) setDefaults(simDep *simv1alpha1.SimulatorDeployment) {
-	if simDep.Spec.Replicas == 0 {
-		simDep.Spec.Replicas = 2
-	}
-	if simDep.Spec.Image == "" {
-		simDep.Spec.Image = "docker.io/library/llm-d-simulator:local"
-	}
-	if simDep.Spec.Service.Name == "" {
-		simDep.Spec.Service.Name = "gaie-inference-scheduling-proxy"
-	}
-	if simDep.Spec.Service.Port == 0 {
-		simDep.Spec.Service.Port = 8200
-	}
-	if simDep.Spec.Service.Type == "" {
-		simDep.Spec.Service.Type = corev1.ServiceTypeClusterIP
-	}
-	if simDep.Spec.LogVerbosity == 0 {
-		simDep.Spec.LogVerbosity = 5
-	}
-}
-
 func (r *SimulatorDeploymentReconciler) reconcileDeployment(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -191,7 +250,8 @@ func (r *SimulatorDeploymentReconciler) reconcileDeployment(ctx context.Context,
 							Name:            "decode",
 							Image:           simDep.Spec.Image,
 							ImagePullPolicy: corev1.PullNever, // Use local image only
-							Args:            r.buildSimulatorArgs(simDep.Spec.LogVerbosity, simDep.Spec.Service.Port, nil),
+							Args:            r.buildSimulatorArgs(simDep.Spec.LogVerbosity, simDep.Spec.Service.Port, nil, nil),
+							Env:             simulatorEnv(),
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "http",
@@ -207,33 +267,13 @@ func (r *SimulatorDeploymentReconciler) reconcileDeployment(ctx context.Context,
 		},
 	}
 
-	// Set SimulatorDeployment instance as the owner
-	if err := controllerutil.SetControllerReference(simDep, deployment, r.Scheme); err != nil {
-		return err
-	}
-
-	// Check if deployment exists
-	found := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, deployment)
-	} else if err != nil {
-		return err
-	}
-
-	// Update if needed
-	if found.Spec.Replicas == nil || *found.Spec.Replicas != simDep.Spec.Replicas {
-		found.Spec.Replicas = &simDep.Spec.Replicas
-		return r.Update(ctx, found)
-	}
-
-	return nil
+	return r.applyDeployment(ctx, simDep, deployment)
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileService(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
+func (r *SimulatorDeploymentReconciler) reconcileService(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      simDep.Spec.Service.Name,
+			Name:      serviceName(simDep, names),
 			Namespace: simDep.Namespace,
 			Labels: map[string]string{
 				"app.kubernetes.io/name": simDep.Name,
@@ -256,130 +296,377 @@ func (r *SimulatorDeploymentReconciler) reconcileService(ctx context.Context, si
 		},
 	}
 
-	// Set SimulatorDeployment instance as the owner
-	if err := controllerutil.SetControllerReference(simDep, service, r.Scheme); err != nil {
-		return err
+	return r.applyService(ctx, simDep, service)
+}
+
+func (r *SimulatorDeploymentReconciler) reconcileDestinationRule(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
+	gvk := destinationRuleGVK
+	if !r.gvkSupported(gvk) {
+		return nil
 	}
 
-	// Check if service exists
-	found := &corev1.Service{}
-	err := r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, service)
-	} else if err != nil {
-		return err
+	// One rule per simulator Service: the stage Services in prefill/decode mode, the legacy Service otherwise
+	var serviceNames []string
+	if simDep.Spec.Prefill != nil && simDep.Spec.Prefill.Enabled {
+		serviceNames = append(serviceNames, names.stage("prefill"))
+	}
+	if simDep.Spec.Decode != nil && simDep.Spec.Decode.Enabled {
+		serviceNames = append(serviceNames, names.stage("decode"))
+	}
+	if len(serviceNames) == 0 {
+		serviceNames = append(serviceNames, serviceName(simDep, names))
 	}
 
+	lb := simDep.Spec.LoadBalancing
+	for _, serviceName := range serviceNames {
+		dr := &unstructured.Unstructured{}
+		dr.SetGroupVersionKind(gvk)
+		dr.SetName(fmt.Sprintf("%s-lb", serviceName))
+		dr.SetNamespace(simDep.Namespace)
+		if err := controllerutil.SetControllerReference(simDep, dr, r.Scheme); err != nil {
+			return err
+		}
+
+		host := fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, simDep.Namespace)
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, dr, func() error {
+			dr.SetLabels(map[string]string{
+				"app.kubernetes.io/name": simDep.Name,
+			})
+
+			trafficPolicy := map[string]interface{}{
+				"loadBalancer": map[string]interface{}{
+					"simple": lb.Algorithm,
+				},
+			}
+			if lb.ConnectionPool != nil {
+				trafficPolicy["connectionPool"] = map[string]interface{}{
+					"http": map[string]interface{}{
+						"http1MaxPendingRequests":  int64(lb.ConnectionPool.HTTP1MaxPendingRequests),
+						"maxRequestsPerConnection": int64(lb.ConnectionPool.MaxRequestsPerConnection),
+					},
+				}
+			}
+			spec := map[string]interface{}{
+				"host":          host,
+				"trafficPolicy": trafficPolicy,
+			}
+			return unstructured.SetNestedField(dr.Object, spec, "spec")
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileDestinationRule(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
-	// This would create an Istio DestinationRule
-	// For now, we'll skip the actual implementation as it requires Istio CRDs
-	// In a real implementation, you would use unstructured.Unstructured to create the DestinationRule
-	return nil
+func (r *SimulatorDeploymentReconciler) gvkSupported(gvk schema.GroupVersionKind) bool {
+	if r.RESTMapper == nil {
+		return true
+	}
+	_, err := r.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	return err == nil
 }
 
-func (r *SimulatorDeploymentReconciler) updateStatus(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
-	// Determine which deployment to check based on configuration
-	var deploymentName string
+func (r *SimulatorDeploymentReconciler) updateStatus(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
+	// Refetch the latest SimulatorDeployment to avoid conflict
+	latestSimDep := &simv1alpha1.SimulatorDeployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: simDep.Name, Namespace: simDep.Namespace}, latestSimDep); err != nil {
+		return err
+	}
+	status := &latestSimDep.Status
+	status.NamePrefix = names.prefix
+
+	var err error
+	stagesEnabled := false
+	previousPrefill := status.Prefill
+	status.Prefill = nil
+	if simDep.Spec.Prefill != nil && simDep.Spec.Prefill.Enabled {
+		stagesEnabled = true
+		if status.Prefill, err = r.stageStatus(ctx, simDep, names, "prefill", simDep.Spec.Prefill, previousPrefill); err != nil {
+			return err
+		}
+	}
+	previousDecode := status.Decode
+	status.Decode = nil
 	if simDep.Spec.Decode != nil && simDep.Spec.Decode.Enabled {
-		// New stage-based deployment
-		deploymentName = "ms-sim-llm-d-modelservice-decode"
-	} else {
+		stagesEnabled = true
+		if status.Decode, err = r.stageStatus(ctx, simDep, names, "decode", simDep.Spec.Decode, previousDecode); err != nil {
+			return err
+		}
+	} else if !stagesEnabled {
 		// Legacy deployment
-		deploymentName = fmt.Sprintf("ms-sim-%s-decode", simDep.Name)
+		name := fmt.Sprintf("ms-sim-%s-decode", simDep.Name)
+		if status.Decode, err = r.componentStatus(ctx, simDep.Namespace, name, serviceName(simDep, names), previousDecode); err != nil {
+			return err
+		}
 	}
 
-	// Get the deployment to check status
-	deployment := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{
-		Name:      deploymentName,
-		Namespace: simDep.Namespace,
-	}, deployment)
-	if err != nil {
-		// If deployment not found, it might not be created yet - don't fail
-		if errors.IsNotFound(err) {
-			return nil
+	previousEPP := status.EPP
+	status.EPP = nil
+	if simDep.Spec.EPP != nil && simDep.Spec.EPP.Enabled {
+		if status.EPP, err = r.componentStatus(ctx, simDep.Namespace, names.epp(), names.epp(), previousEPP); err != nil {
+			return err
+		}
+	}
+
+	previousGateway, previousIstioGateway := status.Gateway, status.IstioGateway
+	status.Gateway = nil
+	status.IstioGateway = nil
+	if gw := simDep.Spec.InferenceGateway; gw != nil && gw.Enabled {
+		if gw.Standard != nil && gw.Standard.Enabled {
+			if status.Gateway, err = r.componentStatus(ctx, simDep.Namespace, names.standardGateway(), names.standardGateway(), previousGateway); err != nil {
+				return err
+			}
+		}
+		if gw.Istio != nil && gw.Istio.Enabled {
+			if status.IstioGateway, err = r.componentStatus(ctx, simDep.Namespace, names.istioGateway(), names.istioGateway(), previousIstioGateway); err != nil {
+				return err
+			}
 		}
-		return err
 	}
 
-	// Calculate new status
-	newReplicas := deployment.Status.Replicas
-	newReadyReplicas := deployment.Status.ReadyReplicas
+	// Aggregate the per-component view into the top-level fields
+	status.Replicas = 0
+	status.ReadyReplicas = 0
+	if status.Decode != nil {
+		status.Replicas = status.Decode.Replicas
+		status.ReadyReplicas = status.Decode.ReadyReplicas
+	}
+	status.Endpoints = nil
+	status.GatewayURL = ""
+	ready := true
+	var notReady []string
+	for _, component := range []struct {
+		name   string
+		status *simv1alpha1.ComponentStatus
+	}{
+		{"prefill", status.Prefill},
+		{"decode", status.Decode},
+		{"epp", status.EPP},
+		{"gateway", status.Gateway},
+		{"istioGateway", status.IstioGateway},
+	} {
+		if component.status == nil {
+			continue
+		}
+		status.Endpoints = append(status.Endpoints, component.status.Endpoints...)
+		if status.GatewayURL == "" && component.status.URL != "" {
+			status.GatewayURL = component.status.URL
+		}
+		if !meta.IsStatusConditionTrue(component.status.Conditions, "Available") {
+			ready = false
+			notReady = append(notReady, component.name)
+		}
+	}
 
-	// Create condition
 	condition := metav1.Condition{
-		Type:               "Ready",
-		Status:             metav1.ConditionTrue,
-		Reason:             "DeploymentReady",
-		Message:            "Simulator deployment is ready",
-		LastTransitionTime: metav1.Now(),
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "DeploymentReady",
+		Message: "Simulator deployment is ready",
 	}
-	if deployment.Status.ReadyReplicas != deployment.Status.Replicas {
+	if !ready {
 		condition.Status = metav1.ConditionFalse
 		condition.Reason = "DeploymentNotReady"
-		condition.Message = "Waiting for pods to be ready"
+		condition.Message = fmt.Sprintf("Waiting for components to be ready: %s", strings.Join(notReady, ", "))
 	}
+	meta.SetStatusCondition(&status.Conditions, condition)
 
-	// Refetch the latest SimulatorDeployment to avoid conflict
-	latestSimDep := &simv1alpha1.SimulatorDeployment{}
-	if err := r.Get(ctx, types.NamespacedName{Name: simDep.Name, Namespace: simDep.Namespace}, latestSimDep); err != nil {
-		return err
+	return r.Status().Update(ctx, latestSimDep)
+}
+
+// componentStatus reports replicas, conditions and Service endpoints of one Deployment-backed component
+func (r *SimulatorDeploymentReconciler) componentStatus(ctx context.Context, namespace, deploymentName, serviceName string, previous *simv1alpha1.ComponentStatus) (*simv1alpha1.ComponentStatus, error) {
+	status := &simv1alpha1.ComponentStatus{DeploymentName: deploymentName}
+	if previous != nil {
+		status.Conditions = previous.Conditions
 	}
 
-	// Update status on the latest object
-	latestSimDep.Status.Replicas = newReplicas
-	latestSimDep.Status.ReadyReplicas = newReadyReplicas
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, deployment)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if errors.IsNotFound(err) {
+		// It might not be created yet - don't fail
+		for _, conditionType := range []string{"Available", "Progressing", "Degraded"} {
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:    conditionType,
+				Status:  metav1.ConditionUnknown,
+				Reason:  "DeploymentNotFound",
+				Message: fmt.Sprintf("Deployment %s not found", deploymentName),
+			})
+		}
+	} else {
+		if deployment.Spec.Replicas != nil {
+			status.Replicas = *deployment.Spec.Replicas
+		}
+		status.ReadyReplicas = deployment.Status.ReadyReplicas
+		status.Conditions = deploymentConditions(deployment, status.Conditions)
+	}
 
-	// Update or append condition
-	found := false
-	for i, c := range latestSimDep.Status.Conditions {
-		if c.Type == "Ready" {
-			latestSimDep.Status.Conditions[i] = condition
-			found = true
-			break
+	service := &corev1.Service{}
+	err = r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: namespace}, service)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		host := fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, service.Namespace)
+		for _, port := range service.Spec.Ports {
+			status.Endpoints = append(status.Endpoints, fmt.Sprintf("%s:%d", host, port.Port))
+		}
+		if service.Labels["llm-d.ai/component"] == "gateway" {
+			status.URL = serviceURL(service)
 		}
 	}
-	if !found {
-		latestSimDep.Status.Conditions = append(latestSimDep.Status.Conditions, condition)
+	return status, nil
+}
+
+// stageStatus reports a stage. A stage with pools reports each pool, totals
+// their replicas and takes each condition from the least healthy pool.
+func (r *SimulatorDeploymentReconciler) stageStatus(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames, stage string, config *simv1alpha1.StageConfig, previous *simv1alpha1.ComponentStatus) (*simv1alpha1.ComponentStatus, error) {
+	serviceName := names.stage(stage)
+	if len(config.Pools) == 0 {
+		status, err := r.componentStatus(ctx, simDep.Namespace, serviceName, serviceName, previous)
+		if err != nil {
+			return nil, err
+		}
+		status.Behavior = config.Behavior.DeepCopy()
+		return status, nil
 	}
 
-	return r.Status().Update(ctx, latestSimDep)
+	status := &simv1alpha1.ComponentStatus{Behavior: config.Behavior.DeepCopy()}
+	if previous != nil {
+		status.Conditions = previous.Conditions
+	}
+	var pools []*simv1alpha1.ComponentStatus
+	for _, pool := range config.Pools {
+		poolStatus, err := r.componentStatus(ctx, simDep.Namespace, names.stagePool(stage, pool.Name), serviceName, nil)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, poolStatus)
+		status.Replicas += poolStatus.Replicas
+		status.ReadyReplicas += poolStatus.ReadyReplicas
+		status.Endpoints = poolStatus.Endpoints
+		status.Pools = append(status.Pools, simv1alpha1.PoolStatus{
+			Name:           pool.Name,
+			DeploymentName: poolStatus.DeploymentName,
+			Replicas:       poolStatus.Replicas,
+			ReadyReplicas:  poolStatus.ReadyReplicas,
+			Behavior:       pool.Behavior.DeepCopy(),
+		})
+	}
+
+	for _, conditionType := range []string{"Available", "Progressing", "Degraded"} {
+		healthy := metav1.ConditionFalse
+		if conditionType == "Available" {
+			healthy = metav1.ConditionTrue
+		}
+		var worst *metav1.Condition
+		for i, poolStatus := range pools {
+			condition := meta.FindStatusCondition(poolStatus.Conditions, conditionType)
+			if condition == nil || (worst != nil && (worst.Status != healthy || condition.Status == healthy)) {
+				continue
+			}
+			worst = condition.DeepCopy()
+			worst.Message = fmt.Sprintf("pool %s: %s", config.Pools[i].Name, condition.Message)
+		}
+		if worst != nil {
+			meta.SetStatusCondition(&status.Conditions, *worst)
+		}
+	}
+	return status, nil
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileEPPConfigMap(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
-	// Default plugins configuration for EPP
-	pluginsConfig := `apiVersion: inference.networking.x-k8s.io/v1alpha1
-kind: EndpointPickerConfig
-plugins:
-- type: load-aware-scorer
-- type: prefix-cache-scorer
-  parameters:
-    hashBlockSize: 5
-    maxPrefixBlocksToMatch: 256
-    lruCapacityPerServer: 31250
-- type: kv-cache-utilization-scorer
-- type: decode-filter
-- type: max-score-picker
-- type: single-profile-handler
-schedulingProfiles:
-- name: default
-  plugins:
-  - pluginRef: decode-filter
-  - pluginRef: max-score-picker
-  - pluginRef: load-aware-scorer
-    weight: 1
-  - pluginRef: prefix-cache-scorer
-    weight: 2
-  - pluginRef: kv-cache-utilization-scorer
-    weight: 1
-`
+// deploymentConditions maps Deployment conditions onto Available, Progressing and Degraded
+func deploymentConditions(deployment *appsv1.Deployment, conditions []metav1.Condition) []metav1.Condition {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+
+	available := metav1.Condition{
+		Type:    "Available",
+		Status:  metav1.ConditionFalse,
+		Reason:  "ReplicasNotReady",
+		Message: fmt.Sprintf("%d/%d replicas ready", deployment.Status.ReadyReplicas, desired),
+	}
+	if deployment.Status.ReadyReplicas >= desired && deployment.Status.ObservedGeneration >= deployment.Generation {
+		available.Status = metav1.ConditionTrue
+		available.Reason = "ReplicasReady"
+	}
+
+	progressing := metav1.Condition{
+		Type:    "Progressing",
+		Status:  metav1.ConditionFalse,
+		Reason:  "RolloutComplete",
+		Message: "Deployment rollout is complete",
+	}
+	degraded := metav1.Condition{
+		Type:    "Degraded",
+		Status:  metav1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: "Deployment is healthy",
+	}
+	for _, c := range deployment.Status.Conditions {
+		switch {
+		case c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded":
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = c.Reason
+			degraded.Message = c.Message
+		case c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionTrue && c.Reason != "NewReplicaSetAvailable":
+			progressing.Status = metav1.ConditionTrue
+			progressing.Reason = c.Reason
+			progressing.Message = c.Message
+		case c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue:
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = c.Reason
+			degraded.Message = c.Message
+		}
+	}
+	if deployment.Status.UpdatedReplicas < desired || deployment.Status.ObservedGeneration < deployment.Generation {
+		progressing.Status = metav1.ConditionTrue
+		if progressing.Reason == "RolloutComplete" {
+			progressing.Reason = "RolloutInProgress"
+			progressing.Message = fmt.Sprintf("%d/%d replicas updated", deployment.Status.UpdatedReplicas, desired)
+		}
+	}
+
+	for _, c := range []metav1.Condition{available, progressing, degraded} {
+		c.ObservedGeneration = deployment.Generation
+		meta.SetStatusCondition(&conditions, c)
+	}
+	return conditions
+}
+
+// serviceURL resolves the URL of a gateway Service, preferring a load balancer address
+func serviceURL(service *corev1.Service) string {
+	if len(service.Spec.Ports) == 0 {
+		return ""
+	}
+	port := service.Spec.Ports[0].Port
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.Hostname != "" {
+			return fmt.Sprintf("http://%s:%d", ingress.Hostname, port)
+		}
+		if ingress.IP != "" {
+			return fmt.Sprintf("http://%s:%d", ingress.IP, port)
+		}
+	}
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", service.Name, service.Namespace, port)
+}
+
+// reconcileEPPConfigMap renders the plugin config into the EPP ConfigMap and returns it
+func (r *SimulatorDeploymentReconciler) reconcileEPPConfigMap(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) (string, error) {
+	pluginsConfig, err := simv1alpha1.RenderEndpointPickerConfig("default", simDep.Spec.EPP.Plugins, simDep.Spec.EPP.SchedulingProfiles)
+	if err != nil {
+		return "", err
+	}
 
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gaie-sim-epp",
+			Name:      names.epp(),
 			Namespace: simDep.Namespace,
 			Labels: map[string]string{
 				"llm-d.ai/component":     "epp",
@@ -391,46 +678,175 @@ schedulingProfiles:
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(simDep, configMap, r.Scheme); err != nil {
+	return pluginsConfig, r.applyConfigMap(ctx, simDep, configMap)
+}
+
+// reconcileEPPServiceAccount creates the ServiceAccount the EPP pods run as
+func (r *SimulatorDeploymentReconciler) reconcileEPPServiceAccount(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.epp(),
+			Namespace: simDep.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, sa, func() error {
+		if err := controllerutil.SetControllerReference(simDep, sa, r.Scheme); err != nil {
+			return err
+		}
+		sa.Labels = eppLabels(simDep)
+		return nil
+	})
+	return err
+}
+
+// reconcileEPPRBAC grants the EPP ServiceAccount read access to the pods and
+// the inference objects of the namespace, which is all the EPP needs to pick
+// endpoints from its InferencePool
+func (r *SimulatorDeploymentReconciler) reconcileEPPRBAC(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.epp(),
+			Namespace: simDep.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, role, func() error {
+		if err := controllerutil.SetControllerReference(simDep, role, r.Scheme); err != nil {
+			return err
+		}
+		role.Labels = eppLabels(simDep)
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"inference.networking.k8s.io", "inference.networking.x-k8s.io"},
+				Resources: []string{"inferencepools", "inferenceobjectives"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	found := &corev1.ConfigMap{}
-	err := r.Get(ctx, types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, configMap)
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.epp(),
+			Namespace: simDep.Namespace,
+		},
 	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, roleBinding, func() error {
+		if err := controllerutil.SetControllerReference(simDep, roleBinding, r.Scheme); err != nil {
+			return err
+		}
+		roleBinding.Labels = eppLabels(simDep)
+		roleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     names.epp(),
+		}
+		roleBinding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      names.epp(),
+				Namespace: simDep.Namespace,
+			},
+		}
+		return nil
+	})
 	return err
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
+// reconcileInferencePool creates the InferencePool the EPP picks from. It
+// selects the decode pods, or the pods of the legacy Deployment without
+// stages, and points at the EPP Service.
+func (r *SimulatorDeploymentReconciler) reconcileInferencePool(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
+	if !r.gvkSupported(inferencePoolGVK) {
+		return nil
+	}
+
+	targetPort := simDep.Spec.Service.Port
+	if simDep.Spec.Decode != nil && simDep.Spec.Decode.Enabled {
+		targetPort = simDep.Spec.Decode.Port
+	}
+
+	inferencePool := &unstructured.Unstructured{}
+	inferencePool.SetGroupVersionKind(inferencePoolGVK)
+	inferencePool.SetName(names.inferencePool())
+	inferencePool.SetNamespace(simDep.Namespace)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, inferencePool, func() error {
+		if err := controllerutil.SetControllerReference(simDep, inferencePool, r.Scheme); err != nil {
+			return err
+		}
+		inferencePool.SetLabels(map[string]string{"app.kubernetes.io/name": simDep.Name})
+		spec := map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					"llm-d.ai/role":             "decode",
+					"llm-d.ai/inferenceServing": "true",
+					"app.kubernetes.io/name":    simDep.Name,
+				},
+			},
+			"targetPorts": []interface{}{
+				map[string]interface{}{"number": int64(targetPort)},
+			},
+			"endpointPickerRef": map[string]interface{}{
+				"group": "",
+				"kind":  "Service",
+				"name":  names.epp(),
+				"port": map[string]interface{}{
+					"number": int64(simDep.Spec.EPP.Port),
+				},
+				"failureMode": "FailClose",
+			},
+		}
+		return unstructured.SetNestedField(inferencePool.Object, spec, "spec")
+	})
+	return err
+}
+
+// eppLabels are the labels of the EPP objects; the EPP pods are selected by them
+func eppLabels(simDep *simv1alpha1.SimulatorDeployment) map[string]string {
+	return map[string]string{
+		"llm-d.ai/component":     "epp",
+		"app.kubernetes.io/name": simDep.Name,
+	}
+}
+
+func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
 	eppConfig := simDep.Spec.EPP
 	if eppConfig == nil {
 		return nil
 	}
 
-	// Create ConfigMap first
-	if err := r.reconcileEPPConfigMap(ctx, simDep); err != nil {
+	// The EPP needs its identity, permissions and pool before it starts
+	if err := r.reconcileEPPServiceAccount(ctx, simDep, names); err != nil {
 		return err
 	}
-
-	// Set defaults
-	if eppConfig.Replicas == 0 {
-		eppConfig.Replicas = 1
+	if err := r.reconcileEPPRBAC(ctx, simDep, names); err != nil {
+		return err
 	}
-	if eppConfig.Image == "" {
-		eppConfig.Image = "ghcr.io/llm-d/llm-d-inference-scheduler:v0.4.0"
+	if err := r.reconcileInferencePool(ctx, simDep, names); err != nil {
+		return err
 	}
-	if eppConfig.Port == 0 {
-		eppConfig.Port = 8100
+
+	// Create ConfigMap first
+	pluginsConfig, err := r.reconcileEPPConfigMap(ctx, simDep, names)
+	if err != nil {
+		return err
 	}
+
 	grpcPort := eppConfig.Port
 	healthPort := grpcPort + 1
 
 	// Create EPP Deployment
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gaie-sim-epp",
+			Name:      names.epp(),
 			Namespace: simDep.Namespace,
 			Labels: map[string]string{
 				"llm-d.ai/component":     "epp",
@@ -453,20 +869,16 @@ func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep
 					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: "gaie-sim-epp",
+					ServiceAccountName: names.epp(),
 					Containers: []corev1.Container{
 						{
 							Name:            "epp",
 							Image:           eppConfig.Image,
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Args: func() []string {
-								verbosity := eppConfig.Verbosity
-								if verbosity == 0 {
-									verbosity = 1
-								}
 								args := []string{
 									"--pool-name",
-									"gaie-sim",
+									names.inferencePool(),
 									"--pool-namespace",
 									simDep.Namespace,
 									"--pool-group",
@@ -483,7 +895,7 @@ func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep
 									"--grpc-health-port",
 									strconv.Itoa(int(healthPort)),
 									"--v",
-									strconv.Itoa(int(verbosity)),
+									strconv.Itoa(int(eppConfig.Verbosity)),
 									"--tracing=false",
 								}
 								if len(eppConfig.Args) > 0 {
@@ -491,7 +903,7 @@ func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep
 								}
 								return args
 							}(),
-							Env: []corev1.EnvVar{
+							Env: append([]corev1.EnvVar{
 								{
 									Name: "NAMESPACE",
 									ValueFrom: &corev1.EnvVarSource{
@@ -510,7 +922,7 @@ func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep
 										},
 									},
 								},
-							},
+							}, eppConfig.Env...),
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "grpc",
@@ -566,7 +978,7 @@ func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep
 							VolumeSource: corev1.VolumeSource{
 								ConfigMap: &corev1.ConfigMapVolumeSource{
 									LocalObjectReference: corev1.LocalObjectReference{
-										Name: "gaie-sim-epp",
+										Name: names.epp(),
 									},
 								},
 							},
@@ -576,25 +988,16 @@ func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep
 			},
 		},
 	}
+	setEPPRolloutAnnotations(&deployment.Spec.Template, simDep, eppConfig.RestartPolicy, pluginsConfig)
 
-	if err := controllerutil.SetControllerReference(simDep, deployment, r.Scheme); err != nil {
-		return err
-	}
-
-	found := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		if err := r.Create(ctx, deployment); err != nil {
-			return err
-		}
-	} else if err != nil {
+	if err := r.applyDeployment(ctx, simDep, deployment); err != nil {
 		return err
 	}
 
 	// Create EPP Service
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gaie-sim-epp",
+			Name:      names.epp(),
 			Namespace: simDep.Namespace,
 			Labels: map[string]string{
 				"llm-d.ai/component":     "epp",
@@ -618,19 +1021,10 @@ func (r *SimulatorDeploymentReconciler) reconcileEPP(ctx context.Context, simDep
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(simDep, service, r.Scheme); err != nil {
-		return err
-	}
-
-	foundSvc := &corev1.Service{}
-	err = r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, foundSvc)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, service)
-	}
-	return err
+	return r.applyService(ctx, simDep, service)
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileInferenceGateways(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
+func (r *SimulatorDeploymentReconciler) reconcileInferenceGateways(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
 	gwConfig := simDep.Spec.InferenceGateway
 	if gwConfig == nil {
 		return nil
@@ -638,14 +1032,14 @@ func (r *SimulatorDeploymentReconciler) reconcileInferenceGateways(ctx context.C
 
 	// Reconcile standard gateway
 	if gwConfig.Standard != nil && gwConfig.Standard.Enabled {
-		if err := r.reconcileGatewayInstance(ctx, simDep, "infra-sim-inference-gateway", gwConfig.Standard, false); err != nil {
+		if err := r.reconcileGatewayInstance(ctx, simDep, names, names.standardGateway(), gwConfig.Standard, false); err != nil {
 			return err
 		}
 	}
 
 	// Reconcile Istio gateway
 	if gwConfig.Istio != nil && gwConfig.Istio.Enabled {
-		if err := r.reconcileGatewayInstance(ctx, simDep, "infra-sim-inference-gateway-istio", gwConfig.Istio, true); err != nil {
+		if err := r.reconcileGatewayInstance(ctx, simDep, names, names.istioGateway(), gwConfig.Istio, true); err != nil {
 			return err
 		}
 	}
@@ -653,57 +1047,12 @@ func (r *SimulatorDeploymentReconciler) reconcileInferenceGateways(ctx context.C
 	return nil
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileGatewayConfigMap(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, name string) error {
-	// Basic Envoy configuration for the gateway
-	envoyConfig := `admin:
-  address:
-    socket_address:
-      address: 0.0.0.0
-      port_value: 19000
-
-static_resources:
-  listeners:
-  - name: listener_0
-    address:
-      socket_address:
-        address: 0.0.0.0
-        port_value: 80
-    filter_chains:
-    - filters:
-      - name: envoy.filters.network.http_connection_manager
-        typed_config:
-          "@type": type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager
-          stat_prefix: ingress_http
-          route_config:
-            name: local_route
-            virtual_hosts:
-            - name: backend
-              domains: ["*"]
-              routes:
-              - match:
-                  prefix: "/"
-                route:
-                  cluster: simulator_cluster
-          http_filters:
-          - name: envoy.filters.http.router
-            typed_config:
-              "@type": type.googleapis.com/envoy.extensions.filters.http.router.v3.Router
-
-  clusters:
-  - name: simulator_cluster
-    connect_timeout: 5s
-    type: STRICT_DNS
-    lb_policy: ROUND_ROBIN
-    load_assignment:
-      cluster_name: simulator_cluster
-      endpoints:
-      - lb_endpoints:
-        - endpoint:
-            address:
-              socket_address:
-                address: ms-sim-llm-d-modelservice-decode
-                port_value: 8200
-`
+// reconcileGatewayConfigMap renders the Envoy bootstrap into the gateway ConfigMap and returns it
+func (r *SimulatorDeploymentReconciler) reconcileGatewayConfigMap(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames, name string, config *simv1alpha1.GatewayInstanceConfig) (string, error) {
+	envoyConfig, err := renderGatewayEnvoyConfig(simDep, names, config)
+	if err != nil {
+		return "", err
+	}
 
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -719,16 +1068,7 @@ static_resources:
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(simDep, configMap, r.Scheme); err != nil {
-		return err
-	}
-
-	found := &corev1.ConfigMap{}
-	err := r.Get(ctx, types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, configMap)
-	}
-	return err
+	return envoyConfig, r.applyConfigMap(ctx, simDep, configMap)
 }
 
 func (r *SimulatorDeploymentReconciler) buildGatewayContainer(config *simv1alpha1.GatewayInstanceConfig, isIstio bool) corev1.Container {
@@ -822,12 +1162,17 @@ func (r *SimulatorDeploymentReconciler) buildGatewayContainer(config *simv1alpha
 				ContainerPort: 9091,
 				Protocol:      corev1.ProtocolTCP,
 			},
+			{
+				Name:          "admin",
+				ContainerPort: config.AdminPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
 		},
 		ReadinessProbe: &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path:   "/ready",
-					Port:   intstr.FromInt(19000),
+					Port:   intstr.FromInt(int(config.AdminPort)),
 					Scheme: corev1.URISchemeHTTP,
 				},
 			},
@@ -841,7 +1186,7 @@ func (r *SimulatorDeploymentReconciler) buildGatewayContainer(config *simv1alpha
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path:   "/ready",
-					Port:   intstr.FromInt(19000),
+					Port:   intstr.FromInt(int(config.AdminPort)),
 					Scheme: corev1.URISchemeHTTP,
 				},
 			},
@@ -881,24 +1226,10 @@ func (r *SimulatorDeploymentReconciler) buildGatewayVolumes(name string, isIstio
 	}
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileGatewayInstance(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, name string, config *simv1alpha1.GatewayInstanceConfig, isIstio bool) error {
-	// Set defaults
-	if config.Replicas == 0 {
-		config.Replicas = 1
-	}
-	if config.Image == "" {
-		if isIstio {
-			config.Image = "docker.io/istio/proxyv2:1.28.1"
-		} else {
-			config.Image = "cr.kgateway.dev/kgateway-dev/envoy-wrapper:v2.1.1"
-		}
-	}
-	if config.Port == 0 {
-		config.Port = 8080
-	}
-
+func (r *SimulatorDeploymentReconciler) reconcileGatewayInstance(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames, name string, config *simv1alpha1.GatewayInstanceConfig, isIstio bool) error {
 	// Create ConfigMap for Envoy configuration
-	if err := r.reconcileGatewayConfigMap(ctx, simDep, name); err != nil {
+	envoyConfig, err := r.reconcileGatewayConfigMap(ctx, simDep, names, name, config)
+	if err != nil {
 		return err
 	}
 
@@ -912,6 +1243,9 @@ func (r *SimulatorDeploymentReconciler) reconcileGatewayInstance(ctx context.Con
 		labels["llm-d.ai/gateway-type"] = "istio"
 		// Prevent sidecar injection since we're manually defining the proxy
 		annotations["sidecar.istio.io/inject"] = "false"
+	} else {
+		// Envoy reads its bootstrap only at startup, so roll the pods when it changes
+		annotations[gatewayConfigHashAnnotation] = configHash(envoyConfig)
 	}
 
 	// Create Gateway Deployment
@@ -941,17 +1275,7 @@ func (r *SimulatorDeploymentReconciler) reconcileGatewayInstance(ctx context.Con
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(simDep, deployment, r.Scheme); err != nil {
-		return err
-	}
-
-	found := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		if err := r.Create(ctx, deployment); err != nil {
-			return err
-		}
-	} else if err != nil {
+	if err := r.applyDeployment(ctx, simDep, deployment); err != nil {
 		return err
 	}
 
@@ -969,89 +1293,155 @@ func (r *SimulatorDeploymentReconciler) reconcileGatewayInstance(ctx context.Con
 				{
 					Name:       "http",
 					Port:       config.Port,
-					TargetPort: intstr.FromInt(80),
+					TargetPort: intstr.FromInt(gatewayListenerPort),
 					Protocol:   corev1.ProtocolTCP,
 				},
 			},
 		},
 	}
-
-	if err := controllerutil.SetControllerReference(simDep, service, r.Scheme); err != nil {
-		return err
+	if !isIstio {
+		// Expose the Envoy admin stats, e.g. /stats/prometheus
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:       "admin",
+			Port:       config.AdminPort,
+			TargetPort: intstr.FromInt(int(config.AdminPort)),
+			Protocol:   corev1.ProtocolTCP,
+		})
 	}
 
-	foundSvc := &corev1.Service{}
-	err = r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, foundSvc)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, service)
-	}
-	return err
+	return r.applyService(ctx, simDep, service)
 }
 
-func (r *SimulatorDeploymentReconciler) reconcilePrefillStage(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
-	return r.reconcileStage(ctx, simDep, "prefill", simDep.Spec.Prefill)
+func (r *SimulatorDeploymentReconciler) reconcilePrefillStage(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
+	return r.reconcileStage(ctx, simDep, names, "prefill", simDep.Spec.Prefill)
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileDecodeStage(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) error {
-	return r.reconcileStage(ctx, simDep, "decode", simDep.Spec.Decode)
+func (r *SimulatorDeploymentReconciler) reconcileDecodeStage(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) error {
+	return r.reconcileStage(ctx, simDep, names, "decode", simDep.Spec.Decode)
 }
 
-func (r *SimulatorDeploymentReconciler) reconcileStage(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, stage string, config *simv1alpha1.StageConfig) error {
+func (r *SimulatorDeploymentReconciler) reconcileStage(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames, stage string, config *simv1alpha1.StageConfig) error {
 	if config == nil {
 		return nil
 	}
 
-	// Set defaults
-	if config.Replicas == 0 {
-		config.Replicas = 2
+	// The webhook rejects invalid behavior and pools, this catches them without the webhook
+	stagePath := field.NewPath("spec", stage)
+	var errs field.ErrorList
+	if config.Behavior != nil {
+		errs = append(errs, simv1alpha1.ValidateSimulatorBehavior(config.Behavior, stagePath.Child("behavior"))...)
 	}
-	if config.Image == "" {
-		config.Image = simDep.Spec.Image
-		if config.Image == "" {
-			config.Image = "docker.io/library/llm-d-simulator:local"
-		}
-	}
-	if config.Port == 0 {
-		config.Port = 8200
+	errs = append(errs, simv1alpha1.ValidateSimulatorPools(config.Pools, stagePath.Child("pools"))...)
+	if len(errs) > 0 {
+		return errs.ToAggregate()
 	}
 
-	deploymentName := fmt.Sprintf("ms-sim-llm-d-modelservice-%s", stage)
 	labels := map[string]string{
 		"llm-d.ai/role":             stage,
 		"llm-d.ai/inferenceServing": "true",
 		"app.kubernetes.io/name":    simDep.Name,
 	}
 
-	// Build container args
-	verbosity := config.LogVerbosity
-	if verbosity == 0 {
-		verbosity = simDep.Spec.LogVerbosity
+	// Create one Deployment per pool; all of them carry the stage labels
+	desired := map[string]bool{}
+	for _, pool := range stagePools(config) {
+		deployment := r.buildStageDeployment(simDep, names, stage, config, pool, labels)
+		desired[deployment.Name] = true
+		if err := r.applyDeployment(ctx, simDep, deployment); err != nil {
+			return err
+		}
+	}
+	if err := r.pruneStageDeployments(ctx, simDep, names, stage, labels, desired); err != nil {
+		return err
 	}
-	args := r.buildSimulatorArgs(verbosity, config.Port, config.Args)
 
-	// Create Stage Deployment
-	deployment := &appsv1.Deployment{
+	// Create Stage Service
+	serviceName := names.stage(stage)
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      deploymentName,
+			Name:      serviceName,
 			Namespace: simDep.Namespace,
 			Labels:    labels,
 		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: labels,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       config.Port,
+					TargetPort: intstr.FromInt(int(config.Port)),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+
+	return r.applyService(ctx, simDep, service)
+}
+
+// stagePools returns the pools of a stage; a stage without pools is a single
+// unnamed pool backed by the stage Deployment
+func stagePools(config *simv1alpha1.StageConfig) []simv1alpha1.SimulatorPool {
+	if len(config.Pools) > 0 {
+		return config.Pools
+	}
+	replicas := config.Replicas
+	return []simv1alpha1.SimulatorPool{{
+		Replicas:  &replicas,
+		Image:     config.Image,
+		Resources: config.Resources,
+		Behavior:  config.Behavior,
+	}}
+}
+
+func (r *SimulatorDeploymentReconciler) buildStageDeployment(simDep *simv1alpha1.SimulatorDeployment, names simulatorNames, stage string, config *simv1alpha1.StageConfig, pool simv1alpha1.SimulatorPool, stageLabels map[string]string) *appsv1.Deployment {
+	// Pods of a pool keep the stage labels the Service selects on; the pool
+	// label keeps the Deployment selectors of sibling pools apart
+	selector := make(map[string]string, len(stageLabels)+1)
+	for key, value := range stageLabels {
+		selector[key] = value
+	}
+	if pool.Name != "" {
+		selector[simv1alpha1.SimulatorPoolLabel] = pool.Name
+	}
+	podLabels := make(map[string]string, len(selector)+len(pool.Labels))
+	for key, value := range pool.Labels {
+		podLabels[key] = value
+	}
+	for key, value := range selector {
+		podLabels[key] = value
+	}
+
+	args := append(append([]string{}, config.Args...), pool.Args...)
+	// Default sets the replicas of every pool; a nil value falls back to that default
+	replicas := int32(1)
+	if pool.Replicas != nil {
+		replicas = *pool.Replicas
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.stagePool(stage, pool.Name),
+			Namespace: simDep.Namespace,
+			Labels:    selector,
+		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: &config.Replicas,
+			Replicas: &replicas,
 			Selector: &metav1.LabelSelector{
-				MatchLabels: labels,
+				MatchLabels: selector,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels: podLabels,
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
 						{
 							Name:            stage,
-							Image:           config.Image,
+							Image:           pool.Image,
 							ImagePullPolicy: corev1.PullNever,
-							Args:            args,
+							Args:            r.buildSimulatorArgs(config.LogVerbosity, config.Port, pool.Behavior, args),
+							Env:             simulatorEnv(),
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "http",
@@ -1059,75 +1449,121 @@ func (r *SimulatorDeploymentReconciler) reconcileStage(ctx context.Context, simD
 									Protocol:      corev1.ProtocolTCP,
 								},
 							},
-							Resources: config.Resources,
+							Resources: pool.Resources,
 						},
 					},
 				},
 			},
 		},
 	}
+}
 
-	if err := controllerutil.SetControllerReference(simDep, deployment, r.Scheme); err != nil {
+// pruneStageDeployments deletes the Deployments of a stage that no pool asks
+// for anymore, e.g. the stage Deployment once pools are introduced
+func (r *SimulatorDeploymentReconciler) pruneStageDeployments(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, names simulatorNames, stage string, stageLabels map[string]string, desired map[string]bool) error {
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(simDep.Namespace), client.MatchingLabels(stageLabels)); err != nil {
 		return err
 	}
-
-	found := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		if err := r.Create(ctx, deployment); err != nil {
-			return err
+	prefix := names.stage(stage)
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if desired[deployment.Name] || !metav1.IsControlledBy(deployment, simDep) ||
+			(deployment.Name != prefix && !strings.HasPrefix(deployment.Name, prefix+"-")) {
+			continue
 		}
-	} else if err != nil {
-		return err
-	} else {
-		// Update if replicas changed
-		if found.Spec.Replicas == nil || *found.Spec.Replicas != config.Replicas {
-			found.Spec.Replicas = &config.Replicas
-			if err := r.Update(ctx, found); err != nil {
-				return err
-			}
+		if err := r.Delete(ctx, deployment); err != nil && !errors.IsNotFound(err) {
+			return err
 		}
 	}
+	return nil
+}
 
-	// Create Stage Service
-	serviceName := fmt.Sprintf("ms-sim-llm-d-modelservice-%s", stage)
-	service := &corev1.Service{
+// applyDeployment creates desired or updates the live Deployment so that manual
+// edits are reverted and spec changes roll the pods.
+func (r *SimulatorDeploymentReconciler) applyDeployment(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, desired *appsv1.Deployment) error {
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      serviceName,
-			Namespace: simDep.Namespace,
-			Labels:    labels,
-		},
-		Spec: corev1.ServiceSpec{
-			Type:     corev1.ServiceTypeClusterIP,
-			Selector: labels,
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "http",
-					Port:       config.Port,
-					TargetPort: intstr.FromInt(int(config.Port)),
-					Protocol:   corev1.ProtocolTCP,
-				},
-			},
+			Name:      desired.Name,
+			Namespace: desired.Namespace,
 		},
 	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+		if err := controllerutil.SetControllerReference(simDep, deployment, r.Scheme); err != nil {
+			return err
+		}
+		deployment.Labels = desired.Labels
+		deployment.Spec.Replicas = desired.Spec.Replicas
+		deployment.Spec.Selector = desired.Spec.Selector
+		template := *desired.Spec.Template.DeepCopy()
+		preserveRolloutAnnotations(&deployment.Spec.Template, &template)
+		deployment.Spec.Template = template
+		return nil
+	})
+	return err
+}
 
-	if err := controllerutil.SetControllerReference(simDep, service, r.Scheme); err != nil {
-		return err
+// applyService creates desired or updates the live Service to match it
+func (r *SimulatorDeploymentReconciler) applyService(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, desired *corev1.Service) error {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      desired.Name,
+			Namespace: desired.Namespace,
+		},
 	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		if err := controllerutil.SetControllerReference(simDep, service, r.Scheme); err != nil {
+			return err
+		}
+		service.Labels = desired.Labels
+		service.Spec.Type = desired.Spec.Type
+		service.Spec.Selector = desired.Spec.Selector
+		service.Spec.Ports = desired.Spec.Ports
+		return nil
+	})
+	return err
+}
 
-	foundSvc := &corev1.Service{}
-	err = r.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, foundSvc)
-	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, service)
+// applyConfigMap creates desired or updates the live ConfigMap to match it
+func (r *SimulatorDeploymentReconciler) applyConfigMap(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, desired *corev1.ConfigMap) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      desired.Name,
+			Namespace: desired.Namespace,
+		},
 	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if err := controllerutil.SetControllerReference(simDep, configMap, r.Scheme); err != nil {
+			return err
+		}
+		configMap.Labels = desired.Labels
+		configMap.Data = desired.Data
+		return nil
+	})
 	return err
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SimulatorDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.RESTMapper == nil {
+		r.RESTMapper = mgr.GetRESTMapper()
+	}
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&simv1alpha1.SimulatorDeployment{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
-		Complete(r)
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{})
+
+	// DestinationRules and InferencePools can only be watched when their CRDs exist
+	for _, gvk := range []schema.GroupVersionKind{destinationRuleGVK, inferencePoolGVK} {
+		if r.gvkSupported(gvk) {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			b = b.Owns(obj)
+		}
+	}
+	return b.Complete(r)
 }
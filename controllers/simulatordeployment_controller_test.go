@@ -0,0 +1,537 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// newSimulatorDeployment returns a SimulatorDeployment with EPP, both
+// gateways, both stages and load balancing enabled, like the full sample
+func newSimulatorDeployment(namespace string) *simv1alpha1.SimulatorDeployment {
+	return &simv1alpha1.SimulatorDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm-sim-full", Namespace: namespace, UID: "simdep-uid"},
+		Spec: simv1alpha1.SimulatorDeploymentSpec{
+			EPP: &simv1alpha1.EPPConfig{Enabled: true, Port: 8100},
+			InferenceGateway: &simv1alpha1.InferenceGatewayConfig{
+				Enabled:  true,
+				Standard: &simv1alpha1.GatewayInstanceConfig{Enabled: true},
+				Istio:    &simv1alpha1.GatewayInstanceConfig{Enabled: true},
+			},
+			Prefill: &simv1alpha1.StageConfig{Enabled: true, Replicas: 2},
+			Decode:  &simv1alpha1.StageConfig{Enabled: true, Replicas: 2, LogVerbosity: 5},
+			LoadBalancing: &simv1alpha1.LoadBalancingConfig{
+				Enabled:   true,
+				Algorithm: "ROUND_ROBIN",
+			},
+		},
+	}
+}
+
+func newSimulatorDeploymentReconciler(scheme *runtime.Scheme, c client.Client) *SimulatorDeploymentReconciler {
+	return &SimulatorDeploymentReconciler{Client: c, Scheme: scheme, RESTMapper: newRESTMapper(watchedGVKs...)}
+}
+
+func TestSimulatorDeploymentReconcileStage(t *testing.T) {
+	scheme := newScheme()
+	simDep := newSimulatorDeployment("llm-d-sim")
+	simDep.Spec.Decode.Args = []string{"--time-to-first-token", "10"}
+	simDep.Default()
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme))
+
+	ctx := context.Background()
+	if err := r.reconcileStage(ctx, simDep, simulatorNames{prefix: simDep.Name}, "decode", simDep.Spec.Decode); err != nil {
+		t.Fatalf("reconcileStage: %v", err)
+	}
+	key := types.NamespacedName{Namespace: simDep.Namespace, Name: "llm-sim-full-decode"}
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("get stage Deployment: %v", err)
+	}
+	expectGolden(t, scheme, "simulatordeployment-decode-deployment", deployment)
+	service := &corev1.Service{}
+	if err := r.Get(ctx, key, service); err != nil {
+		t.Fatalf("get stage Service: %v", err)
+	}
+	expectGolden(t, scheme, "simulatordeployment-decode-service", service)
+}
+
+func TestSimulatorDeploymentReconcileStageBehavior(t *testing.T) {
+	scheme := newScheme()
+	simDep := newSimulatorDeployment("llm-d-sim")
+	simDep.Spec.Decode.Behavior = &simv1alpha1.SimulatorBehavior{
+		Mode:                   "echo",
+		Model:                  "meta-llama/Llama-3.1-8B-Instruct",
+		ServedModelNames:       []string{"llama", "llama-8b"},
+		TimeToFirstToken:       &metav1.Duration{Duration: 200 * time.Millisecond},
+		TimeToFirstTokenJitter: &metav1.Duration{Duration: 50 * time.Millisecond},
+		InterTokenLatency:      &metav1.Duration{Duration: 20 * time.Millisecond},
+		KVCacheSize:            ptr.To[int32](2048),
+		MaxNumSeqs:             ptr.To[int32](8),
+		FailureInjectionRate:   ptr.To[int32](5),
+		FailureTypes:           []simv1alpha1.SimulatorFailureType{"rate_limit", "server_error"},
+	}
+	simDep.Spec.Decode.Args = []string{"--seed", "42"}
+	simDep.Default()
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme))
+
+	ctx := context.Background()
+	if err := r.reconcileStage(ctx, simDep, simulatorNames{prefix: simDep.Name}, "decode", simDep.Spec.Decode); err != nil {
+		t.Fatalf("reconcileStage: %v", err)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "llm-sim-full-decode"}, deployment); err != nil {
+		t.Fatalf("get stage Deployment: %v", err)
+	}
+	want := []string{
+		"--model", "meta-llama/Llama-3.1-8B-Instruct", "--mode", "echo",
+		"--served-model-name", "llama", "--served-model-name", "llama-8b",
+		"--v", "5", "--port", "8200",
+		"--time-to-first-token", "200", "--time-to-first-token-std-dev", "50", "--inter-token-latency", "20",
+		"--kv-cache-size", "2048", "--max-num-seqs", "8",
+		"--failure-injection-rate", "5", "--failure-types", "rate_limit", "--failure-types", "server_error",
+		"--seed", "42",
+	}
+	if got := deployment.Spec.Template.Spec.Containers[0].Args; !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %q\nwant   %q", got, want)
+	}
+
+	// Without the webhook an invalid behavior is refused rather than rendered
+	simDep.Spec.Decode.Behavior.InterTokenLatencyJitter = &metav1.Duration{Duration: 10 * time.Millisecond}
+	err := r.reconcileStage(ctx, simDep, simulatorNames{prefix: simDep.Name}, "decode", simDep.Spec.Decode)
+	if err == nil || !strings.Contains(err.Error(), "spec.decode.behavior.interTokenLatencyJitter") {
+		t.Errorf("reconcileStage = %v, want the jitter rejected", err)
+	}
+}
+
+func TestSimulatorDeploymentReconcileStagePools(t *testing.T) {
+	scheme := newScheme()
+	simDep := newSimulatorDeployment("llm-d-sim")
+	simDep.Spec.Decode.Behavior = &simv1alpha1.SimulatorBehavior{InterTokenLatency: &metav1.Duration{Duration: 20 * time.Millisecond}}
+	simDep.Default()
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, simDep))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	// Split decode into three fast and two slow pods
+	latest := &simv1alpha1.SimulatorDeployment{}
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatal(err)
+	}
+	latest.Spec.Decode.Pools = []simv1alpha1.SimulatorPool{
+		{Name: "fast", Replicas: ptr.To[int32](3)},
+		{
+			Name:     "slow",
+			Replicas: ptr.To[int32](2),
+			Behavior: &simv1alpha1.SimulatorBehavior{InterTokenLatency: &metav1.Duration{Duration: 80 * time.Millisecond}},
+			Args:     []string{"--seed", "7"},
+			Labels:   map[string]string{"sim.llm-d.io/speed": "slow"},
+		},
+	}
+	if err := r.Update(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(simDep.Namespace), client.MatchingLabels{"llm-d.ai/role": "decode"}); err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]*appsv1.Deployment{}
+	for i := range deployments.Items {
+		byName[deployments.Items[i].Name] = &deployments.Items[i]
+	}
+	if len(byName) != 2 {
+		t.Fatalf("decode Deployments = %v, want only the fast and slow pools", byName)
+	}
+	fast, slow := byName["llm-sim-full-decode-fast"], byName["llm-sim-full-decode-slow"]
+	if fast == nil || slow == nil {
+		t.Fatalf("decode Deployments = %v, want one per pool", byName)
+	}
+	if *fast.Spec.Replicas != 3 || *slow.Spec.Replicas != 2 {
+		t.Errorf("replicas = %d/%d, want 3/2", *fast.Spec.Replicas, *slow.Spec.Replicas)
+	}
+	service := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "llm-sim-full-decode"}, service); err != nil {
+		t.Fatal(err)
+	}
+	for _, deployment := range []*appsv1.Deployment{fast, slow} {
+		podLabels := deployment.Spec.Template.Labels
+		for key, value := range service.Spec.Selector {
+			if podLabels[key] != value {
+				t.Errorf("%s pods %v are not selected by the stage Service %v", deployment.Name, podLabels, service.Spec.Selector)
+			}
+		}
+	}
+	if got := slow.Spec.Template.Labels["sim.llm-d.io/speed"]; got != "slow" {
+		t.Errorf("slow pool labels = %v, want the pool labels", slow.Spec.Template.Labels)
+	}
+	args := func(d *appsv1.Deployment) string { return strings.Join(d.Spec.Template.Spec.Containers[0].Args, " ") }
+	if !strings.Contains(args(fast), "--inter-token-latency 20") || !strings.Contains(args(slow), "--inter-token-latency 80") ||
+		!strings.HasSuffix(args(slow), "--seed 7") {
+		t.Errorf("args fast = %q, slow = %q", args(fast), args(slow))
+	}
+
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatal(err)
+	}
+	decode := latest.Status.Decode
+	if decode == nil || decode.Replicas != 5 || len(decode.Pools) != 2 || decode.Pools[1].DeploymentName != slow.Name {
+		t.Fatalf("status.decode = %+v, want both pools totalling 5 replicas", decode)
+	}
+	if itl := decode.Pools[0].Behavior.InterTokenLatency; itl == nil || itl.Duration != 20*time.Millisecond {
+		t.Errorf("fast pool behavior = %+v, want the stage latency", decode.Pools[0].Behavior)
+	}
+
+	// Drain the slow pool; its Deployment stays at zero replicas
+	latest.Spec.Decode.Pools[1].Replicas = ptr.To[int32](0)
+	if err := r.Update(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(slow), slow); err != nil {
+		t.Fatal(err)
+	}
+	if *slow.Spec.Replicas != 0 {
+		t.Errorf("drained slow pool replicas = %d, want 0", *slow.Spec.Replicas)
+	}
+}
+
+func TestSimulatorDeploymentReconcileRevertsEdits(t *testing.T) {
+	scheme := newScheme()
+	simDep := newSimulatorDeployment("llm-d-sim")
+	simDep.Default()
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme))
+
+	ctx := context.Background()
+	if err := r.reconcileStage(ctx, simDep, simulatorNames{prefix: simDep.Name}, "decode", simDep.Spec.Decode); err != nil {
+		t.Fatalf("reconcileStage: %v", err)
+	}
+	key := types.NamespacedName{Namespace: simDep.Namespace, Name: "llm-sim-full-decode"}
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("get stage Deployment: %v", err)
+	}
+	deployment.Spec.Template.Spec.Containers[0].Image = "edited:latest"
+	deployment.Spec.Template.Annotations = map[string]string{kubectlRestartedAtAnnotation: "2024-01-01T00:00:00Z"}
+	if err := r.Update(ctx, deployment); err != nil {
+		t.Fatalf("edit stage Deployment: %v", err)
+	}
+
+	if err := r.reconcileStage(ctx, simDep, simulatorNames{prefix: simDep.Name}, "decode", simDep.Spec.Decode); err != nil {
+		t.Fatalf("reconcileStage: %v", err)
+	}
+	if err := r.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("get stage Deployment: %v", err)
+	}
+	if got := deployment.Spec.Template.Spec.Containers[0].Image; got != simv1alpha1.DefaultSimulatorImage {
+		t.Errorf("image = %q, want the edit reverted to %q", got, simv1alpha1.DefaultSimulatorImage)
+	}
+	if _, ok := deployment.Spec.Template.Annotations[kubectlRestartedAtAnnotation]; !ok {
+		t.Errorf("kubectl rollout restart annotation was dropped")
+	}
+}
+
+func TestSimulatorDeploymentReconcileGatewayConfig(t *testing.T) {
+	scheme := newScheme()
+	simDep := newSimulatorDeployment("llm-d-sim")
+	simDep.Spec.Decode.Port = 8300
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, simDep))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}
+	key := types.NamespacedName{Namespace: simDep.Namespace, Name: "llm-sim-full-inference-gateway"}
+	reconcile := func() (*corev1.ConfigMap, string) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			t.Fatalf("get gateway ConfigMap: %v", err)
+		}
+		deployment := &appsv1.Deployment{}
+		if err := r.Get(ctx, key, deployment); err != nil {
+			t.Fatalf("get gateway Deployment: %v", err)
+		}
+		return configMap, deployment.Spec.Template.Annotations[gatewayConfigHashAnnotation]
+	}
+
+	configMap, hash := reconcile()
+	expectGolden(t, scheme, "simulatordeployment-gateway-configmap", configMap)
+	if hash != configHash(configMap.Data["envoy.yaml"]) {
+		t.Errorf("gateway pod template hash = %q, want the hash of the rendered config", hash)
+	}
+	service := &corev1.Service{}
+	if err := r.Get(ctx, key, service); err != nil {
+		t.Fatal(err)
+	}
+	if len(service.Spec.Ports) != 2 || service.Spec.Ports[1].Name != "admin" || service.Spec.Ports[1].Port != 19000 {
+		t.Errorf("gateway Service ports = %+v, want http and admin", service.Spec.Ports)
+	}
+
+	// Routing through the EPP re-renders the config and rolls the gateway
+	latest := &simv1alpha1.SimulatorDeployment{}
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatal(err)
+	}
+	latest.Spec.InferenceGateway.Standard.ExtProc = &simv1alpha1.GatewayExtProcConfig{
+		Enabled:        true,
+		Timeout:        &metav1.Duration{Duration: 90 * time.Second},
+		MessageTimeout: &metav1.Duration{Duration: 500 * time.Millisecond},
+	}
+	latest.Spec.LoadBalancing.Algorithm = "LEAST_CONN"
+	latest.Spec.LoadBalancing.ConnectionPool = &simv1alpha1.ConnectionPoolConfig{HTTP1MaxPendingRequests: 64, MaxRequestsPerConnection: 100}
+	if err := r.Update(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	configMap, rolled := reconcile()
+	expectGolden(t, scheme, "simulatordeployment-gateway-configmap-ext-proc", configMap)
+	if rolled == hash {
+		t.Errorf("gateway pod template hash did not change with the config")
+	}
+}
+
+func TestSimulatorDeploymentReconcileEPPAccess(t *testing.T) {
+	scheme := newScheme()
+	simDep := newSimulatorDeployment("llm-d-sim")
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, simDep))
+
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	key := types.NamespacedName{Namespace: simDep.Namespace, Name: "llm-sim-full-epp"}
+	sa := &corev1.ServiceAccount{}
+	role := &rbacv1.Role{}
+	roleBinding := &rbacv1.RoleBinding{}
+	for _, obj := range []client.Object{sa, role, roleBinding} {
+		if err := r.Get(ctx, key, obj); err != nil {
+			t.Fatalf("get %T: %v", obj, err)
+		}
+		if !metav1.IsControlledBy(obj, simDep) {
+			t.Errorf("%T is not controlled by the SimulatorDeployment", obj)
+		}
+	}
+	// The EPP only reads; it never writes to the API
+	for _, rule := range role.Rules {
+		if !reflect.DeepEqual(rule.Verbs, []string{"get", "list", "watch"}) {
+			t.Errorf("Role grants %v on %v, want read-only", rule.Verbs, rule.Resources)
+		}
+	}
+	want := []rbacv1.Subject{{Kind: "ServiceAccount", Name: sa.Name, Namespace: simDep.Namespace}}
+	if roleBinding.RoleRef.Name != role.Name || !reflect.DeepEqual(roleBinding.Subjects, want) {
+		t.Errorf("RoleBinding binds %s to %+v", roleBinding.RoleRef.Name, roleBinding.Subjects)
+	}
+
+	pool := getUnstructured(t, r.Client, inferencePoolGVK, simDep.Namespace, "llm-sim-full")
+	expectGolden(t, scheme, "simulatordeployment-inferencepool", pool)
+}
+
+func TestEnvoyLBPolicy(t *testing.T) {
+	for algorithm, want := range map[string]string{
+		"ROUND_ROBIN": "ROUND_ROBIN", "LEAST_REQUEST": "LEAST_REQUEST", "RANDOM": "RANDOM", "LEAST_CONN": "LEAST_REQUEST",
+	} {
+		if got := envoyLBPolicy(&simv1alpha1.LoadBalancingConfig{Enabled: true, Algorithm: algorithm}); got != want {
+			t.Errorf("envoyLBPolicy(%s) = %s, want %s", algorithm, got, want)
+		}
+	}
+	if got := envoyLBPolicy(&simv1alpha1.LoadBalancingConfig{Algorithm: "RANDOM"}); got != "ROUND_ROBIN" {
+		t.Errorf("envoyLBPolicy with load balancing disabled = %s, want ROUND_ROBIN", got)
+	}
+}
+
+func TestSimulatorDeploymentReconcileDefaults(t *testing.T) {
+	scheme := newScheme()
+	simDep := &simv1alpha1.SimulatorDeployment{ObjectMeta: metav1.ObjectMeta{Name: "minimal", Namespace: "llm-d-sim"}}
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, simDep))
+
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	// Without the webhook the defaults are applied in memory only
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "ms-sim-minimal-decode"}, deployment); err != nil {
+		t.Fatalf("get legacy Deployment: %v", err)
+	}
+	if got := deployment.Spec.Template.Spec.Containers[0].Image; got != simv1alpha1.DefaultSimulatorImage {
+		t.Errorf("image = %q, want %q", got, simv1alpha1.DefaultSimulatorImage)
+	}
+	latest := &simv1alpha1.SimulatorDeployment{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(simDep), latest); err != nil {
+		t.Fatalf("get SimulatorDeployment: %v", err)
+	}
+	if latest.Spec.Image != "" {
+		t.Errorf("spec.image = %q was persisted by the reconciler", latest.Spec.Image)
+	}
+}
+
+func TestSimulatorDeploymentReconcileStatus(t *testing.T) {
+	scheme := newScheme()
+	simDep := newSimulatorDeployment("llm-d-sim")
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, simDep))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	latest := &simv1alpha1.SimulatorDeployment{}
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatalf("get SimulatorDeployment: %v", err)
+	}
+	if meta.IsStatusConditionTrue(latest.Status.Conditions, "Ready") {
+		t.Fatalf("Ready before any Deployment reported ready replicas")
+	}
+	for name, component := range map[string]*simv1alpha1.ComponentStatus{
+		"prefill": latest.Status.Prefill, "decode": latest.Status.Decode, "epp": latest.Status.EPP,
+		"gateway": latest.Status.Gateway, "istioGateway": latest.Status.IstioGateway,
+	} {
+		if component == nil {
+			t.Errorf("status.%s is missing", name)
+		}
+	}
+	if behavior := latest.Status.Decode.Behavior; behavior == nil || behavior.Mode != "random" || behavior.Model != "random" {
+		t.Errorf("status.decode.behavior = %+v, want the defaulted behavior", behavior)
+	}
+
+	// Roll out every Deployment, as the Deployment controller would
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(simDep.Namespace)); err != nil {
+		t.Fatalf("list Deployments: %v", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		deployment.Status.ObservedGeneration = deployment.Generation
+		deployment.Status.ReadyReplicas = *deployment.Spec.Replicas
+		deployment.Status.UpdatedReplicas = *deployment.Spec.Replicas
+		if err := r.Status().Update(ctx, deployment); err != nil {
+			t.Fatalf("update Deployment %s status: %v", deployment.Name, err)
+		}
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatalf("get SimulatorDeployment: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(latest.Status.Conditions, "Ready") {
+		t.Errorf("Ready = %+v, want True", meta.FindStatusCondition(latest.Status.Conditions, "Ready"))
+	}
+	if latest.Status.ReadyReplicas != 2 {
+		t.Errorf("readyReplicas = %d, want the 2 decode replicas", latest.Status.ReadyReplicas)
+	}
+	if latest.Status.GatewayURL == "" {
+		t.Errorf("gatewayURL is empty")
+	}
+}
+
+func TestDeploymentConditions(t *testing.T) {
+	replicas := int32(2)
+	tests := []struct {
+		name   string
+		status appsv1.DeploymentStatus
+		want   map[string]string
+	}{
+		{
+			name:   "rolled out",
+			status: appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 2, ObservedGeneration: 1},
+			want:   map[string]string{"Available": "ReplicasReady", "Progressing": "RolloutComplete", "Degraded": "AsExpected"},
+		},
+		{
+			name:   "rolling out",
+			status: appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 1, ObservedGeneration: 1},
+			want:   map[string]string{"Available": "ReplicasNotReady", "Progressing": "RolloutInProgress", "Degraded": "AsExpected"},
+		},
+		{
+			name: "stuck",
+			status: appsv1.DeploymentStatus{ObservedGeneration: 1, Conditions: []appsv1.DeploymentCondition{{
+				Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+			}}},
+			want: map[string]string{"Available": "ReplicasNotReady", "Degraded": "ProgressDeadlineExceeded"},
+		},
+		{
+			name:   "stale generation",
+			status: appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 2, ObservedGeneration: 0},
+			want:   map[string]string{"Available": "ReplicasNotReady", "Progressing": "RolloutInProgress"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     tt.status,
+			}
+			conditions := deploymentConditions(deployment, nil)
+			for conditionType, reason := range tt.want {
+				c := meta.FindStatusCondition(conditions, conditionType)
+				if c == nil || c.Reason != reason {
+					t.Errorf("%s = %+v, want reason %s", conditionType, c, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestSimulatorDeploymentReconcileEnvtest(t *testing.T) {
+	requireEnvtest(t)
+
+	ctx := context.Background()
+	namespace := createNamespace(t, "sim")
+	simDep := newSimulatorDeployment(namespace)
+	simDep.UID = ""
+	if err := k8sClient.Create(ctx, simDep); err != nil {
+		t.Fatalf("create SimulatorDeployment: %v", err)
+	}
+
+	r := &SimulatorDeploymentReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), RESTMapper: k8sRESTMapper}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	names := simulatorNames{prefix: simDep.Name}
+	for _, name := range []string{names.stage("prefill"), names.stage("decode"), names.epp(), names.standardGateway(), names.istioGateway()} {
+		deployment := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, deployment); err != nil {
+			t.Errorf("get Deployment %s: %v", name, err)
+		}
+	}
+	for _, stage := range []string{"prefill", "decode"} {
+		getUnstructured(t, k8sClient, destinationRuleGVK, namespace, names.stage(stage)+"-lb")
+	}
+
+	latest := &simv1alpha1.SimulatorDeployment{}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(simDep), latest); err != nil {
+		t.Fatalf("get SimulatorDeployment: %v", err)
+	}
+	if meta.FindStatusCondition(latest.Status.Conditions, "Ready") == nil {
+		t.Errorf("Ready condition was not written")
+	}
+}
@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// Fixed names of earlier releases, kept for SimulatorDeployments that already use them
+const (
+	legacyStagePrefix         = "ms-sim-llm-d-modelservice"
+	legacyEPPName             = "gaie-sim-epp"
+	legacyInferencePoolName   = "gaie-sim"
+	legacyStandardGatewayName = "infra-sim-inference-gateway"
+	legacyIstioGatewayName    = legacyStandardGatewayName + "-istio"
+	legacyServiceName         = "gaie-inference-scheduling-proxy"
+)
+
+// simulatorNames names the objects of one SimulatorDeployment after its name
+// prefix. The zero value gives the fixed names of earlier releases.
+type simulatorNames struct {
+	prefix string
+}
+
+func (n simulatorNames) stage(stage string) string {
+	if n.prefix == "" {
+		return fmt.Sprintf("%s-%s", legacyStagePrefix, stage)
+	}
+	return fmt.Sprintf("%s-%s", n.prefix, stage)
+}
+
+// stagePool names the Deployment of a pool after its stage
+func (n simulatorNames) stagePool(stage, pool string) string {
+	if pool == "" {
+		return n.stage(stage)
+	}
+	return fmt.Sprintf("%s-%s", n.stage(stage), pool)
+}
+
+// epp names the EPP Deployment, Service, ConfigMap, ServiceAccount, Role and
+// RoleBinding
+func (n simulatorNames) epp() string {
+	if n.prefix == "" {
+		return legacyEPPName
+	}
+	return n.prefix + "-epp"
+}
+
+// inferencePool is the InferencePool the EPP picks endpoints from
+func (n simulatorNames) inferencePool() string {
+	if n.prefix == "" {
+		return legacyInferencePoolName
+	}
+	return n.prefix
+}
+
+func (n simulatorNames) standardGateway() string {
+	if n.prefix == "" {
+		return legacyStandardGatewayName
+	}
+	return n.prefix + "-inference-gateway"
+}
+
+func (n simulatorNames) istioGateway() string {
+	if n.prefix == "" {
+		return legacyIstioGatewayName
+	}
+	return n.prefix + "-inference-gateway-istio"
+}
+
+// service names the Service of the single Deployment run without prefill
+// and decode stages
+func (n simulatorNames) service() string {
+	if n.prefix == "" {
+		return legacyServiceName
+	}
+	return n.prefix + "-proxy"
+}
+
+// serviceName is the Service of the single Deployment run without prefill and
+// decode stages: spec.service.name when set, the derived name otherwise
+func serviceName(simDep *simv1alpha1.SimulatorDeployment, names simulatorNames) string {
+	if simDep.Spec.Service.Name != "" {
+		return simDep.Spec.Service.Name
+	}
+	return names.service()
+}
+
+// objects lists the Deployments, Services, ConfigMaps and EPP RBAC objects
+// these names may give; pool Deployments are matched by owns
+func (n simulatorNames) objects() []client.Object {
+	var objects []client.Object
+	for _, name := range []string{n.stage("prefill"), n.stage("decode"), n.epp(), n.standardGateway(), n.istioGateway()} {
+		objects = append(objects, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		objects = append(objects, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	for _, name := range []string{n.epp(), n.standardGateway(), n.istioGateway()} {
+		objects = append(objects, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	objects = append(objects, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: n.service()}})
+	objects = append(objects,
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: n.epp()}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: n.epp()}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: n.epp()}},
+	)
+	return objects
+}
+
+// owns reports whether name is one these names give
+func (n simulatorNames) owns(name string) bool {
+	for _, stage := range []string{"prefill", "decode"} {
+		if name == n.stage(stage) || strings.HasPrefix(name, n.stage(stage)+"-") {
+			return true
+		}
+	}
+	return name == n.epp() || name == n.inferencePool() || name == n.standardGateway() || name == n.istioGateway() || name == n.service()
+}
+
+// resolveNames picks the names of the objects of simDep. Without
+// spec.namePrefix names follow the SimulatorDeployment name, except for one
+// that already controls objects under the fixed names of earlier releases:
+// those are adopted until spec.namePrefix is set.
+func (r *SimulatorDeploymentReconciler) resolveNames(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment) (simulatorNames, error) {
+	if simDep.Spec.NamePrefix != "" {
+		return simulatorNames{prefix: simDep.Spec.NamePrefix}, nil
+	}
+	if simDep.Status.NamePrefix != "" {
+		return simulatorNames{prefix: simDep.Name}, nil
+	}
+	legacy := simulatorNames{}
+	for _, name := range []string{legacy.stage("decode"), legacy.stage("prefill"), legacy.epp(), legacy.standardGateway(), legacy.istioGateway()} {
+		deployment := &appsv1.Deployment{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: name}, deployment)
+		if err != nil && !errors.IsNotFound(err) {
+			return simulatorNames{}, err
+		}
+		if err == nil && metav1.IsControlledBy(deployment, simDep) {
+			return legacy, nil
+		}
+	}
+	// Without stages only the Service had a fixed name
+	service := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: legacy.service()}, service)
+	if err != nil && !errors.IsNotFound(err) {
+		return simulatorNames{}, err
+	}
+	if err == nil && metav1.IsControlledBy(service, simDep) {
+		return legacy, nil
+	}
+	return simulatorNames{prefix: simDep.Name}, nil
+}
+
+// pruneRenamedObjects deletes what simDep created under its previous names
+// once the objects under the current names are in place
+func (r *SimulatorDeploymentReconciler) pruneRenamedObjects(ctx context.Context, simDep *simv1alpha1.SimulatorDeployment, previous, current simulatorNames) error {
+	if previous == current {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	objects := previous.objects()
+	if r.gvkSupported(destinationRuleGVK) {
+		for _, name := range []string{previous.stage("prefill"), previous.stage("decode"), previous.service()} {
+			dr := &unstructured.Unstructured{}
+			dr.SetGroupVersionKind(destinationRuleGVK)
+			dr.SetName(name + "-lb")
+			objects = append(objects, dr)
+		}
+	}
+	if r.gvkSupported(inferencePoolGVK) {
+		pool := &unstructured.Unstructured{}
+		pool.SetGroupVersionKind(inferencePoolGVK)
+		pool.SetName(previous.inferencePool())
+		objects = append(objects, pool)
+	}
+	// Pool Deployments carry the previous stage name followed by the pool name
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(simDep.Namespace), client.MatchingLabels{"app.kubernetes.io/name": simDep.Name}); err != nil {
+		return err
+	}
+	for i := range deployments.Items {
+		name := deployments.Items[i].Name
+		for _, stage := range []string{"prefill", "decode"} {
+			if strings.HasPrefix(name, previous.stage(stage)+"-") {
+				objects = append(objects, &deployments.Items[i])
+			}
+		}
+	}
+
+	for _, obj := range objects {
+		// The DestinationRules of the current stages are <stage>-lb, so owns covers them too;
+		// an explicit spec.service.name keeps its Service and DestinationRule
+		name := obj.GetName()
+		if current.owns(name) || (simDep.Spec.Service.Name != "" && strings.TrimSuffix(name, "-lb") == simDep.Spec.Service.Name) {
+			continue
+		}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: obj.GetName()}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !metav1.IsControlledBy(obj, simDep) {
+			continue
+		}
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return err
+		}
+		logger.Info("Deleting object created under a previous name", "kind", gvk.Kind, "name", obj.GetName())
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+func TestSimulatorNames(t *testing.T) {
+	tests := []struct {
+		names simulatorNames
+		want  []string
+	}{
+		{
+			names: simulatorNames{},
+			want: []string{"ms-sim-llm-d-modelservice-decode", "ms-sim-llm-d-modelservice-decode-fast", "gaie-sim-epp", "gaie-sim",
+				"infra-sim-inference-gateway", "infra-sim-inference-gateway-istio", "gaie-inference-scheduling-proxy"},
+		},
+		{
+			names: simulatorNames{prefix: "sim-a"},
+			want: []string{"sim-a-decode", "sim-a-decode-fast", "sim-a-epp", "sim-a", "sim-a-inference-gateway", "sim-a-inference-gateway-istio",
+				"sim-a-proxy"},
+		},
+	}
+	for _, tt := range tests {
+		n := tt.names
+		got := []string{n.stage("decode"), n.stagePool("decode", "fast"), n.epp(), n.inferencePool(), n.standardGateway(), n.istioGateway(), n.service()}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("names with prefix %q = %v, want %v", n.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestSimulatorDeploymentReconcileNames(t *testing.T) {
+	scheme := newScheme()
+	a := newSimulatorDeployment("llm-d-sim")
+	a.Name = "sim-a"
+	b := newSimulatorDeployment("llm-d-sim")
+	b.Name, b.UID = "sim-b", "sim-b-uid"
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, a, b))
+
+	ctx := context.Background()
+	for _, simDep := range []*simv1alpha1.SimulatorDeployment{a, b} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}); err != nil {
+			t.Fatalf("Reconcile %s: %v", simDep.Name, err)
+		}
+	}
+
+	// Both share the namespace without taking over each other's objects
+	for _, simDep := range []*simv1alpha1.SimulatorDeployment{a, b} {
+		for _, name := range []string{"-prefill", "-decode", "-epp", "-inference-gateway", "-inference-gateway-istio"} {
+			deployment := &appsv1.Deployment{}
+			if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: simDep.Name + name}, deployment); err != nil {
+				t.Fatalf("get Deployment: %v", err)
+			}
+			if !metav1.IsControlledBy(deployment, simDep) {
+				t.Errorf("Deployment %s is not controlled by %s", deployment.Name, simDep.Name)
+			}
+		}
+		latest := &simv1alpha1.SimulatorDeployment{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(simDep), latest); err != nil {
+			t.Fatal(err)
+		}
+		if latest.Status.NamePrefix != simDep.Name || latest.Status.Decode.DeploymentName != simDep.Name+"-decode" {
+			t.Errorf("status of %s = namePrefix %q, decode %q", simDep.Name, latest.Status.NamePrefix, latest.Status.Decode.DeploymentName)
+		}
+	}
+
+	// Cross-references follow the names
+	epp := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: b.Namespace, Name: "sim-b-epp"}, epp); err != nil {
+		t.Fatal(err)
+	}
+	if args := strings.Join(epp.Spec.Template.Spec.Containers[0].Args, " "); !strings.Contains(args, "--pool-name sim-b ") {
+		t.Errorf("EPP args = %q, want the sim-b InferencePool", args)
+	}
+	if sa := epp.Spec.Template.Spec.ServiceAccountName; sa != "sim-b-epp" {
+		t.Errorf("EPP ServiceAccount = %q", sa)
+	}
+	gateway := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: b.Namespace, Name: "sim-b-inference-gateway"}, gateway); err != nil {
+		t.Fatal(err)
+	}
+	if envoy := gateway.Data["envoy.yaml"]; !strings.Contains(envoy, "address: sim-b-decode\n") {
+		t.Errorf("envoy.yaml does not route to the sim-b decode Service:\n%s", envoy)
+	}
+}
+
+func TestSimulatorDeploymentReconcileLegacyNames(t *testing.T) {
+	scheme := newScheme()
+	simDep := newSimulatorDeployment("llm-d-sim")
+	// An earlier release created the decode stage under its fixed name
+	legacyDecode := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "ms-sim-llm-d-modelservice-decode",
+			Namespace:       simDep.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(simDep, simv1alpha1.GroupVersion.WithKind("SimulatorDeployment"))},
+		},
+	}
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, simDep, legacyDecode))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	for _, name := range []string{"ms-sim-llm-d-modelservice-decode", "gaie-sim-epp", "infra-sim-inference-gateway"} {
+		if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: name}, &appsv1.Deployment{}); err != nil {
+			t.Errorf("legacy Deployment %s was not adopted: %v", name, err)
+		}
+	}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "llm-sim-full-decode"}, &appsv1.Deployment{}); !errors.IsNotFound(err) {
+		t.Errorf("get llm-sim-full-decode = %v, want NotFound while the legacy names are kept", err)
+	}
+
+	// Setting a prefix moves everything to the new names and removes the old objects
+	latest := &simv1alpha1.SimulatorDeployment{}
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatal(err)
+	}
+	latest.Spec.NamePrefix = "sim"
+	if err := r.Update(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(simDep.Namespace)); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, deployment := range deployments.Items {
+		got = append(got, deployment.Name)
+	}
+	want := "sim-decode sim-epp sim-inference-gateway sim-inference-gateway-istio sim-prefill"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Deployments = %v, want %s", got, want)
+	}
+	for _, obj := range []client.Object{&corev1.Service{}, &corev1.ConfigMap{}, &corev1.ServiceAccount{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+		if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "gaie-sim-epp"}, obj); !errors.IsNotFound(err) {
+			t.Errorf("get legacy EPP %T = %v, want NotFound", obj, err)
+		}
+	}
+	legacyPool := &unstructured.Unstructured{}
+	legacyPool.SetGroupVersionKind(inferencePoolGVK)
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "gaie-sim"}, legacyPool); !errors.IsNotFound(err) {
+		t.Errorf("get legacy InferencePool = %v, want NotFound", err)
+	}
+	getUnstructured(t, r.Client, inferencePoolGVK, simDep.Namespace, "sim")
+	getUnstructured(t, r.Client, destinationRuleGVK, simDep.Namespace, "sim-decode-lb")
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatal(err)
+	}
+	if latest.Status.NamePrefix != "sim" {
+		t.Errorf("status.namePrefix = %q, want sim", latest.Status.NamePrefix)
+	}
+}
+
+// newStagelessSimulatorDeployment returns a SimulatorDeployment that runs a
+// single Deployment behind spec.service, without prefill and decode stages
+func newStagelessSimulatorDeployment(namespace, name string) *simv1alpha1.SimulatorDeployment {
+	simDep := newSimulatorDeployment(namespace)
+	simDep.Name, simDep.UID = name, types.UID(name+"-uid")
+	simDep.Spec.Prefill, simDep.Spec.Decode, simDep.Spec.EPP, simDep.Spec.InferenceGateway = nil, nil, nil, nil
+	return simDep
+}
+
+func TestSimulatorDeploymentReconcileStagelessServiceNames(t *testing.T) {
+	scheme := newScheme()
+	a := newStagelessSimulatorDeployment("llm-d-sim", "sim-a")
+	b := newStagelessSimulatorDeployment("llm-d-sim", "sim-b")
+	explicit := newStagelessSimulatorDeployment("llm-d-sim", "sim-c")
+	explicit.Spec.Service.Name = "my-proxy"
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, a, b, explicit))
+
+	ctx := context.Background()
+	want := map[*simv1alpha1.SimulatorDeployment]string{a: "sim-a-proxy", b: "sim-b-proxy", explicit: "my-proxy"}
+	for _, simDep := range []*simv1alpha1.SimulatorDeployment{a, b, explicit} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}); err != nil {
+			t.Fatalf("Reconcile %s: %v", simDep.Name, err)
+		}
+	}
+	for simDep, name := range want {
+		service := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: name}, service); err != nil {
+			t.Fatalf("get Service %s: %v", name, err)
+		}
+		if !metav1.IsControlledBy(service, simDep) {
+			t.Errorf("Service %s is not controlled by %s", name, simDep.Name)
+		}
+		getUnstructured(t, r.Client, destinationRuleGVK, simDep.Namespace, name+"-lb")
+	}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: a.Namespace, Name: "gaie-inference-scheduling-proxy"}, &corev1.Service{}); !errors.IsNotFound(err) {
+		t.Errorf("get gaie-inference-scheduling-proxy = %v, want NotFound", err)
+	}
+}
+
+func TestSimulatorDeploymentReconcileLegacyServiceName(t *testing.T) {
+	scheme := newScheme()
+	simDep := newStagelessSimulatorDeployment("llm-d-sim", "sim-a")
+	// An earlier release created the Service under its fixed name
+	legacyService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "gaie-inference-scheduling-proxy",
+			Namespace:       simDep.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(simDep, simv1alpha1.GroupVersion.WithKind("SimulatorDeployment"))},
+		},
+	}
+	r := newSimulatorDeploymentReconciler(scheme, newFakeClient(scheme, simDep, legacyService))
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(simDep)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	service := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "gaie-inference-scheduling-proxy"}, service); err != nil {
+		t.Fatalf("legacy Service was not adopted: %v", err)
+	}
+	if len(service.Spec.Ports) != 1 || service.Spec.Ports[0].Port != 8200 {
+		t.Errorf("legacy Service ports = %v, want the reconciled 8200", service.Spec.Ports)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "sim-a-proxy"}, &corev1.Service{}); !errors.IsNotFound(err) {
+		t.Errorf("get sim-a-proxy = %v, want NotFound while the legacy name is kept", err)
+	}
+
+	// Setting a prefix moves the Service and its DestinationRule to the new name
+	latest := &simv1alpha1.SimulatorDeployment{}
+	if err := r.Get(ctx, req.NamespacedName, latest); err != nil {
+		t.Fatal(err)
+	}
+	latest.Spec.NamePrefix = "sim"
+	if err := r.Update(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "sim-proxy"}, &corev1.Service{}); err != nil {
+		t.Errorf("get sim-proxy: %v", err)
+	}
+	getUnstructured(t, r.Client, destinationRuleGVK, simDep.Namespace, "sim-proxy-lb")
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "gaie-inference-scheduling-proxy"}, &corev1.Service{}); !errors.IsNotFound(err) {
+		t.Errorf("get legacy Service = %v, want NotFound", err)
+	}
+	legacyRule := &unstructured.Unstructured{}
+	legacyRule.SetGroupVersionKind(destinationRuleGVK)
+	if err := r.Get(ctx, types.NamespacedName{Namespace: simDep.Namespace, Name: "gaie-inference-scheduling-proxy-lb"}, legacyRule); !errors.IsNotFound(err) {
+		t.Errorf("get legacy DestinationRule = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/yaml"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// k8sClient and k8sRESTMapper talk to the envtest apiserver; they are nil when
+// the envtest binaries are not available and the envtest cases are skipped.
+var (
+	k8sClient     client.Client
+	k8sRESTMapper meta.RESTMapper
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		// envtest needs etcd and kube-apiserver, see `make test`
+		os.Exit(m.Run())
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "config", "crd"),
+			filepath.Join("testdata", "crds"),
+		},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start envtest: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := 1
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err == nil {
+		k8sRESTMapper, err = apiutil.NewDynamicRESTMapper(cfg, httpClient)
+	}
+	if err == nil {
+		k8sClient, err = client.New(cfg, client.Options{Scheme: newScheme(), Mapper: k8sRESTMapper})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+	} else {
+		code = m.Run()
+	}
+	if err := testEnv.Stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to stop envtest: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+func requireEnvtest(t *testing.T) {
+	t.Helper()
+	if k8sClient == nil {
+		t.Skip("KUBEBUILDER_ASSETS is not set; skipping envtest")
+	}
+}
+
+// createNamespace creates a uniquely named namespace in the envtest apiserver
+func createNamespace(t *testing.T, prefix string) string {
+	t.Helper()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: prefix + "-"}}
+	if err := k8sClient.Create(context.Background(), ns); err != nil {
+		t.Fatalf("create namespace: %v", err)
+	}
+	return ns.Name
+}
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	if err := simv1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	return scheme
+}
+
+// newRESTMapper returns a mapper that knows the given third-party kinds, so
+// that gvkSupported reports every other optional CRD as missing
+func newRESTMapper(gvks ...schema.GroupVersionKind) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range gvks {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	return mapper
+}
+
+// newFakeClient returns a fake client seeded with objs that serves the status
+// subresource of the CRDs
+func newFakeClient(scheme *runtime.Scheme, objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&simv1alpha1.SchedulerInstall{}, &simv1alpha1.SimulatorDeployment{}, &simv1alpha1.LoadTest{}).
+		Build()
+}
+
+// getUnstructured fetches an object of a third-party kind
+func getUnstructured(t *testing.T, c client.Client, gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		t.Fatalf("get %s %s/%s: %v", gvk.Kind, namespace, name, err)
+	}
+	return obj
+}
+
+// expectGolden compares obj, stripped of server-populated metadata, with
+// testdata/golden/<name>.yaml. Run `go test ./controllers -update` to accept
+// intended changes to the generated manifests.
+func expectGolden(t *testing.T, scheme *runtime.Scheme, name string, obj client.Object) {
+	t.Helper()
+	got, err := goldenYAML(scheme, obj)
+	if err != nil {
+		t.Fatalf("serialize %s: %v", name, err)
+	}
+
+	path := filepath.Join("testdata", "golden", name+".yaml")
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s (run with -update to create it): %v", path, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s does not match %s (run with -update to accept):\n--- got\n%s\n--- want\n%s", name, path, got, want)
+	}
+}
+
+func goldenYAML(scheme *runtime.Scheme, obj client.Object) ([]byte, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	u.SetResourceVersion("")
+	u.SetUID("")
+	u.SetGeneration(0)
+	u.SetManagedFields(nil)
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return yaml.Marshal(u.Object)
+}
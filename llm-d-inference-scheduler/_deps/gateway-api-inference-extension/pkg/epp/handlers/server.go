@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datalayer"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/decisions"
 	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
@@ -44,6 +45,8 @@ import (
 const maxLogBodyBytes = 512
 
 func NewStreamingServer(datastore Datastore, director Director) *StreamingServer {
+	// Start scheduling decision capture, when configured, so its endpoint is up before the first request.
+	decisions.Default()
 	return &StreamingServer{
 		director:  director,
 		datastore: datastore,
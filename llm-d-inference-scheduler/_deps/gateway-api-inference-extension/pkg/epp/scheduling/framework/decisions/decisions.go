@@ -0,0 +1,307 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package decisions captures every scheduling profile run as a structured record: the raw score each
+// scorer gave each pod, the scorer weights, the weighted totals and the pods the picker selected.
+// Records are kept in a bounded in-memory ring buffer, served as JSON over HTTP and optionally
+// appended to a JSONL file, so routing can be analyzed without parsing the "Scoring breakdown" and
+// "Scoring decision" log lines. There is no gRPC query API: the gRPC port of the EPP serves ext_proc
+// only, and plain HTTP keeps the endpoint usable from curl and jq.
+package decisions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// AddrEnv is the address the query endpoint listens on, e.g. ":9005".
+	AddrEnv = "SCHEDULING_DECISIONS_ADDR"
+	// FileEnv is a JSONL file every record is appended to; "-" writes to stdout.
+	FileEnv = "SCHEDULING_DECISIONS_FILE"
+	// CapacityEnv is the number of records kept in memory, DefaultCapacity when not set.
+	CapacityEnv = "SCHEDULING_DECISIONS_CAPACITY"
+
+	// DefaultCapacity is the default number of records kept in memory.
+	DefaultCapacity = 1000
+	// Path is the HTTP path records are served on.
+	Path = "/decisions"
+
+	// sinkBuffer is the number of records waiting for the JSONL file before new ones are dropped.
+	sinkBuffer = 1024
+)
+
+// ScorerScores is the output of one scorer in a scheduling cycle.
+type ScorerScores struct {
+	// Scorer is the typed name of the scorer, e.g. "prefix-cache-scorer/prefix-cache-scorer".
+	Scorer string `json:"scorer"`
+	// Weight is the weight of the scorer in the profile.
+	Weight int `json:"weight"`
+	// Scores are the raw scores by pod, before they are clamped to [0, 1] and weighted.
+	Scores map[string]float64 `json:"scores"`
+}
+
+// Record is one scheduling profile run. Pods are keyed by their namespaced name.
+type Record struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestId"`
+	Model     string    `json:"model"`
+	// PromptLength is the prompt length in characters, the chat messages summed up.
+	PromptLength int `json:"promptLength"`
+	// Candidates are the pods left after filtering, the ones that were scored.
+	Candidates []string       `json:"candidates"`
+	Scorers    []ScorerScores `json:"scorers"`
+	// Weighted is the sum of the clamped scores times the scorer weights, by pod.
+	Weighted map[string]float64 `json:"weighted"`
+	Picker   string             `json:"picker"`
+	Selected []string           `json:"selected"`
+}
+
+// Query selects records; empty fields match every record.
+type Query struct {
+	RequestID string
+	Model     string
+	// Pod matches records that selected the pod.
+	Pod string
+	// Since matches records at or after the time.
+	Since time.Time
+	// Limit keeps the newest matching records; 0 keeps all of them.
+	Limit int
+}
+
+func (q Query) matches(record *Record) bool {
+	if q.RequestID != "" && record.RequestID != q.RequestID {
+		return false
+	}
+	if q.Model != "" && record.Model != q.Model {
+		return false
+	}
+	if !q.Since.IsZero() && record.Time.Before(q.Since) {
+		return false
+	}
+	if q.Pod == "" {
+		return true
+	}
+	for _, pod := range record.Selected {
+		if pod == q.Pod {
+			return true
+		}
+	}
+	return false
+}
+
+// Recorder keeps the latest records in a ring buffer and optionally streams them to a JSONL sink.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+
+	sink    chan Record
+	dropped atomic.Int64
+}
+
+// NewRecorder returns a Recorder that keeps the latest capacity records.
+func NewRecorder(capacity int) *Recorder {
+	if capacity < 1 {
+		capacity = DefaultCapacity
+	}
+	return &Recorder{records: make([]Record, capacity)}
+}
+
+// Add stores a record, evicting the oldest one when the buffer is full. The record must not be
+// modified afterwards. Writing to the sink never blocks scheduling: when the sink falls behind the
+// record is only kept in memory and counted as dropped.
+func (r *Recorder) Add(record Record) {
+	r.mu.Lock()
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+
+	if r.sink != nil {
+		select {
+		case r.sink <- record:
+		default:
+			r.dropped.Add(1)
+		}
+	}
+}
+
+// Records returns the records matching the query, oldest first.
+func (r *Recorder) Records(q Query) []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ordered := r.records[:r.next]
+	if r.full {
+		ordered = append(append([]Record{}, r.records[r.next:]...), r.records[:r.next]...)
+	}
+	matched := []Record{}
+	for i := range ordered {
+		if q.matches(&ordered[i]) {
+			matched = append(matched, ordered[i])
+		}
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[len(matched)-q.Limit:]
+	}
+	return matched
+}
+
+// Dropped returns the number of records the JSONL sink could not keep up with.
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// StartSink appends every record added from now on to w as one JSON object per line, until the
+// process exits.
+func (r *Recorder) StartSink(w io.Writer) {
+	sink := make(chan Record, sinkBuffer)
+	r.sink = sink
+	go func() {
+		logger := log.Log.WithName("scheduling-decisions")
+		encoder := json.NewEncoder(w)
+		for record := range sink {
+			if err := encoder.Encode(record); err != nil {
+				logger.Error(err, "Failed to write scheduling decision", "request_id", record.RequestID)
+			}
+		}
+	}()
+}
+
+// ServeHTTP serves the records matching the requestId, model, pod, since (RFC 3339) and limit query
+// parameters as a JSON array, oldest first.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	params := req.URL.Query()
+	q := Query{RequestID: params.Get("requestId"), Model: params.Get("model"), Pod: params.Get("pod")}
+	if since := params.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("since: %v", err), http.StatusBadRequest)
+			return
+		}
+		q.Since = t
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		q.Limit = n
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Dropped-Records", strconv.FormatInt(r.Dropped(), 10))
+	if err := json.NewEncoder(w).Encode(r.Records(q)); err != nil {
+		log.Log.WithName("scheduling-decisions").Error(err, "Failed to serve scheduling decisions")
+	}
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultRecorder *Recorder
+)
+
+// Default returns the process-wide Recorder configured from AddrEnv, FileEnv and CapacityEnv, starting
+// the query endpoint and the sink on the first call. It returns nil, and nothing is captured, when
+// neither AddrEnv nor FileEnv is set or the configuration is invalid.
+func Default() *Recorder {
+	defaultOnce.Do(func() {
+		logger := log.Log.WithName("scheduling-decisions")
+		recorder, err := fromEnv()
+		if err != nil {
+			logger.Error(err, "Scheduling decision capture is disabled")
+			return
+		}
+		if recorder != nil {
+			logger.Info("Capturing scheduling decisions", "addr", os.Getenv(AddrEnv), "file", os.Getenv(FileEnv),
+				"capacity", len(recorder.records))
+		}
+		defaultRecorder = recorder
+	})
+	return defaultRecorder
+}
+
+func fromEnv() (*Recorder, error) {
+	addr, file := os.Getenv(AddrEnv), os.Getenv(FileEnv)
+	if addr == "" && file == "" {
+		return nil, nil
+	}
+	capacity := DefaultCapacity
+	if value := os.Getenv(CapacityEnv); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%s must be a positive integer, got %q", CapacityEnv, value)
+		}
+		capacity = n
+	}
+	recorder := NewRecorder(capacity)
+
+	// Listen first so a bad address disables capture instead of failing later
+	var listener net.Listener
+	if addr != "" {
+		var err error
+		if listener, err = net.Listen("tcp", addr); err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", AddrEnv, err)
+		}
+	}
+
+	switch file {
+	case "":
+	case "-":
+		recorder.StartSink(os.Stdout)
+	default:
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			if listener != nil {
+				listener.Close()
+			}
+			return nil, fmt.Errorf("failed to open %s: %w", FileEnv, err)
+		}
+		recorder.StartSink(f)
+	}
+
+	if listener != nil {
+		mux := http.NewServeMux()
+		mux.Handle(Path, recorder)
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				log.Log.WithName("scheduling-decisions").Error(err, "Scheduling decision endpoint stopped")
+			}
+		}()
+	}
+	return recorder, nil
+}
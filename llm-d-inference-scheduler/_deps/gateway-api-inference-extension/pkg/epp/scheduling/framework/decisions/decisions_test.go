@@ -0,0 +1,374 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decisions
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// record returns the i-th test record, one second after the previous one.
+func record(i int, model, selected string) Record {
+	return Record{
+		Time:      start.Add(time.Duration(i) * time.Second),
+		RequestID: fmt.Sprintf("req-%d", i),
+		Model:     model,
+		Selected:  []string{selected},
+	}
+}
+
+func requestIDs(records []Record) []string {
+	ids := []string{}
+	for _, r := range records {
+		ids = append(ids, r.RequestID)
+	}
+	return ids
+}
+
+func TestRecorderRecords(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		added    int
+		query    Query
+		want     []string
+	}{
+		{
+			name:     "empty",
+			capacity: 3,
+			want:     []string{},
+		},
+		{
+			name:     "not full",
+			capacity: 3,
+			added:    2,
+			want:     []string{"req-0", "req-1"},
+		},
+		{
+			name:     "exactly full",
+			capacity: 3,
+			added:    3,
+			want:     []string{"req-0", "req-1", "req-2"},
+		},
+		{
+			name:     "wrapped around keeps the newest, oldest first",
+			capacity: 3,
+			added:    7,
+			want:     []string{"req-4", "req-5", "req-6"},
+		},
+		{
+			name:     "limit keeps the newest",
+			capacity: 3,
+			added:    7,
+			query:    Query{Limit: 2},
+			want:     []string{"req-5", "req-6"},
+		},
+		{
+			name:     "limit above the matches",
+			capacity: 5,
+			added:    2,
+			query:    Query{Limit: 10},
+			want:     []string{"req-0", "req-1"},
+		},
+		{
+			name:     "invalid capacity uses the default",
+			capacity: 0,
+			added:    DefaultCapacity + 1,
+			query:    Query{Limit: 1},
+			want:     []string{fmt.Sprintf("req-%d", DefaultCapacity)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRecorder(tt.capacity)
+			for i := 0; i < tt.added; i++ {
+				r.Add(record(i, "m", "ns/pod"))
+			}
+			if got := requestIDs(r.Records(tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Records() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	r := NewRecorder(10)
+	r.Add(record(0, "llama", "ns/a"))
+	r.Add(record(1, "qwen", "ns/b"))
+	r.Add(record(2, "llama", "ns/b"))
+	r.Add(record(3, "llama", "ns/a"))
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{name: "request id", query: Query{RequestID: "req-2"}, want: []string{"req-2"}},
+		{name: "model", query: Query{Model: "llama"}, want: []string{"req-0", "req-2", "req-3"}},
+		{name: "selected pod", query: Query{Pod: "ns/b"}, want: []string{"req-1", "req-2"}},
+		{name: "since is inclusive", query: Query{Since: start.Add(2 * time.Second)}, want: []string{"req-2", "req-3"}},
+		{name: "combined with limit", query: Query{Model: "llama", Pod: "ns/a", Limit: 1}, want: []string{"req-3"}},
+		{name: "no match", query: Query{Pod: "ns/c"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestIDs(r.Records(tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Records(%+v) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := NewRecorder(10)
+	r.Add(record(0, "llama", "ns/a"))
+	r.Add(record(1, "qwen", "ns/b"))
+	r.Add(record(2, "llama", "ns/b"))
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		want       []string
+	}{
+		{name: "all", target: Path, wantStatus: http.StatusOK, want: []string{"req-0", "req-1", "req-2"}},
+		{name: "filters", target: Path + "?model=llama&pod=ns/b", wantStatus: http.StatusOK, want: []string{"req-2"}},
+		{name: "request id", target: Path + "?requestId=req-1", wantStatus: http.StatusOK, want: []string{"req-1"}},
+		{name: "since", target: Path + "?since=2025-06-01T12:00:01Z", wantStatus: http.StatusOK, want: []string{"req-1", "req-2"}},
+		{name: "limit", target: Path + "?limit=1", wantStatus: http.StatusOK, want: []string{"req-2"}},
+		{name: "no match is an empty array", target: Path + "?model=none", wantStatus: http.StatusOK, want: []string{}},
+		{name: "bad since", target: Path + "?since=yesterday", wantStatus: http.StatusBadRequest},
+		{name: "bad limit", target: Path + "?limit=ten", wantStatus: http.StatusBadRequest},
+		{name: "negative limit", target: Path + "?limit=-1", wantStatus: http.StatusBadRequest},
+		{name: "post", method: http.MethodPost, target: Path, wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(method, tt.target, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := w.Header().Get("X-Dropped-Records"); got != "0" {
+				t.Errorf("X-Dropped-Records = %q, want 0", got)
+			}
+			var records []Record
+			if err := json.Unmarshal(w.Body.Bytes(), &records); err != nil {
+				t.Fatalf("decode %q: %v", w.Body.String(), err)
+			}
+			if got := requestIDs(records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("records = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSinkWritesJSONLines(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	r := NewRecorder(10)
+	r.StartSink(writer)
+
+	want := record(0, "llama", "ns/a")
+	want.Scorers = []ScorerScores{{Scorer: "queue-scorer/queue-scorer", Weight: 2, Scores: map[string]float64{"ns/a": 1}}}
+	r.Add(want)
+	r.Add(record(1, "qwen", "ns/b"))
+
+	scanner := bufio.NewScanner(reader)
+	for i, wantID := range []string{"req-0", "req-1"} {
+		if !scanner.Scan() {
+			t.Fatalf("line %d: %v", i, scanner.Err())
+		}
+		var got Record
+		if err := json.Unmarshal(scanner.Bytes(), &got); err != nil {
+			t.Fatalf("line %d %q: %v", i, scanner.Text(), err)
+		}
+		if got.RequestID != wantID {
+			t.Errorf("line %d request id = %q, want %q", i, got.RequestID, wantID)
+		}
+		if i == 0 && !reflect.DeepEqual(got.Scorers, want.Scorers) {
+			t.Errorf("scorers = %+v, want %+v", got.Scorers, want.Scorers)
+		}
+	}
+}
+
+// blockingWriter holds every write until it is released; writing is signaled once the first write starts.
+type blockingWriter struct {
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	select {
+	case w.writing <- struct{}{}:
+	default:
+	}
+	<-w.release
+	return len(p), nil
+}
+
+func TestSinkDropsWhenBehind(t *testing.T) {
+	w := &blockingWriter{writing: make(chan struct{}, 1), release: make(chan struct{})}
+	r := NewRecorder(10)
+	r.StartSink(w)
+
+	// The first record holds the sink in Write, the next sinkBuffer fill the queue
+	r.Add(record(0, "m", "ns/a"))
+	<-w.writing
+	for i := 1; i <= sinkBuffer+5; i++ {
+		r.Add(record(i, "m", "ns/a"))
+	}
+	if got := r.Dropped(); got != 5 {
+		t.Errorf("Dropped() = %d, want 5", got)
+	}
+	// Dropped records are still kept in memory
+	if got := requestIDs(r.Records(Query{Limit: 1})); !reflect.DeepEqual(got, []string{fmt.Sprintf("req-%d", sinkBuffer+5)}) {
+		t.Errorf("newest record = %v", got)
+	}
+
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, Path+"?limit=1", nil))
+	if got := resp.Header().Get("X-Dropped-Records"); got != "5" {
+		t.Errorf("X-Dropped-Records = %q, want 5", got)
+	}
+	close(w.release)
+}
+
+func TestFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantErr      string
+		wantRecorder bool
+		wantCapacity int
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "capacity alone does not enable capture",
+			env:  map[string]string{CapacityEnv: "10"},
+		},
+		{
+			name:         "file",
+			env:          map[string]string{FileEnv: filepath.Join(dir, "decisions.jsonl")},
+			wantRecorder: true,
+			wantCapacity: DefaultCapacity,
+		},
+		{
+			name:         "address and capacity",
+			env:          map[string]string{AddrEnv: "127.0.0.1:0", CapacityEnv: "10"},
+			wantRecorder: true,
+			wantCapacity: 10,
+		},
+		{
+			name:    "bad capacity",
+			env:     map[string]string{AddrEnv: "127.0.0.1:0", CapacityEnv: "none"},
+			wantErr: CapacityEnv,
+		},
+		{
+			name:    "zero capacity",
+			env:     map[string]string{FileEnv: "-", CapacityEnv: "0"},
+			wantErr: CapacityEnv,
+		},
+		{
+			name:    "bad address",
+			env:     map[string]string{AddrEnv: "127.0.0.1:notaport"},
+			wantErr: AddrEnv,
+		},
+		{
+			name:    "file in a missing directory",
+			env:     map[string]string{AddrEnv: "127.0.0.1:0", FileEnv: filepath.Join(dir, "missing", "decisions.jsonl")},
+			wantErr: FileEnv,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{AddrEnv, FileEnv, CapacityEnv} {
+				t.Setenv(name, tt.env[name])
+			}
+			recorder, err := fromEnv()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one about %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fromEnv: %v", err)
+			}
+			if (recorder != nil) != tt.wantRecorder {
+				t.Fatalf("recorder = %v, want one: %v", recorder, tt.wantRecorder)
+			}
+			if recorder != nil && len(recorder.records) != tt.wantCapacity {
+				t.Errorf("capacity = %d, want %d", len(recorder.records), tt.wantCapacity)
+			}
+		})
+	}
+}
+
+func TestFromEnvFileSink(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "decisions.jsonl")
+	t.Setenv(AddrEnv, "")
+	t.Setenv(FileEnv, file)
+	t.Setenv(CapacityEnv, "")
+	recorder, err := fromEnv()
+	if err != nil {
+		t.Fatalf("fromEnv: %v", err)
+	}
+	recorder.Add(record(0, "llama", "ns/a"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read sink: %v", err)
+		}
+		if strings.HasSuffix(string(content), "\n") {
+			var got Record
+			if err := json.Unmarshal(content, &got); err != nil || got.RequestID != "req-0" {
+				t.Fatalf("sink = %q, %v", content, err)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("record not written to %s: %q", file, content)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
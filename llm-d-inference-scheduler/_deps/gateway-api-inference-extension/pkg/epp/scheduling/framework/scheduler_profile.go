@@ -26,6 +26,7 @@ import (
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/decisions"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
@@ -120,9 +121,14 @@ func (p *SchedulerProfile) Run(ctx context.Context, request *types.LLMRequest, c
 		return nil, errutil.Error{Code: errutil.Internal, Msg: "no pods available for the given request"}
 	}
 	// if we got here, there is at least one pod to score
-	weightedScorePerPod := p.runScorerPlugins(ctx, request, cycleState, pods)
+	recorder := decisionRecorder()
+	record := newDecisionRecord(recorder, request, pods)
+	weightedScorePerPod := p.runScorerPlugins(ctx, request, cycleState, pods, record)
 
-	result := p.runPickerPlugin(ctx, request, cycleState, weightedScorePerPod)
+	result := p.runPickerPlugin(ctx, request, cycleState, weightedScorePerPod, record)
+	if record != nil {
+		recorder.Add(*record)
+	}
 
 	return result, nil
 }
@@ -147,7 +153,7 @@ func (p *SchedulerProfile) runFilterPlugins(ctx context.Context, request *types.
 	return filteredPods
 }
 
-func (p *SchedulerProfile) runScorerPlugins(ctx context.Context, request *types.LLMRequest, cycleState *types.CycleState, pods []types.Pod) map[types.Pod]float64 {
+func (p *SchedulerProfile) runScorerPlugins(ctx context.Context, request *types.LLMRequest, cycleState *types.CycleState, pods []types.Pod, record *decisions.Record) map[types.Pod]float64 {
 	logger := log.FromContext(ctx)
 	logger.V(logutil.DEBUG).Info("Before running scorer plugins", "pods", pods)
 
@@ -163,26 +169,37 @@ func (p *SchedulerProfile) runScorerPlugins(ctx context.Context, request *types.
 		scores := scorer.Score(ctx, cycleState, request, pods)
 		metrics.RecordPluginProcessingLatency(ScorerExtensionPoint, scorer.TypedName().Type, scorer.TypedName().Name, time.Since(before))
 		scorePairs := make([]string, 0, len(scores))
+		var recorded map[string]float64
+		if record != nil {
+			recorded = make(map[string]float64, len(scores))
+		}
 		for pod, score := range scores { // weight is relative to the sum of weights
 			logger.V(logutil.DEBUG).Info("Calculated score", "plugin", scorer.TypedName(), "endpoint", pod.GetPod().NamespacedName, "score", score)
 			scorePairs = append(scorePairs, fmt.Sprintf("%s=%.3f", pod.GetPod().NamespacedName.String(), score))
 			weightedScorePerPod[pod] += enforceScoreRange(score) * float64(scorer.Weight())
+			if recorded != nil {
+				recorded[pod.GetPod().NamespacedName.String()] = score
+			}
 		}
 		breakdown = append(breakdown, fmt.Sprintf("%s[%s]", scorer.TypedName().String(), strings.Join(scorePairs, ", ")))
+		if record != nil {
+			record.Scorers = append(record.Scorers, decisions.ScorerScores{
+				Scorer: scorer.TypedName().String(),
+				Weight: scorer.Weight(),
+				Scores: recorded,
+			})
+		}
 		logger.V(logutil.DEBUG).Info("Completed running scorer plugin successfully", "plugin", scorer.TypedName())
 	}
 	logger.V(logutil.VERBOSE).Info("Completed running scorer plugins successfully")
-	if request != nil {
-		promptLen := 0
-		if request.Body != nil {
-			if request.Body.Completions != nil {
-				promptLen = len(request.Body.Completions.Prompt)
-			} else if request.Body.ChatCompletions != nil {
-				for _, msg := range request.Body.ChatCompletions.Messages {
-					promptLen += len(msg.Content.PlainText())
-				}
-			}
+	if record != nil {
+		record.Weighted = make(map[string]float64, len(weightedScorePerPod))
+		for pod, score := range weightedScorePerPod {
+			record.Weighted[pod.GetPod().NamespacedName.String()] = score
 		}
+	}
+	if request != nil {
+		promptLen := promptLength(request)
 		weighted := make([]string, 0, len(weightedScorePerPod))
 		for pod, score := range weightedScorePerPod {
 			weighted = append(weighted, fmt.Sprintf("%s=%.3f", pod.GetPod().NamespacedName.String(), score))
@@ -200,7 +217,7 @@ func (p *SchedulerProfile) runScorerPlugins(ctx context.Context, request *types.
 	return weightedScorePerPod
 }
 
-func (p *SchedulerProfile) runPickerPlugin(ctx context.Context, request *types.LLMRequest, cycleState *types.CycleState, weightedScorePerPod map[types.Pod]float64) *types.ProfileRunResult {
+func (p *SchedulerProfile) runPickerPlugin(ctx context.Context, request *types.LLMRequest, cycleState *types.CycleState, weightedScorePerPod map[types.Pod]float64, record *decisions.Record) *types.ProfileRunResult {
 	logger := log.FromContext(ctx)
 	scoredPods := make([]*types.ScoredPod, len(weightedScorePerPod))
 	i := 0
@@ -209,6 +226,9 @@ func (p *SchedulerProfile) runPickerPlugin(ctx context.Context, request *types.L
 		i++
 	}
 	logger.V(logutil.VERBOSE).Info("Running picker plugin", "plugin", p.picker.TypedName())
+	if record != nil {
+		record.Picker = p.picker.TypedName().String()
+	}
 	logger.V(logutil.DEBUG).Info("Candidate pods for picking", "pods-weighted-score", scoredPods)
 	before := time.Now()
 	result := p.picker.Pick(ctx, cycleState, scoredPods)
@@ -229,15 +249,10 @@ func (p *SchedulerProfile) runPickerPlugin(ctx context.Context, request *types.L
 		if request != nil {
 			requestID = request.RequestId
 			model = request.TargetModel
-			if request.Body != nil {
-				if request.Body.Completions != nil {
-					promptLen = len(request.Body.Completions.Prompt)
-				} else if request.Body.ChatCompletions != nil {
-					for _, msg := range request.Body.ChatCompletions.Messages {
-						promptLen += len(msg.Content.PlainText())
-					}
-				}
-			}
+			promptLen = promptLength(request)
+		}
+		if record != nil {
+			record.Selected = selected
 		}
 		logger.Info(
 			"Scoring decision",
@@ -253,6 +268,48 @@ func (p *SchedulerProfile) runPickerPlugin(ctx context.Context, request *types.L
 	return result
 }
 
+// decisionRecorder returns the recorder scheduling decisions are captured in, nil when capture is not
+// configured. Tests replace it.
+var decisionRecorder = decisions.Default
+
+// newDecisionRecord starts the scheduling decision record of a request with the pods left after filtering.
+// It returns nil, and nothing is captured, when there is no recorder.
+func newDecisionRecord(recorder *decisions.Recorder, request *types.LLMRequest, pods []types.Pod) *decisions.Record {
+	if recorder == nil {
+		return nil
+	}
+	record := &decisions.Record{
+		Time:       time.Now(),
+		Candidates: make([]string, 0, len(pods)),
+		Scorers:    []decisions.ScorerScores{},
+		Selected:   []string{},
+	}
+	if request != nil {
+		record.RequestID = request.RequestId
+		record.Model = request.TargetModel
+		record.PromptLength = promptLength(request)
+	}
+	for _, pod := range pods {
+		record.Candidates = append(record.Candidates, pod.GetPod().NamespacedName.String())
+	}
+	return record
+}
+
+// promptLength returns the prompt length in characters, summed over the messages of a chat completion.
+func promptLength(request *types.LLMRequest) int {
+	promptLen := 0
+	if request.Body != nil {
+		if request.Body.Completions != nil {
+			promptLen = len(request.Body.Completions.Prompt)
+		} else if request.Body.ChatCompletions != nil {
+			for _, msg := range request.Body.ChatCompletions.Messages {
+				promptLen += len(msg.Content.PlainText())
+			}
+		}
+	}
+	return promptLen
+}
+
 func enforceScoreRange(score float64) float64 {
 	if score < 0 {
 		return 0
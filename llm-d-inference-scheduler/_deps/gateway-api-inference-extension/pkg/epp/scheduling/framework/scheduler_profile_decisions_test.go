@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/plugins"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/decisions"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// withDecisionRecorder makes Run capture into recorder for the duration of the test.
+func withDecisionRecorder(t *testing.T, recorder *decisions.Recorder) {
+	previous := decisionRecorder
+	decisionRecorder = func() *decisions.Recorder { return recorder }
+	t.Cleanup(func() { decisionRecorder = previous })
+}
+
+func decisionsProfile() *SchedulerProfile {
+	keep := &testPlugin{
+		typedName: plugins.TypedName{Type: "keep", Name: "keep"},
+		FilterRes: []k8stypes.NamespacedName{{Namespace: "ns", Name: "pod1"}, {Namespace: "ns", Name: "pod2"}},
+	}
+	queue := &testPlugin{typedName: plugins.TypedName{Type: "queue", Name: "queue"}, ScoreRes: 0.5}
+	prefix := &testPlugin{typedName: plugins.TypedName{Type: "prefix", Name: "prefix"}, ScoreRes: 0.25}
+	picker := &testPlugin{
+		typedName: plugins.TypedName{Type: "max", Name: "max"},
+		PickRes:   k8stypes.NamespacedName{Namespace: "ns", Name: "pod2"},
+	}
+	return NewSchedulerProfile().
+		WithFilters(keep).
+		WithScorers(NewWeightedScorer(queue, 2), NewWeightedScorer(prefix, 4)).
+		WithPicker(picker)
+}
+
+func decisionsPods() []types.Pod {
+	pods := []types.Pod{}
+	for _, name := range []string{"pod1", "pod2", "pod3"} {
+		pods = append(pods, &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "ns", Name: name}}})
+	}
+	return pods
+}
+
+func TestSchedulerProfileRunRecordsDecision(t *testing.T) {
+	recorder := decisions.NewRecorder(10)
+	withDecisionRecorder(t, recorder)
+
+	request := &types.LLMRequest{TargetModel: "llama", RequestId: "req-1"}
+	if _, err := decisionsProfile().Run(context.Background(), request, types.NewCycleState(), decisionsPods()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	records := recorder.Records(decisions.Query{})
+	if len(records) != 1 {
+		t.Fatalf("records = %+v, want one", records)
+	}
+	got := records[0]
+	if got.RequestID != "req-1" || got.Model != "llama" || got.Picker != "max/max" {
+		t.Errorf("record = %+v, want req-1 for llama picked by max/max", got)
+	}
+	if diff := cmp.Diff([]string{"ns/pod1", "ns/pod2"}, got.Candidates); diff != "" {
+		t.Errorf("candidates (-want +got): %s", diff)
+	}
+	wantScorers := []decisions.ScorerScores{
+		{Scorer: "queue/queue", Weight: 2, Scores: map[string]float64{"ns/pod1": 0.5, "ns/pod2": 0.5}},
+		{Scorer: "prefix/prefix", Weight: 4, Scores: map[string]float64{"ns/pod1": 0.25, "ns/pod2": 0.25}},
+	}
+	if diff := cmp.Diff(wantScorers, got.Scorers); diff != "" {
+		t.Errorf("scorers (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff(map[string]float64{"ns/pod1": 2, "ns/pod2": 2}, got.Weighted); diff != "" {
+		t.Errorf("weighted (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"ns/pod2"}, got.Selected); diff != "" {
+		t.Errorf("selected (-want +got): %s", diff)
+	}
+}
+
+func TestSchedulerProfileRunWithoutRecorder(t *testing.T) {
+	withDecisionRecorder(t, nil)
+
+	if record := newDecisionRecord(nil, &types.LLMRequest{RequestId: "req-1"}, decisionsPods()); record != nil {
+		t.Errorf("record = %+v, want nil without a recorder", record)
+	}
+	request := &types.LLMRequest{TargetModel: "llama", RequestId: "req-1"}
+	result, err := decisionsProfile().Run(context.Background(), request, types.NewCycleState(), decisionsPods())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.TargetPods) != 1 || result.TargetPods[0].GetPod().NamespacedName.Name != "pod2" {
+		t.Errorf("result = %+v, want pod2", result)
+	}
+}
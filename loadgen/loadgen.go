@@ -0,0 +1,430 @@
+// Package loadgen sends OpenAI completions or chat requests to an inference
+// gateway and summarizes them into LoadTest results.
+package loadgen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// PodHeader is the response header the simulator reports its pod name in
+const PodHeader = "x-inference-pod"
+
+// MaxTerminationMessage is the size the kubelet truncates a termination message to
+const MaxTerminationMessage = 4096
+
+// Config describes a load test run
+type Config struct {
+	// URL is the base URL of the gateway, without the /v1 path
+	URL string
+	// API is completions or chat
+	API string
+	// Model is sent with every request
+	Model string
+	// RequestsPerSecond is the rate requests are started at; 0 is unlimited.
+	// Not used with phases.
+	RequestsPerSecond int
+	// Concurrency is the maximum number of requests in flight
+	Concurrency int
+	// Duration is how long requests are started for. Not used with phases.
+	Duration time.Duration
+	// Phases shape the arrival rate over time and are reported separately
+	Phases []Phase
+	// Timeout bounds a single request
+	Timeout time.Duration
+	// MaxTokens is the max_tokens of every request
+	MaxTokens int
+	// PromptLength is the prompt length distribution, in words
+	PromptLength simv1alpha1.PromptLengthDistribution
+	// SharedPrefix starts a share of the prompts with a common prefix
+	SharedPrefix simv1alpha1.SharedPrefixConfig
+	// Seed seeds the prompt generator
+	Seed int64
+}
+
+// request is a request body and the index of the phase it was started in
+type request struct {
+	phase int
+	body  []byte
+}
+
+// outcome is the result of a single request
+type outcome struct {
+	phase   int
+	err     string
+	ttft    time.Duration
+	latency time.Duration
+	pod     string
+}
+
+// phaseRun records when a phase ran and how many requests it started
+type phaseRun struct {
+	phase   Phase
+	start   time.Time
+	elapsed time.Duration
+	started int64
+}
+
+// Run sends requests for cfg.Duration, or through every phase, and waits for
+// the requests in flight. It only fails when the run cannot start; failed
+// requests are counted in the results.
+func Run(ctx context.Context, cfg Config) (*simv1alpha1.LoadTestResults, error) {
+	path := "/v1/completions"
+	switch cfg.API {
+	case "completions":
+	case "chat":
+		path = "/v1/chat/completions"
+	default:
+		return nil, fmt.Errorf("unsupported api %q", cfg.API)
+	}
+	if cfg.Concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1")
+	}
+	phases := cfg.Phases
+	if len(phases) == 0 {
+		phases = []Phase{{LoadPhase: simv1alpha1.LoadPhase{
+			Name:              "constant",
+			Shape:             simv1alpha1.LoadShapeConstant,
+			Duration:          metav1.Duration{Duration: cfg.Duration},
+			RequestsPerSecond: int32(cfg.RequestsPerSecond),
+		}}}
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	// Schedules are built up front so a bad trace fails the run before any request
+	schedules := make([]schedule, len(phases))
+	for i, phase := range phases {
+		s, err := newSchedule(phase, rng)
+		if err != nil {
+			return nil, err
+		}
+		schedules[i] = s
+	}
+
+	endpoint := strings.TrimSuffix(cfg.URL, "/") + path
+	client := &http.Client{Timeout: cfg.Timeout}
+	p := &producer{cfg: cfg, prompts: newPromptGenerator(rng, cfg.PromptLength, cfg.SharedPrefix), requests: make(chan request)}
+
+	var mu sync.Mutex
+	var outcomes []outcome
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range p.requests {
+				o := send(ctx, client, endpoint, req.body)
+				o.phase = req.phase
+				mu.Lock()
+				outcomes = append(outcomes, o)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	start := time.Now()
+	runs := make([]phaseRun, 0, len(phases))
+	var err error
+	for i, phase := range phases {
+		if ctx.Err() != nil {
+			break
+		}
+		run := phaseRun{phase: phase, start: time.Now()}
+		run.started, err = p.run(ctx, i, phase.Duration.Duration, schedules[i])
+		run.elapsed = time.Since(run.start)
+		runs = append(runs, run)
+		if err != nil {
+			break
+		}
+	}
+	close(p.requests)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	results := summarize(outcomes, time.Since(start))
+	if len(cfg.Phases) > 0 {
+		results.Phases = summarizePhases(outcomes, runs)
+	}
+	return results, nil
+}
+
+// producer hands request bodies to the workers. Sending blocks while every
+// worker is busy, so no schedule pushes more than Concurrency requests in
+// flight; arrivals that fall behind are sent as soon as a worker frees up.
+type producer struct {
+	cfg      Config
+	prompts  *promptGenerator
+	requests chan request
+}
+
+// run starts the requests of one phase until it ends and returns how many it started
+func (p *producer) run(ctx context.Context, index int, duration time.Duration, arrivals schedule) (int64, error) {
+	start := time.Now()
+	end := time.NewTimer(duration)
+	defer end.Stop()
+	var started int64
+	for {
+		burst := 1
+		if arrivals != nil {
+			offset, n, ok := arrivals.next()
+			if !ok {
+				// Nothing left to send; the phase still lasts its duration
+				select {
+				case <-end.C:
+				case <-ctx.Done():
+				}
+				return started, nil
+			}
+			burst = n
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-end.C:
+					timer.Stop()
+					return started, nil
+				case <-ctx.Done():
+					timer.Stop()
+					return started, nil
+				}
+			}
+		}
+		for i := 0; i < burst; i++ {
+			body, err := requestBody(p.cfg, p.prompts)
+			if err != nil {
+				return started, err
+			}
+			select {
+			case p.requests <- request{phase: index, body: body}:
+				started++
+			case <-end.C:
+				return started, nil
+			case <-ctx.Done():
+				return started, nil
+			}
+		}
+	}
+}
+
+func requestBody(cfg Config, prompts *promptGenerator) ([]byte, error) {
+	prompt, _ := prompts.next()
+	request := map[string]interface{}{
+		"model":      cfg.Model,
+		"max_tokens": cfg.MaxTokens,
+		"stream":     true,
+	}
+	if cfg.API == "chat" {
+		request["messages"] = []map[string]string{{"role": "user", "content": prompt}}
+	} else {
+		request["prompt"] = prompt
+	}
+	return json.Marshal(request)
+}
+
+// send posts a streaming request and times the first and the last chunk
+func send(ctx context.Context, client *http.Client, endpoint string, body []byte) outcome {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return outcome{err: "request"}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return outcome{err: errorReason(err, "connection")}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return outcome{err: strconv.Itoa(resp.StatusCode)}
+	}
+
+	o := outcome{pod: resp.Header.Get(PodHeader)}
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if o.ttft == 0 && strings.HasPrefix(line, "data:") && strings.TrimSpace(strings.TrimPrefix(line, "data:")) != "[DONE]" {
+			o.ttft = time.Since(start)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return outcome{err: errorReason(err, "stream")}
+		}
+	}
+	o.latency = time.Since(start)
+	// A non-streaming response is one chunk
+	if o.ttft == 0 {
+		o.ttft = o.latency
+	}
+	return o
+}
+
+func errorReason(err error, fallback string) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+	return fallback
+}
+
+// tally accumulates the outcomes of a set of requests
+type tally struct {
+	requests, succeeded, errors int64
+	errorsByReason, routing     map[string]int64
+	ttfts, latencies            []time.Duration
+}
+
+func (t *tally) add(o outcome) {
+	t.requests++
+	if o.err != "" {
+		t.errors++
+		if t.errorsByReason == nil {
+			t.errorsByReason = map[string]int64{}
+		}
+		t.errorsByReason[o.err]++
+		return
+	}
+	t.succeeded++
+	t.ttfts = append(t.ttfts, o.ttft)
+	t.latencies = append(t.latencies, o.latency)
+	pod := o.pod
+	if pod == "" {
+		pod = "unknown"
+	}
+	if t.routing == nil {
+		t.routing = map[string]int64{}
+	}
+	t.routing[pod]++
+}
+
+// rate formats a count per second like the other rates in the results
+func rate(count int64, elapsed time.Duration) string {
+	if elapsed <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", float64(count)/elapsed.Seconds())
+}
+
+func summarize(outcomes []outcome, elapsed time.Duration) *simv1alpha1.LoadTestResults {
+	var t tally
+	for _, o := range outcomes {
+		t.add(o)
+	}
+	return &simv1alpha1.LoadTestResults{
+		Requests:         t.requests,
+		Succeeded:        t.succeeded,
+		Errors:           t.errors,
+		ErrorsByReason:   t.errorsByReason,
+		Throughput:       rate(t.succeeded, elapsed),
+		TimeToFirstToken: percentiles(t.ttfts),
+		Latency:          percentiles(t.latencies),
+		Routing:          t.routing,
+	}
+}
+
+// summarizePhases reports the requests by the phase they were started in
+func summarizePhases(outcomes []outcome, runs []phaseRun) []simv1alpha1.LoadPhaseResults {
+	tallies := make([]tally, len(runs))
+	for _, o := range outcomes {
+		tallies[o.phase].add(o)
+	}
+	results := make([]simv1alpha1.LoadPhaseResults, 0, len(runs))
+	for i, run := range runs {
+		t := tallies[i]
+		results = append(results, simv1alpha1.LoadPhaseResults{
+			Name:             run.phase.Name,
+			Shape:            run.phase.Shape,
+			StartTime:        metav1.NewTime(run.start),
+			Duration:         metav1.Duration{Duration: run.elapsed.Round(time.Millisecond)},
+			OfferedRate:      rate(run.started, run.elapsed),
+			Requests:         t.requests,
+			Succeeded:        t.succeeded,
+			Errors:           t.errors,
+			ErrorsByReason:   t.errorsByReason,
+			Throughput:       rate(t.succeeded, run.elapsed),
+			TimeToFirstToken: percentiles(t.ttfts),
+			Latency:          percentiles(t.latencies),
+			Routing:          t.routing,
+		})
+	}
+	return results
+}
+
+// TerminationMessage encodes the results to fit a termination message. When
+// they do not, per-phase details are left out in order: errors by reason and
+// time to first token, latency, routing, the phases themselves and finally
+// the top-level errors by reason and routing, which has one entry per pod.
+func TerminationMessage(results *simv1alpha1.LoadTestResults) ([]byte, error) {
+	out, err := json.Marshal(results)
+	if err != nil || len(out) <= MaxTerminationMessage {
+		return out, err
+	}
+	trimmed := results.DeepCopy()
+	trimmed.Truncated = true
+	phaseTrims := []func(*simv1alpha1.LoadPhaseResults){
+		func(p *simv1alpha1.LoadPhaseResults) { p.ErrorsByReason, p.TimeToFirstToken = nil, nil },
+		func(p *simv1alpha1.LoadPhaseResults) { p.Latency = nil },
+		func(p *simv1alpha1.LoadPhaseResults) { p.Routing = nil },
+	}
+	for _, trim := range phaseTrims {
+		if len(trimmed.Phases) == 0 {
+			break
+		}
+		for i := range trimmed.Phases {
+			trim(&trimmed.Phases[i])
+		}
+		if out, err = json.Marshal(trimmed); err != nil || len(out) <= MaxTerminationMessage {
+			return out, err
+		}
+	}
+	trims := []func(*simv1alpha1.LoadTestResults){
+		func(r *simv1alpha1.LoadTestResults) { r.Phases = nil },
+		func(r *simv1alpha1.LoadTestResults) { r.ErrorsByReason = nil },
+		func(r *simv1alpha1.LoadTestResults) { r.Routing = nil },
+	}
+	for _, trim := range trims {
+		trim(trimmed)
+		if out, err = json.Marshal(trimmed); err != nil || len(out) <= MaxTerminationMessage {
+			break
+		}
+	}
+	return out, err
+}
+
+// percentiles uses the nearest-rank method; durations are rounded to microseconds
+func percentiles(durations []time.Duration) *simv1alpha1.LatencyPercentiles {
+	if len(durations) == 0 {
+		return nil
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	rank := func(p int) metav1.Duration {
+		i := (p*len(durations)+99)/100 - 1
+		return metav1.Duration{Duration: durations[i].Round(time.Microsecond)}
+	}
+	return &simv1alpha1.LatencyPercentiles{
+		P50: rank(50),
+		P90: rank(90),
+		P99: rank(99),
+		Max: rank(100),
+	}
+}
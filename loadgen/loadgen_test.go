@@ -0,0 +1,246 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+func TestRun(t *testing.T) {
+	var calls int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["messages"] == nil || body["stream"] != true {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		// Every fourth request fails
+		n := atomic.AddInt64(&calls, 1)
+		if n%4 == 0 {
+			http.Error(w, "overloaded", http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set(PodHeader, fmt.Sprintf("decode-%d", n%2))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(2 * time.Millisecond)
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	results, err := Run(context.Background(), Config{
+		URL:               server.URL,
+		API:               "chat",
+		Model:             "random",
+		RequestsPerSecond: 200,
+		Concurrency:       4,
+		Duration:          200 * time.Millisecond,
+		Timeout:           time.Second,
+		MaxTokens:         4,
+		PromptLength:      simv1alpha1.PromptLengthDistribution{Distribution: simv1alpha1.PromptLengthFixed, Mean: 8, Min: 1, Max: 16},
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if results.Requests == 0 || results.Requests != atomic.LoadInt64(&calls) {
+		t.Fatalf("requests = %d, server saw %d", results.Requests, calls)
+	}
+	if results.Succeeded+results.Errors != results.Requests {
+		t.Errorf("succeeded %d + errors %d != requests %d", results.Succeeded, results.Errors, results.Requests)
+	}
+	if results.Errors != results.ErrorsByReason["429"] {
+		t.Errorf("errorsByReason = %v, want every error to be a 429", results.ErrorsByReason)
+	}
+	if results.Routing["decode-0"]+results.Routing["decode-1"] != results.Succeeded {
+		t.Errorf("routing = %v does not account for %d succeeded requests", results.Routing, results.Succeeded)
+	}
+	if results.TimeToFirstToken == nil || results.Latency == nil {
+		t.Fatalf("missing latency percentiles: %+v", results)
+	}
+	if results.TimeToFirstToken.P50.Duration > results.Latency.P50.Duration {
+		t.Errorf("ttft p50 %v above latency p50 %v", results.TimeToFirstToken.P50, results.Latency.P50)
+	}
+	if results.Latency.P50.Duration > results.Latency.P99.Duration || results.Latency.P99.Duration > results.Latency.Max.Duration {
+		t.Errorf("latency percentiles out of order: %+v", results.Latency)
+	}
+}
+
+func TestRunConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	results, err := Run(context.Background(), Config{
+		URL: url, API: "completions", Concurrency: 1, RequestsPerSecond: 50,
+		Duration: 50 * time.Millisecond, Timeout: time.Second,
+		PromptLength: simv1alpha1.PromptLengthDistribution{Mean: 4, Min: 1, Max: 8},
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if results.Requests == 0 || results.Errors != results.Requests || results.ErrorsByReason["connection"] != results.Requests {
+		t.Errorf("expected every request to fail to connect, got %+v", results)
+	}
+	if results.Latency != nil {
+		t.Errorf("latency = %+v, want none without succeeded requests", results.Latency)
+	}
+}
+
+func TestRunPhases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(PodHeader, "decode-0")
+		fmt.Fprint(w, "data: {\"choices\":[{\"text\":\"a\"}]}\n\ndata: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	phases := []Phase{
+		{LoadPhase: simv1alpha1.LoadPhase{Name: "warmup", Shape: simv1alpha1.LoadShapeConstant, Duration: metav1.Duration{Duration: 200 * time.Millisecond}, RequestsPerSecond: 20}},
+		{LoadPhase: simv1alpha1.LoadPhase{Name: "ramp", Shape: simv1alpha1.LoadShapeRamp, Duration: metav1.Duration{Duration: 200 * time.Millisecond}, From: 100, To: 200}},
+	}
+	results, err := Run(context.Background(), Config{
+		URL: server.URL, API: "completions", Concurrency: 4, Timeout: time.Second, MaxTokens: 4,
+		PromptLength: simv1alpha1.PromptLengthDistribution{Mean: 4, Min: 1, Max: 8},
+		Phases:       phases,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(results.Phases) != 2 || results.Phases[0].Name != "warmup" || results.Phases[1].Name != "ramp" {
+		t.Fatalf("phases = %+v, want warmup and ramp", results.Phases)
+	}
+	warmup, ramp := results.Phases[0], results.Phases[1]
+	if warmup.Requests+ramp.Requests != results.Requests {
+		t.Errorf("phase requests %d + %d != %d", warmup.Requests, ramp.Requests, results.Requests)
+	}
+	// 4 arrivals are due in the warmup and 30 in the ramp, which may not all
+	// make it through 4 workers
+	if warmup.Requests != 4 || ramp.Requests < 20 || ramp.Requests > 30 {
+		t.Errorf("requests = %d and %d, want 4 and about 30", warmup.Requests, ramp.Requests)
+	}
+	if ramp.Routing["decode-0"] != ramp.Succeeded || ramp.OfferedRate == "" || ramp.Latency == nil {
+		t.Errorf("ramp results incomplete: %+v", ramp)
+	}
+	if ramp.StartTime.Time.Before(warmup.StartTime.Time) || warmup.Duration.Duration < 200*time.Millisecond {
+		t.Errorf("phase timing: warmup %v for %v, ramp %v", warmup.StartTime, warmup.Duration, ramp.StartTime)
+	}
+}
+
+func TestTerminationMessage(t *testing.T) {
+	results := &simv1alpha1.LoadTestResults{Requests: 100, Succeeded: 100}
+	message, err := TerminationMessage(results)
+	if err != nil || strings.Contains(string(message), "truncated") {
+		t.Fatalf("message = %s, %v; want the results untouched", message, err)
+	}
+
+	latency := &simv1alpha1.LatencyPercentiles{P50: metav1.Duration{Duration: time.Millisecond}}
+	for i := 0; i < 30; i++ {
+		routing := map[string]int64{}
+		for pod := 0; pod < 8; pod++ {
+			routing[fmt.Sprintf("llm-sim-full-decode-5d8f7c9b4-%05d", pod)] = 10
+		}
+		results.Phases = append(results.Phases, simv1alpha1.LoadPhaseResults{
+			Name: fmt.Sprintf("step-%d", i), Shape: simv1alpha1.LoadShapeStep, Requests: 80, Succeeded: 80,
+			TimeToFirstToken: latency, Latency: latency, Routing: routing,
+		})
+	}
+	message, err = TerminationMessage(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(message) > MaxTerminationMessage {
+		t.Fatalf("message of %d bytes exceeds %d", len(message), MaxTerminationMessage)
+	}
+	decoded := &simv1alpha1.LoadTestResults{}
+	if err := json.Unmarshal(message, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Truncated || len(decoded.Phases) != 30 || decoded.Phases[0].Latency != nil || decoded.Phases[0].Requests != 80 {
+		t.Errorf("decoded = %+v, want the phases kept without latencies", decoded)
+	}
+	if results.Phases[0].Latency == nil {
+		t.Errorf("TerminationMessage modified its argument")
+	}
+
+	// Without phases the per-pod routing of a large fleet is left out
+	large := &simv1alpha1.LoadTestResults{Requests: 1000, Succeeded: 990, Errors: 10,
+		ErrorsByReason: map[string]int64{"503": 10}, Latency: latency, Routing: map[string]int64{}}
+	for pod := 0; pod < 200; pod++ {
+		large.Routing[fmt.Sprintf("llm-sim-full-decode-5d8f7c9b4-%05d", pod)] = 5
+	}
+	message, err = TerminationMessage(large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(message) > MaxTerminationMessage {
+		t.Fatalf("message of %d bytes exceeds %d", len(message), MaxTerminationMessage)
+	}
+	decoded = &simv1alpha1.LoadTestResults{}
+	if err := json.Unmarshal(message, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Truncated || decoded.Routing != nil || decoded.ErrorsByReason != nil ||
+		decoded.Succeeded != 990 || decoded.Latency == nil {
+		t.Errorf("decoded = %+v, want the totals and latency kept without routing", decoded)
+	}
+	if len(large.Routing) != 200 {
+		t.Errorf("TerminationMessage modified its argument")
+	}
+}
+
+func TestPromptGenerator(t *testing.T) {
+	length := simv1alpha1.PromptLengthDistribution{Distribution: simv1alpha1.PromptLengthNormal, Mean: 20, Min: 10, Max: 30, StdDev: 8}
+	g := newPromptGenerator(rand.New(rand.NewSource(1)), length, simv1alpha1.SharedPrefixConfig{Percent: 50, Length: 12, Groups: 2})
+
+	prefixes := map[string]bool{}
+	shared := 0
+	for i := 0; i < 1000; i++ {
+		prompt, prefixed := g.next()
+		words := strings.Fields(prompt)
+		if len(words) < 10 || len(words) > 30 {
+			t.Fatalf("prompt of %d words outside [10, 30]", len(words))
+		}
+		if prefixed {
+			if len(words) < 12 {
+				t.Fatalf("prefixed prompt of %d words shorter than the prefix", len(words))
+			}
+			shared++
+			prefixes[strings.Join(words[:12], " ")] = true
+		}
+	}
+	if shared < 400 || shared > 600 {
+		t.Errorf("%d of 1000 prompts share a prefix, want about 500", shared)
+	}
+	if len(prefixes) != 2 {
+		t.Errorf("%d distinct prefixes, want 2", len(prefixes))
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	p := percentiles(durations)
+	want := []time.Duration{50 * time.Millisecond, 90 * time.Millisecond, 99 * time.Millisecond, 100 * time.Millisecond}
+	for i, got := range []time.Duration{p.P50.Duration, p.P90.Duration, p.P99.Duration, p.Max.Duration} {
+		if got != want[i] {
+			t.Errorf("percentile %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
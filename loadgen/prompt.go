@@ -0,0 +1,94 @@
+package loadgen
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// vocabulary the prompts are drawn from; every word counts as one token
+var vocabulary = strings.Fields(`
+	the of and to in is was for on that with as by at from it an be this which
+	or are have had not but all were when there can more other their time into
+	only some could them see these than first been who its now people my made
+	over did down way find use may water long little very after words called
+	just where most know get through back much before go good new write our
+	used me man too any day same right look think also around another came
+	come work three word must because does part even place well such here take
+	why help put different away again off went old number great tell men say
+	small every found still between name should home big give air line set own
+	under read last never us left end along while might next sound below saw
+	something thought both few those always show large often together asked
+	house world going want school important until form food keep children feet
+	land side without boy once animal life enough took four head above kind
+	began almost live page got earth need far hand high year mother light
+	country father let night picture being study second soon story since white
+	ever paper hard near sentence better best across during today however sure
+	knew try told young sun thing whole hear example heard several change answer
+	room sea against top turned learn point city play toward five himself usually
+	money seen car morning body upon family later turn move face door cut done
+	group true leave color red friend pretty eat front feel fact hand week eye
+`)
+
+// promptGenerator draws prompts of the configured length, a share of them
+// starting with one of the shared prefixes. It is not safe for concurrent use.
+type promptGenerator struct {
+	rng      *rand.Rand
+	length   simv1alpha1.PromptLengthDistribution
+	percent  int
+	prefixes [][]string
+}
+
+func newPromptGenerator(rng *rand.Rand, length simv1alpha1.PromptLengthDistribution, prefix simv1alpha1.SharedPrefixConfig) *promptGenerator {
+	g := &promptGenerator{rng: rng, length: length, percent: int(prefix.Percent)}
+	if g.percent > 0 {
+		for i := 0; i < int(prefix.Groups); i++ {
+			g.prefixes = append(g.prefixes, g.words(int(prefix.Length)))
+		}
+	}
+	return g
+}
+
+// next returns a prompt and whether it starts with a shared prefix
+func (g *promptGenerator) next() (string, bool) {
+	n := g.drawLength()
+	if g.percent == 0 || g.rng.Intn(100) >= g.percent {
+		return strings.Join(g.words(n), " "), false
+	}
+	prefix := g.prefixes[g.rng.Intn(len(g.prefixes))]
+	// Prompts shorter than the prefix are extended to it
+	if n < len(prefix) {
+		n = len(prefix)
+	}
+	words := append(append([]string{}, prefix...), g.words(n-len(prefix))...)
+	return strings.Join(words, " "), true
+}
+
+func (g *promptGenerator) drawLength() int {
+	l := g.length
+	switch l.Distribution {
+	case simv1alpha1.PromptLengthUniform:
+		return int(l.Min) + g.rng.Intn(int(l.Max-l.Min)+1)
+	case simv1alpha1.PromptLengthNormal:
+		n := int(math.Round(float64(l.Mean) + g.rng.NormFloat64()*float64(l.StdDev)))
+		if n < int(l.Min) {
+			n = int(l.Min)
+		}
+		if n > int(l.Max) {
+			n = int(l.Max)
+		}
+		return n
+	default:
+		return int(l.Mean)
+	}
+}
+
+func (g *promptGenerator) words(n int) []string {
+	words := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		words = append(words, vocabulary[g.rng.Intn(len(vocabulary))])
+	}
+	return words
+}
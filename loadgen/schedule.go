@@ -0,0 +1,199 @@
+package loadgen
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// Phase is a LoadTest phase as passed to the load generator; the trace
+// ConfigMap key is mounted at TraceFile
+type Phase struct {
+	simv1alpha1.LoadPhase `json:",inline"`
+	// TraceFile holds the arrival offsets of a Trace phase
+	TraceFile string `json:"traceFile,omitempty"`
+}
+
+// schedule yields the arrivals of a phase as offsets from its start
+type schedule interface {
+	// next returns the offset of the next arrival and the number of requests
+	// it starts; false once the phase has no arrivals left
+	next() (time.Duration, int, bool)
+}
+
+// newSchedule returns the arrivals of a phase; nil sends as fast as the
+// concurrency allows
+func newSchedule(phase Phase, rng *rand.Rand) (schedule, error) {
+	duration := phase.Duration.Duration.Seconds()
+	if duration <= 0 {
+		return nil, fmt.Errorf("phase %s: duration must be positive", phase.Name)
+	}
+	switch phase.Shape {
+	case simv1alpha1.LoadShapeConstant, "":
+		if phase.RequestsPerSecond == 0 {
+			return nil, nil
+		}
+		rate := float64(phase.RequestsPerSecond)
+		return &rateSchedule{duration: duration, count: func(t float64) float64 { return rate * t }}, nil
+
+	case simv1alpha1.LoadShapeRamp:
+		from, to := float64(phase.From), float64(phase.To)
+		return &rateSchedule{duration: duration, count: func(t float64) float64 {
+			return from*t + (to-from)*t*t/(2*duration)
+		}}, nil
+
+	case simv1alpha1.LoadShapeStep:
+		steps := int(phase.Steps)
+		if steps < 2 {
+			return nil, fmt.Errorf("phase %s: steps must be at least 2", phase.Name)
+		}
+		from, to := float64(phase.From), float64(phase.To)
+		width := duration / float64(steps)
+		return &rateSchedule{duration: duration, count: func(t float64) float64 {
+			n := 0.0
+			for i := 0; i < steps && t > float64(i)*width; i++ {
+				rate := from + (to-from)*float64(i)/float64(steps-1)
+				n += rate * math.Min(t-float64(i)*width, width)
+			}
+			return n
+		}}, nil
+
+	case simv1alpha1.LoadShapeSinusoidal:
+		// The rate starts at its trough, like traffic at night
+		base, amplitude := float64(phase.RequestsPerSecond), float64(phase.Amplitude)
+		period := duration
+		if phase.Period != nil && phase.Period.Duration > 0 {
+			period = phase.Period.Duration.Seconds()
+		}
+		return &rateSchedule{duration: duration, count: func(t float64) float64 {
+			return base*t - amplitude*period/(2*math.Pi)*math.Sin(2*math.Pi*t/period)
+		}}, nil
+
+	case simv1alpha1.LoadShapePoisson:
+		if phase.RequestsPerSecond < 1 {
+			return nil, fmt.Errorf("phase %s: requestsPerSecond must be at least 1", phase.Name)
+		}
+		burst := int(phase.BurstSize)
+		if burst < 1 {
+			burst = 1
+		}
+		return &poissonSchedule{rng: rng, rate: float64(phase.RequestsPerSecond), burst: burst, duration: duration}, nil
+
+	case simv1alpha1.LoadShapeTrace:
+		offsets, err := readTrace(phase.TraceFile)
+		if err != nil {
+			return nil, fmt.Errorf("phase %s: %w", phase.Name, err)
+		}
+		return &traceSchedule{offsets: offsets, duration: phase.Duration.Duration}, nil
+	}
+	return nil, fmt.Errorf("phase %s: unsupported shape %q", phase.Name, phase.Shape)
+}
+
+// rateSchedule places arrival k, counting from 0, where the expected number of
+// arrivals, the integral of the rate, reaches k; the first starts the phase
+type rateSchedule struct {
+	// count is the number of arrivals due t seconds into the phase; it must not decrease
+	count    func(t float64) float64
+	duration float64
+	n        int
+	last     float64
+}
+
+func (s *rateSchedule) next() (time.Duration, int, bool) {
+	k := float64(s.n)
+	s.n++
+	if s.count(s.duration) <= k {
+		return 0, 0, false
+	}
+	if s.count(s.last) >= k {
+		return time.Duration(s.last * float64(time.Second)), 1, true
+	}
+	lo, hi := s.last, s.duration
+	for i := 0; i < 64 && hi-lo > 1e-7; i++ {
+		mid := (lo + hi) / 2
+		if s.count(mid) >= k {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	s.last = hi
+	return time.Duration(hi * float64(time.Second)), 1, true
+}
+
+// poissonSchedule draws exponential gaps between bursts of requests
+type poissonSchedule struct {
+	rng      *rand.Rand
+	rate     float64
+	burst    int
+	duration float64
+	t        float64
+}
+
+func (s *poissonSchedule) next() (time.Duration, int, bool) {
+	s.t += s.rng.ExpFloat64() / s.rate
+	if s.t >= s.duration {
+		return 0, 0, false
+	}
+	return time.Duration(s.t * float64(time.Second)), s.burst, true
+}
+
+// traceSchedule replays recorded arrival offsets
+type traceSchedule struct {
+	offsets  []time.Duration
+	duration time.Duration
+	i        int
+}
+
+func (s *traceSchedule) next() (time.Duration, int, bool) {
+	if s.i >= len(s.offsets) || s.offsets[s.i] >= s.duration {
+		return 0, 0, false
+	}
+	// Arrivals recorded at the same offset start together
+	offset, n := s.offsets[s.i], 0
+	for s.i < len(s.offsets) && s.offsets[s.i] == offset {
+		s.i++
+		n++
+	}
+	return offset, n, true
+}
+
+// readTrace parses one arrival offset in seconds per line; blank lines and
+// lines starting with # are skipped
+func readTrace(path string) ([]time.Duration, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no trace file")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var offsets []time.Duration
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(text, 64)
+		if err != nil || seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return nil, fmt.Errorf("%s:%d: %q is not an offset in seconds", path, line, text)
+		}
+		offsets = append(offsets, time.Duration(seconds*float64(time.Second)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, nil
+}
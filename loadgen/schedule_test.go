@@ -0,0 +1,161 @@
+package loadgen
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	simv1alpha1 "github.com/llm-d/llm-d-scheduler-sim-operator/api/v1alpha1"
+)
+
+// arrivals drains a schedule into offsets, one per request
+func arrivals(t *testing.T, phase Phase) []time.Duration {
+	t.Helper()
+	s, err := newSchedule(phase, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	var offsets []time.Duration
+	for {
+		offset, n, ok := s.next()
+		if !ok {
+			return offsets
+		}
+		for i := 0; i < n; i++ {
+			offsets = append(offsets, offset)
+		}
+	}
+}
+
+// countBefore counts the offsets before d
+func countBefore(offsets []time.Duration, d time.Duration) int {
+	n := 0
+	for _, offset := range offsets {
+		if offset < d {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRateSchedules(t *testing.T) {
+	second := metav1.Duration{Duration: time.Second}
+	tests := []struct {
+		name  string
+		phase simv1alpha1.LoadPhase
+		total int
+		// firstHalf is the number of arrivals in the first half of the phase
+		firstHalf int
+	}{
+		{
+			name:      "constant",
+			phase:     simv1alpha1.LoadPhase{Shape: simv1alpha1.LoadShapeConstant, Duration: second, RequestsPerSecond: 10},
+			total:     10,
+			firstHalf: 5,
+		},
+		{
+			name:      "ramp up",
+			phase:     simv1alpha1.LoadPhase{Shape: simv1alpha1.LoadShapeRamp, Duration: second, To: 20},
+			total:     10,
+			firstHalf: 3,
+		},
+		{
+			name:      "step down",
+			phase:     simv1alpha1.LoadPhase{Shape: simv1alpha1.LoadShapeStep, Duration: metav1.Duration{Duration: 2 * time.Second}, From: 15, To: 5, Steps: 2},
+			total:     20,
+			firstHalf: 15,
+		},
+		{
+			name: "sinusoidal from the trough",
+			phase: simv1alpha1.LoadPhase{Shape: simv1alpha1.LoadShapeSinusoidal, Duration: second, RequestsPerSecond: 10, Amplitude: 10,
+				Period: &metav1.Duration{Duration: 2 * time.Second}},
+			total:     10,
+			firstHalf: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offsets := arrivals(t, Phase{LoadPhase: tt.phase})
+			if len(offsets) != tt.total {
+				t.Fatalf("%d arrivals, want %d: %v", len(offsets), tt.total, offsets)
+			}
+			half := tt.phase.Duration.Duration / 2
+			if got := countBefore(offsets, half); got != tt.firstHalf {
+				t.Errorf("%d arrivals in the first half, want %d: %v", got, tt.firstHalf, offsets)
+			}
+			for i := 1; i < len(offsets); i++ {
+				if offsets[i] < offsets[i-1] || offsets[i] >= tt.phase.Duration.Duration {
+					t.Fatalf("arrival %d at %v out of order or after the phase", i, offsets[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUnlimitedSchedule(t *testing.T) {
+	s, err := newSchedule(Phase{LoadPhase: simv1alpha1.LoadPhase{Name: "flat-out", Duration: metav1.Duration{Duration: time.Second}}}, nil)
+	if err != nil || s != nil {
+		t.Errorf("schedule = %v, %v; want none for a constant phase without a rate", s, err)
+	}
+}
+
+func TestPoissonSchedule(t *testing.T) {
+	phase := simv1alpha1.LoadPhase{
+		Name: "bursts", Shape: simv1alpha1.LoadShapePoisson, Duration: metav1.Duration{Duration: 10 * time.Second},
+		RequestsPerSecond: 100, BurstSize: 3,
+	}
+	s, err := newSchedule(Phase{LoadPhase: phase}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	bursts := 0
+	var last time.Duration
+	for {
+		offset, n, ok := s.next()
+		if !ok {
+			break
+		}
+		if n != 3 || offset < last || offset >= phase.Duration.Duration {
+			t.Fatalf("burst of %d at %v after %v", n, offset, last)
+		}
+		last = offset
+		bursts++
+	}
+	// 1000 bursts expected; a Poisson count is within 5 standard deviations
+	if bursts < 850 || bursts > 1150 {
+		t.Errorf("%d bursts, want about 1000", bursts)
+	}
+}
+
+func TestTraceSchedule(t *testing.T) {
+	dir := t.TempDir()
+	trace := filepath.Join(dir, "trace")
+	content := strings.Join([]string{"# recorded 2026-10-01", "0.5", "0", "", "0.5", "1.25", "7"}, "\n")
+	if err := os.WriteFile(trace, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	phase := simv1alpha1.LoadPhase{Name: "replay", Shape: simv1alpha1.LoadShapeTrace, Duration: metav1.Duration{Duration: 5 * time.Second}}
+	offsets := arrivals(t, Phase{LoadPhase: phase, TraceFile: trace})
+	want := []time.Duration{0, 500 * time.Millisecond, 500 * time.Millisecond, 1250 * time.Millisecond}
+	if len(offsets) != len(want) {
+		t.Fatalf("offsets = %v, want %v", offsets, want)
+	}
+	for i := range want {
+		if offsets[i] != want[i] {
+			t.Errorf("offset %d = %v, want %v", i, offsets[i], want[i])
+		}
+	}
+
+	if err := os.WriteFile(trace, []byte("0.5\nsoon\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newSchedule(Phase{LoadPhase: phase, TraceFile: trace}, nil); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("error = %v, want the bad line reported", err)
+	}
+}
@@ -13,6 +13,20 @@ if [ -z "$EPP_POD" ]; then
 fi
 
 echo "Using EPP Pod: $EPP_POD"
+
+# Scoring decisions are read from the EPP decisions endpoint (SCHEDULING_DECISIONS_ADDR=:9005)
+DECISIONS_PORT=9005
+kubectl port-forward -n llm-d-inference-scheduler pod/$EPP_POD $DECISIONS_PORT:9005 > /dev/null 2>&1 &
+PORT_FORWARD_PID=$!
+trap 'kill $PORT_FORWARD_PID 2>/dev/null' EXIT
+sleep 2
+DECISIONS="http://127.0.0.1:$DECISIONS_PORT/decisions"
+
+if ! curl -sf "$DECISIONS?limit=1" > /dev/null; then
+    echo "ERROR: EPP decisions endpoint not reachable, set SCHEDULING_DECISIONS_ADDR=:9005 in spec.epp.env"
+    exit 1
+fi
+
 echo "Running $NUM_TESTS test requests with same prompt"
 echo "=================================================="
 echo ""
@@ -35,17 +49,14 @@ for i in $(seq 1 $NUM_TESTS); do
     
     sleep 2
     
-    # Get scoring from EPP logs
+    # Get scoring and routing decision of the latest request
+    DECISION=$(curl -s "$DECISIONS?limit=1")
     echo "Scoring:"
-    kubectl logs -n llm-d-inference-scheduler $EPP_POD --tail=20 | \
-      grep "prefix-cache-scorer\[" | tail -1 | \
-      sed 's/.*prefix-cache-scorer\[\([^]]*\)\].*/  \1/'
+    echo "$DECISION" | \
+      jq -r '.[0].scorers[] | select(.scorer | startswith("prefix-cache-scorer")) | .scores | to_entries[] | "  \(.key): \(.value)"'
     
-    # Get routing decision
     echo "Selected pod:"
-    kubectl logs -n llm-d-inference-scheduler $EPP_POD --tail=10 | \
-      grep "Scoring decision" | tail -1 | \
-      sed 's/.*"selected":\["\([^"]*\)".*/  \1/' | \
+    echo "$DECISION" | jq -r '.[0].selected[0]' | \
       sed 's/.*-\([^-]*\)-rank-0/  \1/'
     
     echo ""
@@ -54,8 +65,9 @@ done
 echo "=================================================="
 echo "=== Analysis ==="
 echo ""
-echo "Full prefix-cache-scorer logs:"
-kubectl logs -n llm-d-inference-scheduler $EPP_POD | grep "prefix-cache-scorer\[" | nl
+echo "Full prefix-cache-scorer scores:"
+curl -s "$DECISIONS?limit=$NUM_TESTS" | \
+  jq -c '.[] | .scorers[] | select(.scorer | startswith("prefix-cache-scorer")) | .scores' | nl
 
 echo ""
 echo "Expected behavior:"
@@ -63,8 +75,7 @@ echo "  Request 1: Both pods score 0.000 (cold cache, random selection)"
 echo "  Request 2-8: Selected pod from request 1 scores 1.000, other scores 0.000"
 echo ""
 echo "Actual pod selections:"
-kubectl logs -n llm-d-inference-scheduler $EPP_POD | \
-  grep "Scoring decision" | \
-  sed 's/.*"selected":\["\([^"]*\)".*/\1/' | \
+curl -s "$DECISIONS?limit=$NUM_TESTS" | \
+  jq -r '.[].selected[0]' | \
   sed 's/.*-\([^-]*\)-rank-0/\1/' | \
   nl
@@ -48,8 +48,8 @@ echo "Step 6: Watching deployment status..."
 echo "Run these commands to monitor:"
 echo "  kubectl get pods -n ${NS_SIM} -w"
 echo "  kubectl get configmap -n ${NS_SIM}"
-echo "  kubectl describe deployment gaie-sim-epp -n ${NS_SIM}"
-echo "  kubectl describe deployment infra-sim-inference-gateway -n ${NS_SIM}"
-echo "  kubectl logs -f deployment/gaie-sim-epp -n ${NS_SIM}"
+echo "  kubectl describe deployment llm-sim-full-epp -n ${NS_SIM}"
+echo "  kubectl describe deployment llm-sim-full-inference-gateway -n ${NS_SIM}"
+echo "  kubectl logs -f deployment/llm-sim-full-epp -n ${NS_SIM}"
 echo ""
 echo "To stop the operator: kill ${OPERATOR_PID}"
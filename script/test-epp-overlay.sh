@@ -0,0 +1,26 @@
+#!/bin/bash
+# Runs the tests of the instrumented EPP overlay. The overlay under
+# llm-d-inference-scheduler/_deps only holds the patched files, so it is copied
+# over the gateway-api-inference-extension release it patches and tested there.
+set -euo pipefail
+
+GIE_VERSION=${GIE_VERSION:-v1.3.1}
+ROOT=$(cd "$(dirname "$0")/.." && pwd)
+OVERLAY="$ROOT/llm-d-inference-scheduler/_deps/gateway-api-inference-extension"
+
+WORK=$(mktemp -d)
+trap 'rm -rf "$WORK"' EXIT
+
+SRC=$(cd "$WORK" && go mod download -json "sigs.k8s.io/gateway-api-inference-extension@${GIE_VERSION}" |
+  sed -n 's/^[[:space:]]*"Dir": "\(.*\)",$/\1/p')
+if [ -z "$SRC" ]; then
+  echo "ERROR: could not download gateway-api-inference-extension ${GIE_VERSION}"
+  exit 1
+fi
+
+cp -R "$SRC" "$WORK/gateway-api-inference-extension"
+chmod -R u+w "$WORK/gateway-api-inference-extension"
+cp -R "$OVERLAY/." "$WORK/gateway-api-inference-extension/"
+
+cd "$WORK/gateway-api-inference-extension"
+go test -race ./pkg/epp/handlers/... ./pkg/epp/scheduling/framework/...